@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/grokify/spectrum/openapi3/openapi3bundle"
+	flags "github.com/jessevdk/go-flags"
+)
+
+type Options struct {
+	InputFile string `short:"i" long:"input" description:"Input OpenAPI 3 root filepath" required:"true"`
+	Output    string `short:"o" long:"output" description:"Output filepath, YAML for .yaml or .yml, or directory with --split" required:"true"`
+	Split     bool   `short:"s" long:"split" description:"Split the input spec into path and component files"`
+}
+
+func main() {
+	opts := Options{}
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	infile := strings.TrimSpace(opts.InputFile)
+	output := strings.TrimSpace(opts.Output)
+
+	if opts.Split {
+		files, err := openapi3bundle.SplitFile(infile, output, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("WROTE [%d] files to [%v]\n", len(files), output)
+	} else {
+		bundle, err := openapi3bundle.BundleFile(infile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for src, name := range bundle.Renamed {
+			fmt.Printf("RENAMED [%s] to [%s]\n", src, name)
+		}
+		err = bundle.WriteFile(output, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("WROTE [%v]\n", output)
+	}
+
+	fmt.Println("DONE")
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3diff"
+	flags "github.com/jessevdk/go-flags"
+)
+
+type Options struct {
+	SpecFileOld string `short:"o" long:"old" description:"Old OAS Spec File" required:"true"`
+	SpecFileNew string `short:"n" long:"new" description:"New OAS Spec File" required:"true"`
+	Format      string `short:"f" long:"format" description:"Output format: text, json or markdown" default:"text"`
+}
+
+// main exits with status 1 when breaking changes are found so it can be
+// used to gate releases.
+func main() {
+	var opts Options
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(2)
+	}
+
+	specOld, err := openapi3.ReadFile(opts.SpecFileOld, false)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(3)
+	}
+	specNew, err := openapi3.ReadFile(opts.SpecFileNew, false)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(3)
+	}
+
+	report := openapi3diff.Diff(specOld, specNew)
+	if err := report.Write(os.Stdout, opts.Format); err != nil {
+		slog.Error(err.Error())
+		os.Exit(4)
+	}
+	if report.HasBreaking() {
+		os.Exit(1)
+	}
+}
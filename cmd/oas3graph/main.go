@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/grokify/spectrum/openapi3"
+	flags "github.com/jessevdk/go-flags"
+)
+
+type Options struct {
+	InputFile string `short:"i" long:"input" description:"Input OpenAPI 3 filepath" required:"true"`
+	Format    string `short:"f" long:"format" description:"Output format, dot or mermaid" default:"dot"`
+	Output    string `short:"o" long:"output" description:"Output filepath, stdout if not set"`
+}
+
+func main() {
+	opts := Options{}
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	spec, err := openapi3.ReadFile(strings.TrimSpace(opts.InputFile), false)
+	if err != nil {
+		log.Fatal(err)
+	}
+	graph, err := (&openapi3.SpecMore{Spec: spec}).ComponentGraph()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var out string
+	switch strings.ToLower(strings.TrimSpace(opts.Format)) {
+	case "dot":
+		out = graph.DOT()
+	case "mermaid":
+		out = graph.Mermaid()
+	default:
+		log.Fatal(fmt.Errorf("format not supported [%s]", opts.Format))
+	}
+
+	output := strings.TrimSpace(opts.Output)
+	if output == "" {
+		fmt.Print(out)
+		return
+	}
+	err = os.WriteFile(output, []byte(out), 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("WROTE [%v]\n", output)
+}
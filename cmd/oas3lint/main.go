@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"regexp"
 
 	"github.com/grokify/mogo/fmt/fmtutil"
@@ -13,48 +17,128 @@ import (
 )
 
 type Options struct {
-	PolicyFile    string `short:"p" long:"policyfile" description:"Policy File" required:"true"`
+	PolicyFile    string `short:"p" long:"policyfile" description:"Policy File in JSON or YAML format"`
+	SpectralFile  string `short:"r" long:"spectral" description:"Spectral ruleset file to import, e.g. .spectral.yaml"`
 	InputFileOAS3 string `short:"i" long:"inputspec" description:"Input OAS Spec File or Dir" required:"false"`
 	Severity      string `short:"s" long:"severity" description:"Severity level"`
+	Format        string `short:"f" long:"format" description:"Output format: json, sarif, junit or checkstyle" default:"json"`
+	Fix           bool   `long:"fix" description:"Fix violations for rules that support it and write corrected spec files"`
+	Baseline      string `short:"b" long:"baseline" description:"Baseline file of existing violations to exclude"`
+	BaselineWrite string `long:"baselinewrite" description:"Write current violations to baseline file"`
+	Workers       int    `short:"w" long:"workers" description:"Validate spec files concurrently with this many workers, 0 for sequential"`
 }
 
 func main() {
 	var opts Options
 	_, err := flags.Parse(&opts)
 	logutil.FatalErr(err)
-	fmtutil.MustPrintJSON(opts)
+	if len(opts.PolicyFile) == 0 && len(opts.SpectralFile) == 0 {
+		logutil.FatalErr(errors.New("a policy file or spectral ruleset file is required"))
+	}
+
+	verbose := opts.Format == lintutil.FormatJSON
+	if verbose {
+		fmtutil.MustPrintJSON(opts)
+	}
 
-	vsets, err := ValidateSpecFiles(opts.InputFileOAS3, opts.PolicyFile, opts.Severity)
+	if opts.Fix {
+		fixes, err := FixSpecFiles(opts.InputFileOAS3, opts.PolicyFile, opts.SpectralFile, opts.Severity)
+		logutil.FatalErr(err)
+		if verbose {
+			fmtutil.MustPrintJSON(lintutil.FixesByRule(fixes))
+		}
+	}
+
+	vsets, results, err := ValidateSpecFiles(opts.InputFileOAS3, opts.PolicyFile, opts.SpectralFile, opts.Severity, opts.Workers, verbose)
 	logutil.FatalErr(err)
 
-	fmtutil.MustPrintJSON(vsets.LocationsByRule())
-	fmtutil.MustPrintJSON(vsets.CountsByRule())
+	if len(opts.BaselineWrite) > 0 {
+		logutil.FatalErr(lintutil.NewBaseline(vsets).WriteFile(opts.BaselineWrite, 0600))
+	}
+	if len(opts.Baseline) > 0 {
+		bl, err := lintutil.ReadBaselineFile(opts.Baseline)
+		logutil.FatalErr(err)
+		var baselined uint
+		vsets, baselined = bl.Filter(vsets)
+		if verbose {
+			fmtutil.MustPrintJSON(map[string]uint{"BaselinedCount": baselined})
+		}
+	}
 
-	fmt.Println("DONE")
+	if verbose {
+		fmtutil.MustPrintJSON(vsets.LocationsByRule())
+		fmtutil.MustPrintJSON(vsets.CountsByRule())
+		if vsets.SuppressedCount() > 0 {
+			fmtutil.MustPrintJSON(map[string]map[string]uint{
+				"SuppressedCountsByRule": vsets.SuppressedCountsByRule()})
+		}
+		fmt.Println("DONE")
+	} else {
+		formatter, err := lintutil.NewFormatter(opts.Format)
+		logutil.FatalErr(err)
+		logutil.FatalErr(formatter.Format(os.Stdout, vsets))
+	}
+	// Files that could not be validated are reported after the results
+	// for the files that could.
+	logutil.FatalErr(results.Errors())
 }
 
-func ValidateSpecFiles(specFileOrDir string, policyfile, sev string) (*lintutil.PolicyViolationsSets, error) {
+// ValidateSpecFiles validates files sequentially when `workers` is 0. Otherwise
+// files are validated concurrently and can be cancelled with an interrupt.
+func ValidateSpecFiles(specFileOrDir string, policyfile, spectralfile, sev string, workers int, verbose bool) (*lintutil.PolicyViolationsSets, openapi3lint.FileResults, error) {
 	files, err := filesFromFileOrDir(specFileOrDir)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	polCfg, err := openapi3lint.NewPolicyConfigFile(policyfile)
+	pol, err := loadPolicy(policyfile, spectralfile, verbose)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	//polCfg.AddRuleCollection(extensions.NewRuleCollectionExtensions())
-	//logutil.FatalErr(fmtutil.PrintJSON(polCfg))
-	//logutil.FatalErr(fmtutil.PrintJSON(polCfg.RuleNames()))
+	if verbose {
+		fmtutil.MustPrintJSON(pol)
+		fmtutil.MustPrintJSON(pol.RuleNames())
+		fmtutil.MustPrintJSON(pol.RuleSettings())
+	}
+
+	if workers == 0 {
+		vsets, err := pol.ValidateSpecFiles(sev, files)
+		return vsets, nil, err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return pol.ValidateSpecFilesConcurrent(ctx, sev, files, workers)
+}
 
-	pol, err := polCfg.Policy()
+func FixSpecFiles(specFileOrDir string, policyfile, spectralfile, sev string) ([]lintutil.PolicyFix, error) {
+	files, err := filesFromFileOrDir(specFileOrDir)
 	if err != nil {
 		return nil, err
 	}
-	fmtutil.MustPrintJSON(pol)
-	fmtutil.MustPrintJSON(pol.RuleNames())
+	pol, err := loadPolicy(policyfile, spectralfile, false)
+	if err != nil {
+		return nil, err
+	}
+	return pol.FixSpecFiles(sev, files)
+}
 
-	return pol.ValidateSpecFiles(sev, files)
+// loadPolicy builds the policy from the policy file and adds the rules
+// of the optional Spectral ruleset, reporting rules that were not imported.
+func loadPolicy(policyfile, spectralfile string, verbose bool) (openapi3lint.Policy, error) {
+	pol, err := openapi3lint.NewPolicyWithConfig(policyfile)
+	if err != nil || len(spectralfile) == 0 {
+		return pol, err
+	}
+	rpt, err := pol.AddSpectralRulesetFile(spectralfile)
+	if err != nil {
+		return pol, err
+	}
+	if verbose {
+		fmtutil.MustPrintJSON(rpt)
+	} else if rpt.HasUnsupported() {
+		err = rpt.WriteText(os.Stderr)
+	}
+	return pol, err
 }
 
 func filesFromFileOrDir(filename string) ([]string, error) {
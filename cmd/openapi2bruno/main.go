@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/grokify/spectrum/openapi3/openapi3bruno"
+	"github.com/grokify/spectrum/openapi3/openapi3postman2"
+	flags "github.com/jessevdk/go-flags"
+)
+
+type Options struct {
+	OAS3File   string `short:"i" long:"input" description:"Input OpenAPI 3 filepath" required:"true"`
+	OutputDir  string `short:"o" long:"output" description:"Output Bruno collection directory" required:"true"`
+	ConfigFile string `short:"c" long:"config" description:"Postman conversion configuration filepath"`
+}
+
+func main() {
+	opts := Options{}
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := openapi3postman2.Configuration{}
+	if cfgfile := strings.TrimSpace(opts.ConfigFile); len(cfgfile) > 0 {
+		cfg, err = openapi3postman2.ConfigurationReadFile(cfgfile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	col, err := openapi3bruno.ConvertFile(cfg, strings.TrimSpace(opts.OAS3File))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outdir := strings.TrimSpace(opts.OutputDir)
+	err = col.WriteDir(outdir, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("WROTE [%v]\n", outdir)
+
+	fmt.Println("DONE")
+}
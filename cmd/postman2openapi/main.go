@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/postman2/postman2openapi3"
+	flags "github.com/jessevdk/go-flags"
+)
+
+type Options struct {
+	PostmanFile string `short:"i" long:"input" description:"Input Postman 2 Collection filepath" required:"true"`
+	OAS3File    string `short:"o" long:"output" description:"Output OpenAPI 3 filepath, YAML for .yaml or .yml" required:"true"`
+	APIVersion  string `short:"v" long:"version" description:"API version for info.version"`
+	ServerURL   string `short:"s" long:"server" description:"Server URL overriding request URL hosts"`
+}
+
+func main() {
+	opts := Options{}
+	_, err := flags.Parse(&opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	spec, err := postman2openapi3.ConvertFile(postman2openapi3.Configuration{
+		APIVersion: opts.APIVersion,
+		ServerURL:  opts.ServerURL,
+	}, strings.TrimSpace(opts.PostmanFile))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sm := openapi3.SpecMore{Spec: spec}
+	outfile := strings.TrimSpace(opts.OAS3File)
+	lc := strings.ToLower(outfile)
+	if strings.HasSuffix(lc, ".yaml") || strings.HasSuffix(lc, ".yml") {
+		err = sm.WriteFileYAML(outfile, 0644)
+	} else {
+		err = sm.WriteFileJSON(outfile, 0644, "", "  ")
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("WROTE [%v]\n", outfile)
+
+	fmt.Println("DONE")
+}
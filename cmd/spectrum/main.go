@@ -19,6 +19,8 @@ type Options struct {
 	PostmanBase string `short:"B" long:"basePostmanFile" description:"Basic Postman File"`
 	Postman     string `short:"P" long:"postmanFile" description:"Output Postman File"`
 	XLSXFile    string `short:"X" long:"xlsxFile" description:"Output XLSX File"`
+	EnvDir      string `short:"E" long:"environmentDir" description:"Output directory for Postman environment files, one per server"`
+	Sync        bool   `short:"S" long:"sync" description:"Sync the existing Postman file, or the base file if set, keeping manual edits"`
 }
 
 func (opts *Options) TrimSpace() {
@@ -26,6 +28,7 @@ func (opts *Options) TrimSpace() {
 	opts.PostmanBase = strings.TrimSpace(opts.PostmanBase)
 	opts.Postman = strings.TrimSpace(opts.Postman)
 	opts.OpenAPIFile = strings.TrimSpace(opts.OpenAPIFile)
+	opts.EnvDir = strings.TrimSpace(opts.EnvDir)
 }
 
 func main() {
@@ -40,15 +43,41 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if len(opts.Postman) > 0 {
-		cfg3 := openapi3postman2.Configuration{}
+	cfg3 := openapi3postman2.Configuration{}
+	if len(opts.Config) > 0 {
+		cfg3, err = openapi3postman2.ConfigurationReadFile(opts.Config)
+		if err != nil {
+			log.Fatal(errorsutil.Wrap(err, "openapi3postman2.ConfigurationReadFile"))
+		}
+	}
+	if len(opts.EnvDir) > 0 {
+		// Collection URLs reference the base URL variable set by each
+		// environment.
+		cfg3 = cfg3.WithDefaultBaseURLVariable()
+	}
 
-		if len(opts.Config) > 0 {
-			cfg3, err = openapi3postman2.ConfigurationReadFile(opts.Config)
-			if err != nil {
-				log.Fatal(errorsutil.Wrap(err, "openapi3postman2.ConfigurationReadFile"))
-			}
+	if len(opts.Postman) > 0 && opts.Sync {
+		conv := openapi3postman2.NewConverter(cfg3)
+		pmanFile := opts.PostmanBase
+		if len(pmanFile) == 0 {
+			pmanFile = opts.Postman
+		}
+		report, err := conv.SyncConvert(opts.OpenAPIFile, pmanFile, opts.Postman)
+		if err != nil {
+			log.Fatal(errorsutil.Wrap(err, "spectrum.main << conv.SyncConvert"))
+		}
+		for _, item := range report.Added {
+			fmt.Printf("added [%s] %s\n", item.Key, item.Name)
 		}
+		for _, item := range report.Updated {
+			fmt.Printf("updated [%s] %s\n", item.Key, item.Name)
+		}
+		for _, item := range report.Removed {
+			fmt.Printf("operation removed, item kept [%s] %s in [%s]\n",
+				item.Key, item.Name, strings.Join(item.Folder, " / "))
+		}
+		fmt.Printf("wrote Postman collection [%s]\n", opts.Postman)
+	} else if len(opts.Postman) > 0 {
 
 		conv := openapi3postman2.Converter{
 			Configuration: cfg3,
@@ -65,6 +94,15 @@ func main() {
 
 		fmt.Printf("wrote Postman collection [%s]\n", opts.Postman)
 	}
+	if len(opts.EnvDir) > 0 {
+		filenames, err := openapi3postman2.WriteEnvironmentFiles(cfg3, spec, opts.EnvDir, 0600)
+		if err != nil {
+			log.Fatal(errorsutil.Wrap(err, "spectrum.main << openapi3postman2.WriteEnvironmentFiles"))
+		}
+		for _, filename := range filenames {
+			fmt.Printf("wrote Postman environment [%s]\n", filename)
+		}
+	}
 	if len(opts.XLSXFile) > 0 {
 		sm := openapi3.SpecMore{Spec: spec}
 		err := sm.WriteFileXLSX(opts.XLSXFile, nil, nil, nil)
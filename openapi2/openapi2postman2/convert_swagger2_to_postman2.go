@@ -82,8 +82,8 @@ func Merge(cfg Configuration, pman postman2.Collection, swag openapi2.Specificat
 	if len(pman.Info.Name) == 0 {
 		pman.Info.Name = strings.TrimSpace(swag.Info.Title)
 	}
-	if len(pman.Info.Description) == 0 {
-		pman.Info.Description = strings.TrimSpace(swag.Info.Description)
+	if pman.Info.Description == nil {
+		pman.Info.Description = postman2.NewDescription(swag.Info.Description)
 	}
 	if len(pman.Info.Schema) == 0 {
 		pman.Info.Schema = "https://schema.getpostman.com/json/collection/v2.0.0/collection.json"
@@ -0,0 +1,275 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ComponentGraphRoot is the graph node for refs outside `components`, such
+// as from paths, webhooks and top-level security requirements.
+const ComponentGraphRoot = "#"
+
+// ComponentGraphTypes are the component types included in a
+// `ComponentGraph`.
+var ComponentGraphTypes = []string{
+	"schemas", "parameters", "responses", "requestBodies", "headers",
+	"examples", "securitySchemes", "links", "callbacks"}
+
+// ComponentGraph is a dependency graph of components. Nodes are component
+// JSON pointers, e.g. `#/components/schemas/Pet`, and `ComponentGraphRoot`.
+// Edges are local `$ref`s, security requirements and discriminator
+// mappings.
+type ComponentGraph struct {
+	// Nodes are the components in the spec, sorted.
+	Nodes []string
+	// Edges maps a node to the sorted nodes it depends on. Refs to
+	// components not in the spec are included.
+	Edges map[string][]string
+}
+
+// ComponentGraph returns the dependency graph of the spec components.
+func (sm *SpecMore) ComponentGraph() (ComponentGraph, error) {
+	g := ComponentGraph{Nodes: []string{}, Edges: map[string][]string{}}
+	if sm.Spec == nil {
+		return g, ErrSpecNotSet
+	}
+	data, err := sm.Spec.MarshalJSON()
+	if err != nil {
+		return g, err
+	}
+	doc := map[string]any{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return g, err
+	}
+
+	edges := map[string]map[string]bool{}
+	add := func(from, to string) {
+		if edges[from] == nil {
+			edges[from] = map[string]bool{}
+		}
+		edges[from][to] = true
+	}
+	comps, _ := doc[PathComponents].(map[string]any)
+	for _, compType := range ComponentGraphTypes {
+		typeComps, ok := comps[compType].(map[string]any)
+		if !ok {
+			continue
+		}
+		for name, comp := range typeComps {
+			node := ComponentPointer(compType, name)
+			g.Nodes = append(g.Nodes, node)
+			componentGraphWalk(comp, "", func(to string) { add(node, to) })
+		}
+	}
+	for k, v := range doc {
+		if k != PathComponents {
+			componentGraphWalk(v, k, func(to string) { add(ComponentGraphRoot, to) })
+		}
+	}
+	sort.Strings(g.Nodes)
+	for from, tos := range edges {
+		for to := range tos {
+			g.Edges[from] = append(g.Edges[from], to)
+		}
+		sort.Strings(g.Edges[from])
+	}
+	return g, nil
+}
+
+// ComponentPointer returns the JSON pointer for a component, e.g.
+// `#/components/schemas/Pet`.
+func ComponentPointer(compType, name string) string {
+	return "#/" + PathComponents + "/" + compType + "/" + strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+// componentGraphWalk calls `visit` with the component pointer of each
+// dependency in `node`. `key` is the member name of `node` in its parent.
+func componentGraphWalk(node any, key string, visit func(to string)) {
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			if to, ok := componentRefPointer(ref); ok {
+				visit(to)
+			}
+		}
+		if key == "discriminator" {
+			if mapping, ok := v["mapping"].(map[string]any); ok {
+				for _, target := range mapping {
+					if s, ok := target.(string); ok {
+						if to, ok := componentRefPointer(s); ok {
+							visit(to)
+						} else if !strings.Contains(s, "#") {
+							visit(ComponentPointer(PathSchemas, s))
+						}
+					}
+				}
+			}
+		}
+		for k, item := range v {
+			componentGraphWalk(item, k, visit)
+		}
+	case []any:
+		if key == "security" {
+			for _, item := range v {
+				if req, ok := item.(map[string]any); ok {
+					for name := range req {
+						visit(ComponentPointer("securitySchemes", name))
+					}
+				}
+			}
+		}
+		for _, item := range v {
+			componentGraphWalk(item, "", visit)
+		}
+	}
+}
+
+// componentRefPointer returns the component pointer for a local ref to a
+// component or a location within one.
+func componentRefPointer(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, "#/"+PathComponents+"/") {
+		return "", false
+	}
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+	parts := strings.Split(ref, "/")
+	if len(parts) < 4 || len(parts[3]) == 0 {
+		return "", false
+	}
+	return strings.Join(parts[:4], "/"), true
+}
+
+// Reachable returns the sorted nodes that can be reached from
+// `ComponentGraphRoot`, not including the root.
+func (g ComponentGraph) Reachable() []string {
+	seen := map[string]bool{ComponentGraphRoot: true}
+	queue := []string{ComponentGraphRoot}
+	reachable := []string{}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, to := range g.Edges[node] {
+			if !seen[to] {
+				seen[to] = true
+				reachable = append(reachable, to)
+				queue = append(queue, to)
+			}
+		}
+	}
+	sort.Strings(reachable)
+	return reachable
+}
+
+// Unreachable returns the sorted components that cannot be reached from
+// `ComponentGraphRoot`.
+func (g ComponentGraph) Unreachable() []string {
+	reachable := map[string]bool{}
+	for _, node := range g.Reachable() {
+		reachable[node] = true
+	}
+	unreachable := []string{}
+	for _, node := range g.Nodes {
+		if !reachable[node] {
+			unreachable = append(unreachable, node)
+		}
+	}
+	return unreachable
+}
+
+// ComponentGraphLabel returns a short label for a node, e.g. `schemas/Pet`.
+// Escaped `/` and `~` in component names are unescaped.
+func ComponentGraphLabel(node string) string {
+	if node == ComponentGraphRoot {
+		return "spec"
+	}
+	label := strings.TrimPrefix(node, "#/"+PathComponents+"/")
+	return strings.ReplaceAll(strings.ReplaceAll(label, "~1", "/"), "~0", "~")
+}
+
+// dotQuote returns `s` as a DOT quoted string.
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// mermaidQuote returns `s` as a Mermaid quoted label. Mermaid has no
+// escape character, so quotes are written as the `#quot;` entity.
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// DOT returns the graph in Graphviz DOT format. Unreachable components are
+// dashed.
+func (g ComponentGraph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph components {\n  rankdir=LR;\n")
+	sb.WriteString(fmt.Sprintf("  %s [shape=box];\n", dotQuote(ComponentGraphLabel(ComponentGraphRoot))))
+	unreachable := map[string]bool{}
+	for _, node := range g.Unreachable() {
+		unreachable[node] = true
+	}
+	for _, node := range g.Nodes {
+		if unreachable[node] {
+			sb.WriteString(fmt.Sprintf("  %s [style=dashed];\n", dotQuote(ComponentGraphLabel(node))))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %s;\n", dotQuote(ComponentGraphLabel(node))))
+		}
+	}
+	for _, from := range g.edgeSources() {
+		for _, to := range g.Edges[from] {
+			sb.WriteString(fmt.Sprintf("  %s -> %s;\n", dotQuote(ComponentGraphLabel(from)), dotQuote(ComponentGraphLabel(to))))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart. Unreachable components
+// use the `unreachable` class.
+func (g ComponentGraph) Mermaid() string {
+	ids := map[string]string{}
+	id := func(node string) string {
+		if _, ok := ids[node]; !ok {
+			ids[node] = fmt.Sprintf("n%d", len(ids))
+		}
+		return ids[node]
+	}
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for _, node := range append([]string{ComponentGraphRoot}, g.Nodes...) {
+		sb.WriteString(fmt.Sprintf("  %s[%s]\n", id(node), mermaidQuote(ComponentGraphLabel(node))))
+	}
+	for _, from := range g.edgeSources() {
+		for _, to := range g.Edges[from] {
+			if _, ok := ids[to]; !ok {
+				sb.WriteString(fmt.Sprintf("  %s[%s]\n", id(to), mermaidQuote(ComponentGraphLabel(to))))
+			}
+			sb.WriteString(fmt.Sprintf("  %s --> %s\n", id(from), id(to)))
+		}
+	}
+	if unreachable := g.Unreachable(); len(unreachable) > 0 {
+		sb.WriteString("  classDef unreachable stroke-dasharray: 5 5\n")
+		for _, node := range unreachable {
+			sb.WriteString(fmt.Sprintf("  class %s unreachable\n", id(node)))
+		}
+	}
+	return sb.String()
+}
+
+// edgeSources returns the nodes with edges, root first.
+func (g ComponentGraph) edgeSources() []string {
+	sources := []string{}
+	for from := range g.Edges {
+		if from != ComponentGraphRoot {
+			sources = append(sources, from)
+		}
+	}
+	sort.Strings(sources)
+	if _, ok := g.Edges[ComponentGraphRoot]; ok {
+		sources = append([]string{ComponentGraphRoot}, sources...)
+	}
+	return sources
+}
@@ -0,0 +1,70 @@
+package openapi3
+
+import (
+	"strings"
+	"testing"
+)
+
+const componentGraphSpec = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"paths":{
+  "/pets":{
+    "get":{
+      "responses":{
+        "200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}}}}}},
+"components":{
+  "schemas":{
+    "Pet":{"type":"object","properties":{"owner":{"$ref":"#/components/schemas/Owner~1Address"}}},
+    "Owner/Address":{"type":"string"},
+    "Legacy \"v1\"":{"type":"object","properties":{"pet":{"$ref":"#/components/schemas/Pet"}}}}}}`
+
+const componentGraphDOT = `digraph components {
+  rankdir=LR;
+  "spec" [shape=box];
+  "schemas/Legacy \"v1\"" [style=dashed];
+  "schemas/Owner/Address";
+  "schemas/Pet";
+  "spec" -> "schemas/Pet";
+  "schemas/Legacy \"v1\"" -> "schemas/Pet";
+  "schemas/Pet" -> "schemas/Owner/Address";
+}
+`
+
+const componentGraphMermaid = `flowchart LR
+  n0["spec"]
+  n1["schemas/Legacy #quot;v1#quot;"]
+  n2["schemas/Owner/Address"]
+  n3["schemas/Pet"]
+  n0 --> n3
+  n1 --> n3
+  n3 --> n2
+  classDef unreachable stroke-dasharray: 5 5
+  class n1 unreachable
+`
+
+// TestComponentGraph ensures reachability and the DOT and Mermaid output,
+// including unreachable styling and names with quotes and slashes.
+func TestComponentGraph(t *testing.T) {
+	spec, err := Parse([]byte(componentGraphSpec))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	g, err := (&SpecMore{Spec: spec}).ComponentGraph()
+	if err != nil {
+		t.Fatalf("openapi3.SpecMore.ComponentGraph() Error [%s]", err.Error())
+	}
+	wantReachable := `#/components/schemas/Owner~1Address,#/components/schemas/Pet`
+	if got := strings.Join(g.Reachable(), ","); got != wantReachable {
+		t.Errorf("openapi3.ComponentGraph.Reachable() Mismatch: want [%s], got [%s]", wantReachable, got)
+	}
+	wantUnreachable := `#/components/schemas/Legacy "v1"`
+	if got := strings.Join(g.Unreachable(), ","); got != wantUnreachable {
+		t.Errorf("openapi3.ComponentGraph.Unreachable() Mismatch: want [%s], got [%s]", wantUnreachable, got)
+	}
+	if got := g.DOT(); got != componentGraphDOT {
+		t.Errorf("openapi3.ComponentGraph.DOT() Mismatch: want [%s], got [%s]", componentGraphDOT, got)
+	}
+	if got := g.Mermaid(); got != componentGraphMermaid {
+		t.Errorf("openapi3.ComponentGraph.Mermaid() Mismatch: want [%s], got [%s]", componentGraphMermaid, got)
+	}
+}
@@ -8,6 +8,7 @@ const (
 	TypeArray      = "array"
 	TypeBoolean    = "boolean"
 	TypeInteger    = "integer"
+	TypeNumber     = "number"
 	TypeObject     = "object"
 	TypeString     = "string"
 	FormatDate     = "date"
@@ -0,0 +1,178 @@
+package openapi3
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+)
+
+// DereferenceOptions configures `SpecMore.Dereference()`.
+type DereferenceOptions struct {
+	// MaxDepth is the number of times a recursive ref is expanded inside
+	// itself. When 0, the ref is kept at the cycle point, and the component
+	// it refers to is kept. Otherwise, the ref at the depth limit is
+	// replaced with an empty schema so the spec has no refs.
+	MaxDepth int
+}
+
+// Dereference returns a copy of the spec where local `$ref`s to schemas,
+// parameters, responses, request bodies, headers, examples and other
+// components are replaced with copies of their values. Refs to other files
+// are kept, so multi-file specs should be bundled first. See
+// `DereferenceOptions` for how recursive refs are handled.
+func (sm *SpecMore) Dereference(opts *DereferenceOptions) (*Spec, error) {
+	if sm.Spec == nil {
+		return nil, ErrSpecNotSet
+	}
+	if opts == nil {
+		opts = &DereferenceOptions{}
+	}
+	data, err := sm.Spec.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]any{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	d := dereferencer{doc: doc, maxDepth: opts.MaxDepth, stack: map[string]int{}}
+	out := map[string]any{}
+	for k, v := range doc {
+		if k != PathComponents {
+			if out[k], err = d.walk(v); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		comps, ok := v.(map[string]any)
+		if !ok {
+			out[k] = v
+			continue
+		}
+		// A component is on the stack while its own value is dereferenced,
+		// so recursive components refer to themselves at the cycle point.
+		outComps := map[string]any{}
+		for compType, typeComps := range comps {
+			typeMap, ok := typeComps.(map[string]any)
+			if !ok {
+				outComps[compType] = typeComps
+				continue
+			}
+			outTypeMap := map[string]any{}
+			for name, comp := range typeMap {
+				key := "/" + strings.Join([]string{PathComponents, compType, name}, "/")
+				d.stack[key]++
+				outTypeMap[name], err = d.walk(comp)
+				d.stack[key]--
+				if err != nil {
+					return nil, err
+				}
+			}
+			outComps[compType] = outTypeMap
+		}
+		out[k] = outComps
+	}
+
+	data, err = json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return oas3.NewLoader().LoadFromData(data)
+}
+
+type dereferencer struct {
+	doc      map[string]any
+	maxDepth int
+	stack    map[string]int // refs being expanded, keyed by unescaped pointer
+}
+
+func (d *dereferencer) walk(node any) (any, error) {
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+			pointer, err := url.PathUnescape(strings.TrimPrefix(ref, "#"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid $ref [%s]: %w", ref, err)
+			}
+			parts := refPointerParts(pointer)
+			key := "/" + strings.Join(parts, "/")
+			if d.stack[key] > d.maxDepth {
+				if d.maxDepth == 0 {
+					return map[string]any{"$ref": ref}, nil
+				}
+				return map[string]any{}, nil
+			}
+			target, ok := refPointerValue(d.doc, parts)
+			if !ok {
+				return nil, fmt.Errorf("cannot resolve $ref [%s]", ref)
+			}
+			d.stack[key]++
+			out, err := d.walk(target)
+			d.stack[key]--
+			return out, err
+		}
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			val, err := d.walk(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = val
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			val, err := d.walk(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = val
+		}
+		return out, nil
+	}
+	return node, nil
+}
+
+func refPointerParts(pointer string) []string {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if len(pointer) == 0 {
+		return []string{}
+	}
+	parts := strings.Split(pointer, "/")
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(strings.ReplaceAll(p, "~1", "/"), "~0", "~")
+	}
+	return parts
+}
+
+func refPointerValue(doc any, parts []string) (any, bool) {
+	cur := doc
+	for _, p := range parts {
+		switch v := cur.(type) {
+		case map[string]any:
+			next, ok := v[p]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			i, err := strconv.Atoi(p)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			cur = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
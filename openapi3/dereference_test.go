@@ -0,0 +1,76 @@
+package openapi3
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const dereferenceSpec = `{
+"openapi":"3.0.3","info":{"title":"Nodes","version":"1.0.0"},
+"paths":{
+  "/nodes/{nodeId}":{
+    "put":{
+      "parameters":[{"$ref":"#/components/parameters/NodeId"}],
+      "requestBody":{"$ref":"#/components/requestBodies/Node"},
+      "responses":{
+        "200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Node"}}}},
+        "default":{"$ref":"#/components/responses/Error"}}}}},
+"components":{
+  "parameters":{"NodeId":{"name":"nodeId","in":"path","required":true,"schema":{"$ref":"#/components/schemas/Id"}}},
+  "requestBodies":{"Node":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Node"}}}}},
+  "responses":{"Error":{"description":"Error","headers":{"X-Request-Id":{"$ref":"#/components/headers/RequestId"}}}},
+  "headers":{"RequestId":{"schema":{"$ref":"#/components/schemas/Id"}}},
+  "schemas":{
+    "Id":{"type":"string"},
+    "Node":{"type":"object","properties":{
+      "id":{"$ref":"#/components/schemas/Id"},
+      "children":{"type":"array","items":{"$ref":"#/components/schemas/Node"}}}}}}}`
+
+var dereferenceTests = []struct {
+	maxDepth int
+	wantRefs int
+}{
+	// The ref is kept in the `Node` schema and request body components, and
+	// in the operation request body and response.
+	{0, 4},
+	{1, 0},
+	{3, 0},
+}
+
+// TestDereference ensures refs are inlined and recursive refs are kept at
+// the cycle point or expanded to a depth.
+func TestDereference(t *testing.T) {
+	spec, err := Parse([]byte(dereferenceSpec))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	sm := SpecMore{Spec: spec}
+	for _, tt := range dereferenceTests {
+		deref, err := sm.Dereference(&DereferenceOptions{MaxDepth: tt.maxDepth})
+		if err != nil {
+			t.Fatalf("openapi3.SpecMore.Dereference() Error [%s]", err.Error())
+		}
+		if err := deref.Validate(context.Background()); err != nil {
+			t.Errorf("openapi3.SpecMore.Dereference() Validate Error [%s]", err.Error())
+		}
+		data, err := deref.MarshalJSON()
+		if err != nil {
+			t.Fatalf("openapi3.Spec.MarshalJSON() Error [%s]", err.Error())
+		}
+		if got := strings.Count(string(data), `"$ref"`); got != tt.wantRefs {
+			t.Errorf("openapi3.SpecMore.Dereference() Mismatch: maxDepth [%d] want [%d] refs, got [%d]: %s",
+				tt.maxDepth, tt.wantRefs, got, string(data))
+		}
+
+		op := deref.Paths.Find("/nodes/{nodeId}").Put
+		if op.Parameters[0].Ref != "" || op.Parameters[0].Value.Schema.Value == nil ||
+			!TypesRefIs(op.Parameters[0].Value.Schema.Value.Type, TypeString) {
+			t.Errorf("openapi3.SpecMore.Dereference() Mismatch: maxDepth [%d] want inlined string parameter", tt.maxDepth)
+		}
+		resp := op.Responses.Value("default")
+		if resp.Ref != "" || resp.Value.Headers["X-Request-Id"].Ref != "" {
+			t.Errorf("openapi3.SpecMore.Dereference() Mismatch: maxDepth [%d] want inlined response and header", tt.maxDepth)
+		}
+	}
+}
@@ -6,12 +6,14 @@ import (
 	"os"
 	"reflect"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	oas3 "github.com/getkin/kin-openapi/openapi3"
 	"github.com/grokify/gocharts/v2/data/table"
 	"github.com/grokify/mogo/errors/errorsutil"
+	"github.com/grokify/mogo/net/http/httputilmore"
 	"github.com/grokify/mogo/os/osutil"
 )
 
@@ -78,46 +80,131 @@ func MergeFiles(filepaths []string, mergeOpts *MergeOptions) (*Spec, error) {
 	return specMaster, nil
 }
 
+// Merge merges `specExtra` into `specMaster`. Collisions, where both specs
+// have different items with the same name, are resolved by the policies in
+// `mergeOpts` and recorded in `mergeOpts.Conflicts`. Security schemes are
+// merged. Servers are only merged when `mergeOpts.Policies` has a policy for
+// `MergeTypeServers`, otherwise the `specMaster` servers are kept.
 func Merge(specMaster, specExtra *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, error) {
-	specMaster = MergeTags(specMaster, specExtra)
-	specMaster, err := MergeParameters(specMaster, specExtra, specExtraNote, mergeOpts)
-	if err != nil {
-		return specMaster, err
+	if specExtra == nil {
+		return specMaster, errors.New("spec extra cannot be nil")
 	}
-	specMaster, err = MergeSchemas(specMaster, specExtra, specExtraNote, mergeOpts)
-	if err != nil {
-		return specMaster, err
+	if mergeOpts == nil {
+		mergeOpts = &MergeOptions{}
 	}
-	specMaster, err = MergePaths(specMaster, specExtra)
-	if err != nil {
+	if err := mergeOpts.checkPolicies(); err != nil {
 		return specMaster, err
 	}
-	specMaster, err = MergeResponses(specMaster, specExtra, specExtraNote, mergeOpts)
+	if specMaster.Components == nil {
+		specMaster.Components = &oas3.Components{}
+	}
+	specExtra, err := mergeRename(specMaster, specExtra, specExtraNote, mergeOpts)
 	if err != nil {
 		return specMaster, err
 	}
-	return MergeRequestBodies(specMaster, specExtra, specExtraNote)
+	if specExtra.Components == nil {
+		specExtra.Components = &oas3.Components{}
+	}
+	if _, ok := mergeOpts.Policies[MergeTypeServers]; ok {
+		if specMaster, err = MergeServers(specMaster, specExtra, specExtraNote, mergeOpts); err != nil {
+			return specMaster, err
+		}
+	}
+	for _, mergeFunc := range []func(specMaster, specExtra *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, error){
+		mergeTags,
+		MergeSecuritySchemes,
+		MergeParameters,
+		MergeSchemas,
+		mergePaths,
+		MergeResponses,
+		mergeRequestBodies,
+	} {
+		if specMaster, err = mergeFunc(specMaster, specExtra, specExtraNote, mergeOpts); err != nil {
+			return specMaster, err
+		}
+	}
+	return specMaster, nil
 }
 
+// MergeTags adds the `specExtra` tags that are not in `specMaster`.
 func MergeTags(specMaster, specExtra *Spec) *Spec {
+	specMaster, _ = mergeTags(specMaster, specExtra, "", &MergeOptions{
+		Policies: map[string]MergePolicy{MergeTypeTags: MergePolicyKeepFirst}})
+	return specMaster
+}
+
+func mergeTags(specMaster, specExtra *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, error) {
 	tagsMap := map[string]int{}
-	for _, tag := range specMaster.Tags {
-		tagsMap[tag.Name] = 1
+	for i, tag := range specMaster.Tags {
+		tagsMap[tag.Name] = i
 	}
 	for _, tag := range specExtra.Tags {
 		tag.Name = strings.TrimSpace(tag.Name)
-		if _, ok := tagsMap[tag.Name]; !ok {
+		i, ok := tagsMap[tag.Name]
+		if !ok {
+			tagsMap[tag.Name] = len(specMaster.Tags)
 			specMaster.Tags = append(specMaster.Tags, tag)
+			continue
+		} else if mergeSameTag(tag.Name, specMaster.Tags[i], tag) {
+			continue
+		}
+		policy, err := mergeOpts.resolve(MergeTypeTags, tag.Name, specExtraNote)
+		if err != nil {
+			return specMaster, err
+		}
+		switch policy {
+		case MergePolicyKeepLast:
+			specMaster.Tags[i] = tag
+		case MergePolicyDeepMerge:
+			merged, err := mergeDeepValue(specMaster.Tags[i], tag)
+			if err != nil {
+				return specMaster, err
+			}
+			specMaster.Tags[i] = merged
 		}
 	}
-	return specMaster
+	return specMaster, nil
+}
+
+// MergeServers adds the `specExtra` servers, keyed by URL.
+func MergeServers(specMaster, specExtra *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, error) {
+	for _, server := range specExtra.Servers {
+		if server == nil {
+			continue
+		}
+		i := slices.IndexFunc(specMaster.Servers, func(s *oas3.Server) bool {
+			return s != nil && s.URL == server.URL
+		})
+		if i < 0 {
+			specMaster.Servers = append(specMaster.Servers, server)
+			continue
+		} else if reflect.DeepEqual(specMaster.Servers[i], server) {
+			continue
+		}
+		policy, err := mergeOpts.resolve(MergeTypeServers, server.URL, specExtraNote)
+		if err != nil {
+			return specMaster, err
+		}
+		switch policy {
+		case MergePolicyKeepLast:
+			specMaster.Servers[i] = server
+		case MergePolicyDeepMerge:
+			merged, err := mergeDeepValue(specMaster.Servers[i], server)
+			if err != nil {
+				return specMaster, err
+			}
+			specMaster.Servers[i] = merged
+		}
+	}
+	return specMaster, nil
 }
 
 // MergeWithTables performs a spec merge and returns comparison
-// tables. This is useful to combine with github.com/grokify/gocharts/v2/data/table
+// tables and the conflict report of the merge. This is useful to combine with github.com/grokify/gocharts/v2/data/table
 // WriteXLSX() to write out comparison tables for debugging.
 func MergeWithTables(spec1, spec2 *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, []*table.Table, error) {
 	tbls := []*table.Table{}
+	conflictsStart := len(mergeOpts.Conflicts)
 	sm1 := SpecMore{Spec: spec1}
 	sm2 := SpecMore{Spec: spec2}
 	tbls1, err := sm1.OperationsTable(mergeOpts.TableColumns, mergeOpts.TableOpFilterFunc, mergeOpts.TableAddlColFormatFuncs)
@@ -134,7 +221,7 @@ func MergeWithTables(spec1, spec2 *Spec, specExtraNote string, mergeOpts *MergeO
 	tbls[1].Name = "Spec2"
 	specf, err := Merge(spec1, spec2, specExtraNote, mergeOpts)
 	if err != nil {
-		return specf, tbls, err
+		return specf, append(tbls, mergeOpts.Conflicts[conflictsStart:].Table()), err
 	}
 	smf := SpecMore{Spec: specf}
 	tblsf, err := smf.OperationsTable(mergeOpts.TableColumns, mergeOpts.TableOpFilterFunc, mergeOpts.TableAddlColFormatFuncs)
@@ -144,16 +231,29 @@ func MergeWithTables(spec1, spec2 *Spec, specExtraNote string, mergeOpts *MergeO
 	tbls = append(tbls, tblsf)
 
 	tbls[2].Name = "SpecFinal"
+	tbls = append(tbls, mergeOpts.Conflicts[conflictsStart:].Table())
 	return specf, tbls, nil
 }
 
+// MergePaths adds the `specExtra` operations, returning an error for
+// different operations with the same path and method.
 func MergePaths(specMaster, specExtra *Spec) (*Spec, error) {
+	return mergePaths(specMaster, specExtra, "", nil)
+}
+
+func mergePaths(specMaster, specExtra *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, error) {
 	// getkin v0.121.0 to v0.122.0 - new version
 	if specExtra == nil {
 		return specMaster, errors.New("spec extra cannot be nil")
+	} else if specExtra.Paths == nil {
+		return specMaster, nil
+	}
+	if specMaster.Paths == nil {
+		specMaster.Paths = oas3.NewPaths()
 	}
 	addPathMap := specExtra.Paths.Map()
-	for addPathKey, addPathItem := range addPathMap {
+	for _, addPathKey := range mergeSortedKeys(addPathMap) {
+		addPathItem := addPathMap[addPathKey]
 		if addPathItem == nil {
 			continue
 		}
@@ -162,12 +262,33 @@ func MergePaths(specMaster, specExtra *Spec) (*Spec, error) {
 			specMaster.Paths.Set(addPathKey, addPathItem)
 			continue
 		}
-		srcPathItemMore := PathItemMore{PathItem: srcPathItem}
-		err := srcPathItemMore.AddPathItemOperations(addPathItem, false)
-		if err != nil {
-			return specMaster, err
+		for _, method := range httputilmore.Methods() {
+			opAdd := addPathItem.GetOperation(method)
+			opSrc := srcPathItem.GetOperation(method)
+			if opAdd == nil {
+				continue
+			} else if opSrc == nil {
+				srcPathItem.SetOperation(method, opAdd)
+				continue
+			} else if reflect.DeepEqual(opAdd, opSrc) {
+				continue
+			}
+			policy, err := mergeOpts.resolve(MergeTypeOperations, method+" "+addPathKey, specExtraNote)
+			if err != nil {
+				return specMaster, errorsutil.Wrap(err, fmt.Sprintf("operation collision on op id (%s)", opSrc.OperationID))
+			}
+			switch policy {
+			case MergePolicyKeepLast:
+				srcPathItem.SetOperation(method, opAdd)
+			case MergePolicyDeepMerge:
+				merged, err := mergeDeepValue(opSrc, opAdd)
+				if err != nil {
+					return specMaster, err
+				}
+				srcPathItem.SetOperation(method, merged)
+			}
 		}
-		specMaster.Paths.Set(addPathKey, srcPathItemMore.PathItem)
+		specMaster.Paths.Set(addPathKey, srcPathItem)
 	}
 
 	return specMaster, nil
@@ -253,121 +374,50 @@ func MergePathsOld(specMaster, specExtra *Spec) (*Spec, error) {
 */
 
 func MergeParameters(specMaster, specExtra *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, error) {
-	if specMaster.Components.Parameters == nil {
-		specMaster.Components.Parameters = map[string]*oas3.ParameterRef{}
-	}
-	for pName, pExtra := range specExtra.Components.Parameters {
-		if pExtra == nil {
-			continue
-		} else if pMaster, ok := specMaster.Components.Parameters[pName]; ok {
-			if pMaster == nil {
-				specMaster.Components.Parameters[pName] = pExtra
-			} else {
-				if mergeOpts == nil {
-					mergeOpts = &MergeOptions{}
-				}
-				if mergeOpts.CollisionCheckResult == CollisionCheckSkip {
-					continue
-				} else if reflect.DeepEqual(pExtra, pMaster) {
-					continue
-				} else if mergeOpts.CollisionCheckResult == CollisionCheckOverwrite {
-					specExtra.Components.Parameters[pName] = pExtra
-				} else {
-					return nil, fmt.Errorf("E_SCHEMA_COLLISION [%v] EXTRA_COMPONENTS_PARAMETER [%s]", specExtraNote, pName)
-				}
-			}
-		} else {
-			specMaster.Components.Parameters[pName] = pExtra
-		}
-	}
-	return specMaster, nil
+	var err error
+	specMaster.Components.Parameters, err = mergeComponents(MergeTypeParameters,
+		specMaster.Components.Parameters, specExtra.Components.Parameters, specExtraNote, mergeOpts, mergeSame[oas3.ParameterRef])
+	return specMaster, err
 }
 
 func MergeResponses(specMaster, specExtra *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, error) {
-	if specMaster.Components.Responses == nil {
-		specMaster.Components.Responses = map[string]*oas3.ResponseRef{}
-	}
-	for rName, rExtra := range specExtra.Components.Responses {
-		if rExtra == nil {
-			continue
-		} else if rMaster, ok := specMaster.Components.Responses[rName]; ok {
-			if rMaster == nil {
-				specMaster.Components.Responses[rName] = rExtra
-			} else {
-				if mergeOpts == nil {
-					mergeOpts = &MergeOptions{}
-				}
-				if mergeOpts.CollisionCheckResult == CollisionCheckSkip {
-					continue
-				} else if reflect.DeepEqual(rExtra, rMaster) {
-					continue
-				} else {
-					return nil, fmt.Errorf("E_SCHEMA_COLLISION [%v] EXTRA_COMPONENTS_RESPONSE [%s]", specExtraNote, rName)
-				}
-			}
-		} else {
-			specMaster.Components.Responses[rName] = rExtra
-		}
-	}
-	return specMaster, nil
+	var err error
+	specMaster.Components.Responses, err = mergeComponents(MergeTypeResponses,
+		specMaster.Components.Responses, specExtra.Components.Responses, specExtraNote, mergeOpts, mergeSame[oas3.ResponseRef])
+	return specMaster, err
 }
 
 func MergeSchemas(specMaster, specExtra *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, error) {
-	for schemaName, schemaExtra := range specExtra.Components.Schemas {
-		if schemaExtra == nil {
-			continue
-		} else if schemaMaster, ok := specMaster.Components.Schemas[schemaName]; ok {
-			if schemaMaster == nil {
-				specMaster.Components.Schemas[schemaName] = schemaExtra
-			} else {
-				if mergeOpts == nil {
-					mergeOpts = &MergeOptions{}
-				}
-				checkCollisionResult := mergeOpts.CheckSchemaCollision(schemaName, schemaMaster, schemaExtra, specExtraNote)
-				if checkCollisionResult != CollisionCheckSame &&
-					mergeOpts.CollisionCheckResult != CollisionCheckSkip {
-					switch mergeOpts.CollisionCheckResult {
-					case CollisionCheckOverwrite:
-						delete(specMaster.Components.Schemas, schemaName)
-						specMaster.Components.Schemas[schemaName] = schemaExtra
-					case CollisionCheckError:
-						return nil, fmt.Errorf("E_SCHEMA_COLLISION [%v] EXTRA_SPEC [%s]", schemaName, specExtraNote)
-					}
-				}
-				/*
-					if !reflect.DeepEqual(schemaMaster, schemaExtra) {
-						return nil, fmt.Errorf("E_SCHEMA_COLLISION [%v] EXTRA_SPEC [%s]", schemaName, specExtraNote)
-					}*/
-				continue
-			}
-		} else {
-			specMaster.Components.Schemas[schemaName] = schemaExtra
-		}
+	if mergeOpts == nil {
+		mergeOpts = &MergeOptions{}
 	}
-	return specMaster, nil
+	var err error
+	specMaster.Components.Schemas, err = mergeComponents(MergeTypeSchemas,
+		specMaster.Components.Schemas, specExtra.Components.Schemas, specExtraNote, mergeOpts,
+		func(schemaName string, schemaMaster, schemaExtra *oas3.SchemaRef) bool {
+			return mergeOpts.CheckSchemaCollision(schemaName, schemaMaster, schemaExtra, specExtraNote) == CollisionCheckSame
+		})
+	return specMaster, err
 }
 
+// MergeRequestBodies adds the `specExtra` request bodies, returning an error
+// for different request bodies with the same name.
 func MergeRequestBodies(specMaster, specExtra *Spec, specExtraNote string) (*Spec, error) {
-	for rbName, rbExtra := range specExtra.Components.RequestBodies {
-		if rbExtra == nil {
-			continue
-		} else if rbMaster, ok := specMaster.Components.RequestBodies[rbName]; ok {
-			if rbMaster == nil {
-				if specMaster.Components.RequestBodies == nil {
-					specMaster.Components.RequestBodies = map[string]*oas3.RequestBodyRef{}
-				}
-				specMaster.Components.RequestBodies[rbName] = rbExtra
-			} else if !reflect.DeepEqual(rbMaster, rbExtra) {
-				return nil, fmt.Errorf("E_SCHEMA_COLLISION [%v] EXTRA_SPEC [%s]", rbName, specExtraNote)
-			}
-		} else {
-			if specMaster.Components.RequestBodies == nil {
-				specMaster.Components.RequestBodies = map[string]*oas3.RequestBodyRef{}
-			}
-			specMaster.Components.RequestBodies[rbName] = rbExtra
-		}
-	}
-	return specMaster, nil
+	return mergeRequestBodies(specMaster, specExtra, specExtraNote, nil)
+}
+
+func mergeRequestBodies(specMaster, specExtra *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, error) {
+	var err error
+	specMaster.Components.RequestBodies, err = mergeComponents(MergeTypeRequestBodies,
+		specMaster.Components.RequestBodies, specExtra.Components.RequestBodies, specExtraNote, mergeOpts, mergeSame[oas3.RequestBodyRef])
+	return specMaster, err
+}
+
+func MergeSecuritySchemes(specMaster, specExtra *Spec, specExtraNote string, mergeOpts *MergeOptions) (*Spec, error) {
+	var err error
+	specMaster.Components.SecuritySchemes, err = mergeComponents(MergeTypeSecuritySchemes,
+		specMaster.Components.SecuritySchemes, specExtra.Components.SecuritySchemes, specExtraNote, mergeOpts, mergeSame[oas3.SecuritySchemeRef])
+	return specMaster, err
 }
 
 func WriteFileDirMerge(outfile, inputDir string, perm os.FileMode, mergeOpts *MergeOptions) (int, error) {
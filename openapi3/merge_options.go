@@ -26,6 +26,17 @@ type MergeOptions struct {
 	TableColumns            *tabulator.ColumnSet
 	TableOpFilterFunc       func(path, method string, op *oas3.Operation) bool
 	TableAddlColFormatFuncs *OperationMoreStringFuncMap
+	// Policies sets the collision policy by merge type, e.g.
+	// `MergeTypeOperations`. See `Policy()` for types without a policy.
+	// `Merge()` only merges servers with a `MergeTypeServers` policy.
+	Policies map[string]MergePolicy
+	// RenamePrefix is prepended to names renamed by
+	// `MergePolicyRenameWithPrefix`. It defaults to the extra spec file
+	// name without extension.
+	RenamePrefix string
+	// Conflicts is the report of collisions resolved by merges using these
+	// options.
+	Conflicts MergeConflicts
 }
 
 func NewMergeOptionsSkip() *MergeOptions {
@@ -34,6 +45,38 @@ func NewMergeOptionsSkip() *MergeOptions {
 		SchemaFunc:           SchemaCheckCollisionSkip}
 }
 
+// Policy returns the collision policy for a merge type. Without a policy in
+// `Policies`, `CollisionCheckSkip` keeps the first item and
+// `CollisionCheckOverwrite` keeps the last. Otherwise schemas, security
+// schemes, servers and tags keep the first item and other types return an
+// error.
+func (mo *MergeOptions) Policy(mergeType string) MergePolicy {
+	if mo == nil {
+		return mergePolicyDefault(mergeType)
+	} else if policy, ok := mo.Policies[mergeType]; ok && len(policy) > 0 {
+		return policy
+	}
+	switch mo.CollisionCheckResult {
+	case CollisionCheckSkip:
+		return MergePolicyKeepFirst
+	case CollisionCheckOverwrite:
+		return MergePolicyKeepLast
+	case CollisionCheckError:
+		return MergePolicyError
+	default:
+		return mergePolicyDefault(mergeType)
+	}
+}
+
+func mergePolicyDefault(mergeType string) MergePolicy {
+	switch mergeType {
+	case MergeTypeSchemas, MergeTypeSecuritySchemes, MergeTypeServers, MergeTypeTags:
+		return MergePolicyKeepFirst
+	default:
+		return MergePolicyError
+	}
+}
+
 func (mo *MergeOptions) CheckSchemaCollision(schemaName string, sch1, sch2 interface{}, hint2 string) CollisionCheckResult {
 	if mo.CollisionCheckResult == CollisionCheckSkip {
 		mo.SchemaFunc = SchemaCheckCollisionSkip
@@ -0,0 +1,626 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/gocharts/v2/data/table"
+	"github.com/grokify/mogo/net/http/httputilmore"
+)
+
+// MergePolicy is how `Merge` resolves a collision, where the master and
+// extra specs have different items with the same name.
+type MergePolicy string
+
+const (
+	// MergePolicyKeepFirst keeps the master item.
+	MergePolicyKeepFirst MergePolicy = "keep-first"
+	// MergePolicyKeepLast replaces the master item with the extra item.
+	MergePolicyKeepLast MergePolicy = "keep-last"
+	// MergePolicyError returns an error.
+	MergePolicyError MergePolicy = "error"
+	// MergePolicyRenameWithPrefix adds the extra item under a name with
+	// `MergeOptions.RenamePrefix` and updates refs to it in the extra spec.
+	// Operations are added under the path with a `/{prefix}` segment and a
+	// prefixed operation id. It is not supported for servers.
+	MergePolicyRenameWithPrefix MergePolicy = "rename-with-prefix"
+	// MergePolicyDeepMerge merges objects by member, keeping master values
+	// that are not objects and combining arrays without duplicates.
+	MergePolicyDeepMerge MergePolicy = "deep-merge"
+)
+
+// Merge types are the `MergeOptions.Policies` keys. Operations are keyed by
+// path and method, servers by URL and tags by name.
+const (
+	MergeTypeOperations      = "operations"
+	MergeTypeParameters      = "parameters"
+	MergeTypeRequestBodies   = "requestBodies"
+	MergeTypeResponses       = "responses"
+	MergeTypeSchemas         = "schemas"
+	MergeTypeSecuritySchemes = "securitySchemes"
+	MergeTypeServers         = "servers"
+	MergeTypeTags            = "tags"
+)
+
+// MergeConflict is a collision resolved by a merge.
+type MergeConflict struct {
+	Type   string
+	Name   string
+	Source string
+	Policy MergePolicy
+	Result string
+}
+
+// MergeConflicts is a merge conflict report.
+type MergeConflicts []MergeConflict
+
+// Table returns the report as a table named `Conflicts`.
+func (mcs MergeConflicts) Table() *table.Table {
+	tbl := table.NewTable("Conflicts")
+	tbl.Columns = []string{"Type", "Name", "Source", "Policy", "Result"}
+	for _, mc := range mcs {
+		tbl.Rows = append(tbl.Rows, []string{mc.Type, mc.Name, mc.Source, string(mc.Policy), mc.Result})
+	}
+	return &tbl
+}
+
+// checkPolicies returns an error for unknown or unsupported policies.
+func (mo *MergeOptions) checkPolicies() error {
+	for mergeType, policy := range mo.Policies {
+		switch mergeType {
+		case MergeTypeOperations, MergeTypeParameters, MergeTypeRequestBodies, MergeTypeResponses,
+			MergeTypeSchemas, MergeTypeSecuritySchemes, MergeTypeServers, MergeTypeTags:
+		default:
+			return fmt.Errorf("merge type not supported (%s)", mergeType)
+		}
+		switch policy {
+		case "", MergePolicyKeepFirst, MergePolicyKeepLast, MergePolicyError, MergePolicyDeepMerge:
+		case MergePolicyRenameWithPrefix:
+			if mergeType == MergeTypeServers {
+				return fmt.Errorf("merge policy (%s) not supported for merge type (%s)", policy, mergeType)
+			}
+		default:
+			return fmt.Errorf("merge policy not supported (%s)", policy)
+		}
+	}
+	return nil
+}
+
+// resolve records a conflict, unless `mo` is nil, and returns the policy
+// for it, or an error for `MergePolicyError`.
+func (mo *MergeOptions) resolve(mergeType, name, specExtraNote string) (MergePolicy, error) {
+	policy := mo.Policy(mergeType)
+	result := ""
+	switch policy {
+	case MergePolicyKeepFirst:
+		result = "kept first"
+	case MergePolicyKeepLast:
+		result = "kept last"
+	case MergePolicyDeepMerge:
+		result = "deep merged"
+	case MergePolicyRenameWithPrefix:
+		// recorded with the new name by `mergeRename()`.
+		return policy, nil
+	default:
+		result = "error"
+	}
+	if mo != nil {
+		mo.Conflicts = append(mo.Conflicts, MergeConflict{
+			Type: mergeType, Name: name, Source: specExtraNote, Policy: policy, Result: result})
+	}
+	if result == "error" {
+		return policy, fmt.Errorf("E_MERGE_COLLISION [%v] EXTRA_%s [%s]", specExtraNote, strings.ToUpper(mergeType), name)
+	}
+	return policy, nil
+}
+
+// mergeComponents merges the `extra` components into `master`. `same`
+// reports whether two components with the same name are equivalent.
+func mergeComponents[M ~map[string]*T, T any](mergeType string, master, extra M, specExtraNote string, mo *MergeOptions, same func(name string, a, b *T) bool) (M, error) {
+	if master == nil {
+		master = M{}
+	}
+	for _, name := range mergeSortedKeys(extra) {
+		valExtra := extra[name]
+		if valExtra == nil {
+			continue
+		}
+		valMaster, ok := master[name]
+		if !ok || valMaster == nil {
+			master[name] = valExtra
+			continue
+		} else if same(name, valMaster, valExtra) {
+			continue
+		}
+		policy, err := mo.resolve(mergeType, name, specExtraNote)
+		if err != nil {
+			return master, err
+		}
+		switch policy {
+		case MergePolicyKeepLast:
+			master[name] = valExtra
+		case MergePolicyDeepMerge:
+			merged, err := mergeDeepValue(valMaster, valExtra)
+			if err != nil {
+				return master, err
+			}
+			master[name] = merged
+		}
+	}
+	return master, nil
+}
+
+// mergeSortedKeys returns the sorted keys of a map.
+func mergeSortedKeys[M ~map[string]V, V any](m M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mergeDeepValue returns a deep merge of `master` and `extra` using their
+// JSON encoding.
+func mergeDeepValue[T any](master, extra *T) (*T, error) {
+	var docs [2]any
+	for i, v := range []*T{master, extra} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &docs[i]); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(mergeDeep(docs[0], docs[1]))
+	if err != nil {
+		return nil, err
+	}
+	merged := new(T)
+	return merged, json.Unmarshal(data, merged)
+}
+
+// mergeDeep merges decoded JSON. Objects are merged by member, arrays are
+// combined without duplicates and otherwise the `master` value is kept.
+func mergeDeep(master, extra any) any {
+	switch valMaster := master.(type) {
+	case map[string]any:
+		valExtra, ok := extra.(map[string]any)
+		if !ok {
+			return master
+		}
+		for k, v := range valExtra {
+			if vMaster, ok := valMaster[k]; ok {
+				valMaster[k] = mergeDeep(vMaster, v)
+			} else {
+				valMaster[k] = v
+			}
+		}
+	case []any:
+		valExtra, ok := extra.([]any)
+		if !ok {
+			return master
+		}
+	ITEMS:
+		for _, v := range valExtra {
+			for _, vMaster := range valMaster {
+				if reflect.DeepEqual(vMaster, v) {
+					continue ITEMS
+				}
+			}
+			valMaster = append(valMaster, v)
+		}
+		return valMaster
+	}
+	return master
+}
+
+// mergeRenamePrefix returns `MergeOptions.RenamePrefix` or the file name of
+// `specExtraNote` without extension.
+func (mo *MergeOptions) mergeRenamePrefix(specExtraNote string) string {
+	if len(mo.RenamePrefix) > 0 {
+		return mo.RenamePrefix
+	}
+	base := filepath.Base(specExtraNote)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	prefix := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, base)
+	if len(prefix) == 0 {
+		return "Extra"
+	}
+	return prefix
+}
+
+// mergeRenameName returns `prefix` plus `name`, numbered if it is taken.
+func mergeRenameName(prefix, name string, taken func(string) bool) string {
+	newName := prefix + name
+	for i := 2; taken(newName); i++ {
+		newName = prefix + name + strconv.Itoa(i)
+	}
+	return newName
+}
+
+// mergeRenameComponents returns new names for the `extra` components that
+// collide with different `master` components.
+func mergeRenameComponents[M ~map[string]*T, T any](master, extra M, prefix string, same func(name string, a, b *T) bool) map[string]string {
+	renames := map[string]string{}
+	taken := func(name string) bool {
+		_, okMaster := master[name]
+		_, okExtra := extra[name]
+		return okMaster || okExtra
+	}
+	for _, name := range mergeSortedKeys(extra) {
+		valMaster, ok := master[name]
+		if valExtra := extra[name]; ok && valMaster != nil && valExtra != nil && !same(name, valMaster, valExtra) {
+			renames[name] = mergeRenameName(prefix, name, func(s string) bool {
+				for _, renamed := range renames {
+					if s == renamed {
+						return true
+					}
+				}
+				return taken(s)
+			})
+		}
+	}
+	return renames
+}
+
+// mergeRename renames the `specExtra` items that collide under
+// `MergePolicyRenameWithPrefix` and returns an updated copy of `specExtra`,
+// or `specExtra` if there are no renames.
+func mergeRename(specMaster, specExtra *Spec, specExtraNote string, mo *MergeOptions) (*Spec, error) {
+	prefix := mo.mergeRenamePrefix(specExtraNote)
+	renames := map[string]map[string]string{}
+	if specMaster.Components != nil && specExtra.Components != nil {
+		cm, ce := specMaster.Components, specExtra.Components
+		for mergeType, rename := range map[string]func() map[string]string{
+			MergeTypeParameters: func() map[string]string {
+				return mergeRenameComponents(cm.Parameters, ce.Parameters, prefix, mergeSame[oas3.ParameterRef])
+			},
+			MergeTypeRequestBodies: func() map[string]string {
+				return mergeRenameComponents(cm.RequestBodies, ce.RequestBodies, prefix, mergeSame[oas3.RequestBodyRef])
+			},
+			MergeTypeResponses: func() map[string]string {
+				return mergeRenameComponents(cm.Responses, ce.Responses, prefix, mergeSame[oas3.ResponseRef])
+			},
+			MergeTypeSchemas: func() map[string]string {
+				return mergeRenameComponents(cm.Schemas, ce.Schemas, prefix, func(name string, a, b *oas3.SchemaRef) bool {
+					return mo.CheckSchemaCollision(name, a, b, specExtraNote) == CollisionCheckSame
+				})
+			},
+			MergeTypeSecuritySchemes: func() map[string]string {
+				return mergeRenameComponents(cm.SecuritySchemes, ce.SecuritySchemes, prefix, mergeSame[oas3.SecuritySchemeRef])
+			},
+		} {
+			if mo.Policy(mergeType) == MergePolicyRenameWithPrefix {
+				if r := rename(); len(r) > 0 {
+					renames[mergeType] = r
+				}
+			}
+		}
+	}
+	if mo.Policy(MergeTypeTags) == MergePolicyRenameWithPrefix {
+		tagsMaster := map[string]*oas3.Tag{}
+		for _, tag := range specMaster.Tags {
+			if tag != nil {
+				tagsMaster[tag.Name] = tag
+			}
+		}
+		tagsExtra := map[string]*oas3.Tag{}
+		for _, tag := range specExtra.Tags {
+			if tag != nil {
+				tagsExtra[strings.TrimSpace(tag.Name)] = tag
+			}
+		}
+		if r := mergeRenameComponents(tagsMaster, tagsExtra, prefix, mergeSameTag); len(r) > 0 {
+			renames[MergeTypeTags] = r
+		}
+	}
+	if mo.Policy(MergeTypeOperations) == MergePolicyRenameWithPrefix {
+		if r := mergeRenameOperations(specMaster, specExtra, prefix); len(r) > 0 {
+			renames[MergeTypeOperations] = r
+		}
+	}
+	if len(renames) == 0 {
+		return specExtra, nil
+	}
+
+	for _, mergeType := range mergeSortedKeys(renames) {
+		for _, name := range mergeSortedKeys(renames[mergeType]) {
+			mo.Conflicts = append(mo.Conflicts, MergeConflict{
+				Type: mergeType, Name: name, Source: specExtraNote, Policy: MergePolicyRenameWithPrefix,
+				Result: "renamed to " + renames[mergeType][name]})
+		}
+	}
+	data, err := specExtra.MarshalJSON()
+	if err != nil {
+		return specExtra, err
+	}
+	doc := map[string]any{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return specExtra, err
+	}
+	mergeRenameDoc(doc, renames, prefix)
+	if data, err = json.Marshal(doc); err != nil {
+		return specExtra, err
+	}
+	if specRefsLoaded(specExtra) {
+		// Load like the master so merged items compare equal.
+		return oas3.NewLoader().LoadFromData(data)
+	}
+	renamed := &Spec{}
+	return renamed, renamed.UnmarshalJSON(data)
+}
+
+// specRefsLoaded reports whether `spec` was read by `oas3.Loader`, which
+// resolves refs and records the path of each loaded component.
+func specRefsLoaded(spec *Spec) bool {
+	if spec.Components != nil {
+		for _, ref := range spec.Components.Schemas {
+			if ref != nil && ref.RefPath() != nil {
+				return true
+			}
+		}
+		for _, ref := range spec.Components.Parameters {
+			if ref != nil && ref.RefPath() != nil {
+				return true
+			}
+		}
+		for _, ref := range spec.Components.Responses {
+			if ref != nil && ref.RefPath() != nil {
+				return true
+			}
+		}
+		for _, ref := range spec.Components.RequestBodies {
+			if ref != nil && ref.RefPath() != nil {
+				return true
+			}
+		}
+	}
+	loaded := false
+	VisitOperations(spec, func(path, method string, op *oas3.Operation) {
+		if op == nil {
+			return
+		}
+		for _, ref := range op.Parameters {
+			if ref != nil && ref.RefPath() != nil {
+				loaded = true
+			}
+		}
+		if op.RequestBody != nil && op.RequestBody.RefPath() != nil {
+			loaded = true
+		}
+		if op.Responses != nil {
+			for _, ref := range op.Responses.Map() {
+				if ref != nil && ref.RefPath() != nil {
+					loaded = true
+				}
+			}
+		}
+	})
+	return loaded
+}
+
+// mergeRenameOperations returns new paths for the `specExtra` operations
+// that collide with different `specMaster` operations, keyed by
+// `METHOD path`.
+func mergeRenameOperations(specMaster, specExtra *Spec, prefix string) map[string]string {
+	renames := map[string]string{}
+	if specMaster.Paths == nil || specExtra.Paths == nil {
+		return renames
+	}
+	pathsExtra := specExtra.Paths.Map()
+	for _, path := range mergeSortedKeys(pathsExtra) {
+		itemMaster := specMaster.Paths.Find(path)
+		if itemMaster == nil || pathsExtra[path] == nil {
+			continue
+		}
+		for _, method := range httputilmore.Methods() {
+			opExtra := pathsExtra[path].GetOperation(method)
+			opMaster := itemMaster.GetOperation(method)
+			if opExtra == nil || opMaster == nil || reflect.DeepEqual(opMaster, opExtra) {
+				continue
+			}
+			renames[method+" "+path] = mergeRenameName("/"+prefix, path, func(s string) bool {
+				if item := specMaster.Paths.Find(s); item != nil && item.GetOperation(method) != nil {
+					return true
+				} else if item := specExtra.Paths.Find(s); item != nil && item.GetOperation(method) != nil {
+					return true
+				}
+				return false
+			})
+		}
+	}
+	return renames
+}
+
+// mergeRenameDoc applies renames to a decoded spec.
+func mergeRenameDoc(doc map[string]any, renames map[string]map[string]string, prefix string) {
+	if comps, ok := doc[PathComponents].(map[string]any); ok {
+		ptrs := map[string]string{}
+		for mergeType, r := range renames {
+			typeComps, ok := comps[mergeType].(map[string]any)
+			if !ok {
+				continue
+			}
+			for name, newName := range r {
+				if comp, ok := typeComps[name]; ok {
+					delete(typeComps, name)
+					typeComps[newName] = comp
+				}
+				ptrs[ComponentPointer(mergeType, name)] = ComponentPointer(mergeType, newName)
+			}
+		}
+		if len(ptrs) > 0 {
+			mergeRenameRefs(doc, "", ptrs, renames[MergeTypeSchemas])
+		}
+	}
+
+	schemes := renames[MergeTypeSecuritySchemes]
+	tags := renames[MergeTypeTags]
+	if len(tags) > 0 {
+		if tagsDoc, ok := doc["tags"].([]any); ok {
+			for _, tag := range tagsDoc {
+				if tagMap, ok := tag.(map[string]any); ok {
+					if name, ok := tagMap["name"].(string); ok {
+						if newName, ok := tags[strings.TrimSpace(name)]; ok {
+							tagMap["name"] = newName
+						}
+					}
+				}
+			}
+		}
+	}
+	mergeRenameSecurity(doc["security"], schemes)
+	paths, ok := doc["paths"].(map[string]any)
+	if !ok {
+		return
+	}
+	for _, item := range paths {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, method := range httputilmore.Methods() {
+			op, ok := itemMap[strings.ToLower(method)].(map[string]any)
+			if !ok {
+				continue
+			}
+			mergeRenameSecurity(op["security"], schemes)
+			if opTags, ok := op["tags"].([]any); ok {
+				for i, tag := range opTags {
+					if name, ok := tag.(string); ok {
+						if newName, ok := tags[name]; ok {
+							opTags[i] = newName
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, key := range mergeSortedKeys(renames[MergeTypeOperations]) {
+		method, path, _ := strings.Cut(key, " ")
+		newPath := renames[MergeTypeOperations][key]
+		itemMap, ok := paths[path].(map[string]any)
+		if !ok {
+			continue
+		}
+		op, ok := itemMap[strings.ToLower(method)].(map[string]any)
+		if !ok {
+			continue
+		}
+		delete(itemMap, strings.ToLower(method))
+		if opID, ok := op["operationId"].(string); ok && len(opID) > 0 {
+			op["operationId"] = prefix + opID
+		}
+		newItem, ok := paths[newPath].(map[string]any)
+		if !ok {
+			newItem = map[string]any{}
+			for k, v := range itemMap {
+				if !mergeIsMethod(k) {
+					newItem[k] = v
+				}
+			}
+			paths[newPath] = newItem
+		}
+		newItem[strings.ToLower(method)] = op
+		hasOp := false
+		for k := range itemMap {
+			if mergeIsMethod(k) {
+				hasOp = true
+			}
+		}
+		if !hasOp {
+			delete(paths, path)
+		}
+	}
+}
+
+func mergeIsMethod(key string) bool {
+	for _, method := range httputilmore.Methods() {
+		if key == strings.ToLower(method) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeRenameRefs updates `$ref`s and discriminator mappings to renamed
+// components.
+func mergeRenameRefs(node any, key string, ptrs, schemas map[string]string) {
+	rename := func(ref string) string {
+		for ptr, newPtr := range ptrs {
+			if ref == ptr {
+				return newPtr
+			} else if rest, ok := strings.CutPrefix(ref, ptr+"/"); ok {
+				return newPtr + "/" + rest
+			}
+		}
+		return ref
+	}
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			v["$ref"] = rename(ref)
+		}
+		if key == "discriminator" {
+			if mapping, ok := v["mapping"].(map[string]any); ok {
+				for k, target := range mapping {
+					if s, ok := target.(string); ok {
+						if newName, ok := schemas[s]; ok {
+							mapping[k] = newName
+						} else {
+							mapping[k] = rename(s)
+						}
+					}
+				}
+			}
+		}
+		for k, item := range v {
+			mergeRenameRefs(item, k, ptrs, schemas)
+		}
+	case []any:
+		for _, item := range v {
+			mergeRenameRefs(item, "", ptrs, schemas)
+		}
+	}
+}
+
+// mergeRenameSecurity updates security requirements for renamed security
+// schemes.
+func mergeRenameSecurity(security any, schemes map[string]string) {
+	reqs, ok := security.([]any)
+	if !ok || len(schemes) == 0 {
+		return
+	}
+	for _, req := range reqs {
+		if reqMap, ok := req.(map[string]any); ok {
+			for name, newName := range schemes {
+				if scopes, ok := reqMap[name]; ok {
+					delete(reqMap, name)
+					reqMap[newName] = scopes
+				}
+			}
+		}
+	}
+}
+
+func mergeSame[T any](name string, a, b *T) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func mergeSameTag(name string, a, b *oas3.Tag) bool {
+	return a.Description == b.Description && reflect.DeepEqual(a.ExternalDocs, b.ExternalDocs) &&
+		reflect.DeepEqual(a.Extensions, b.Extensions)
+}
@@ -0,0 +1,186 @@
+package openapi3
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const mergeSpecMaster = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"servers":[{"url":"https://api.example.com","description":"Production"}],
+"tags":[{"name":"pets","description":"Pets"}],
+"paths":{
+  "/pets":{"get":{"operationId":"listPets","tags":["pets"],
+    "parameters":[{"$ref":"#/components/parameters/Limit"}],
+    "responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}}}}}},
+"components":{
+  "parameters":{"Limit":{"name":"limit","in":"query","schema":{"type":"integer"}}},
+  "securitySchemes":{"bearer":{"type":"http","scheme":"bearer"}},
+  "schemas":{"Pet":{"type":"object","properties":{"name":{"type":"string"}}}}}}`
+
+const mergeSpecExtra = `{
+"openapi":"3.0.3","info":{"title":"Stores","version":"1.0.0"},
+"servers":[{"url":"https://api.example.com","description":"Stores"}],
+"tags":[{"name":"pets","description":"Store pets"}],
+"security":[{"bearer":[]}],
+"paths":{
+  "/pets":{"get":{"operationId":"listStorePets","tags":["pets"],
+    "parameters":[{"$ref":"#/components/parameters/Limit"}],
+    "responses":{
+      "200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}},
+      "404":{"description":"Not Found"}}}}},
+"components":{
+  "parameters":{"Limit":{"name":"limit","in":"query","schema":{"type":"integer","maximum":100}}},
+  "securitySchemes":{"bearer":{"type":"http","scheme":"bearer","bearerFormat":"JWT"}},
+  "schemas":{"Pet":{"type":"object","properties":{"id":{"type":"integer"}}}}}}`
+
+var mergePolicyTests = []struct {
+	policy      MergePolicy
+	wantSummary string
+}{
+	{MergePolicyKeepFirst, "listPets|name|Production|Pets||1"},
+	{MergePolicyKeepLast, "listStorePets|id|Stores|Store pets|JWT|2"},
+	{MergePolicyDeepMerge, "listPets|id,name|Production|Pets|JWT|2"},
+	{MergePolicyError, ""},
+}
+
+// TestMergePolicies ensures collisions are resolved by the merge policy for
+// each merge type and reported.
+func TestMergePolicies(t *testing.T) {
+	mergeTypes := []string{MergeTypeOperations, MergeTypeParameters, MergeTypeSchemas,
+		MergeTypeSecuritySchemes, MergeTypeServers, MergeTypeTags}
+	for _, tt := range mergePolicyTests {
+		specMaster, specExtra := mergeTestSpecs(t)
+		opts := &MergeOptions{Policies: map[string]MergePolicy{}}
+		for _, mergeType := range mergeTypes {
+			opts.Policies[mergeType] = tt.policy
+		}
+		spec, err := Merge(specMaster, specExtra, "stores.json", opts)
+		if tt.policy == MergePolicyError {
+			if err == nil || len(opts.Conflicts) != 1 {
+				t.Errorf("openapi3.Merge(%s) Mismatch: want error and [1] conflict, got [%v] and [%d]", tt.policy, err, len(opts.Conflicts))
+			}
+			continue
+		} else if err != nil {
+			t.Fatalf("openapi3.Merge(%s) Error [%s]", tt.policy, err.Error())
+		}
+		if got := mergeTestSummary(spec); got != tt.wantSummary {
+			t.Errorf("openapi3.Merge(%s) Mismatch: want [%s], got [%s]", tt.policy, tt.wantSummary, got)
+		}
+		if len(opts.Conflicts) != len(mergeTypes) {
+			t.Errorf("openapi3.Merge(%s) Mismatch: want [%d] conflicts, got [%d]", tt.policy, len(mergeTypes), len(opts.Conflicts))
+		}
+		for _, mc := range opts.Conflicts {
+			if mc.Policy != tt.policy || mc.Source != "stores.json" {
+				t.Errorf("openapi3.Merge(%s) Mismatch: conflict [%v]", tt.policy, mc)
+			}
+		}
+	}
+}
+
+// TestMergeRenameWithPrefix ensures colliding items are renamed and refs to
+// them are updated.
+func TestMergeRenameWithPrefix(t *testing.T) {
+	specMaster, specExtra := mergeTestSpecs(t)
+	opts := &MergeOptions{
+		Policies: map[string]MergePolicy{
+			MergeTypeOperations:      MergePolicyRenameWithPrefix,
+			MergeTypeParameters:      MergePolicyRenameWithPrefix,
+			MergeTypeSchemas:         MergePolicyRenameWithPrefix,
+			MergeTypeSecuritySchemes: MergePolicyRenameWithPrefix,
+			MergeTypeTags:            MergePolicyRenameWithPrefix}}
+	spec, tbls, err := MergeWithTables(specMaster, specExtra, "stores.json", opts)
+	if err != nil {
+		t.Fatalf("openapi3.MergeWithTables() Error [%s]", err.Error())
+	}
+	op := spec.Paths.Find("/stores/pets").Get
+	if op == nil {
+		t.Fatalf("openapi3.MergeWithTables() Mismatch: want renamed path [/stores/pets]")
+	}
+	got := strings.Join([]string{
+		op.OperationID,
+		strings.Join(op.Tags, ","),
+		op.Parameters[0].Ref,
+		op.Responses.Status(200).Value.Content.Get("application/json").Schema.Ref,
+		strings.Join(mergeSortedKeys(spec.Components.Schemas), ","),
+		strings.Join(mergeSortedKeys(spec.Components.SecuritySchemes), ","),
+		spec.Tags[1].Name}, "|")
+	want := "storeslistStorePets|storespets|#/components/parameters/storesLimit|#/components/schemas/storesPet|Pet,storesPet|bearer,storesbearer|storespets"
+	if got != want {
+		t.Errorf("openapi3.MergeWithTables() Mismatch: want [%s], got [%s]", want, got)
+	}
+	if spec.Paths.Find("/pets").Get.OperationID != "listPets" {
+		t.Errorf("openapi3.MergeWithTables() Mismatch: want master operation [listPets], got [%s]", spec.Paths.Find("/pets").Get.OperationID)
+	}
+	if len(tbls) != 4 || tbls[3].Name != "Conflicts" || len(tbls[3].Rows) != 5 {
+		t.Fatalf("openapi3.MergeWithTables() Mismatch: want [4] tables with [5] conflicts, got [%d] tables", len(tbls))
+	}
+	if row := strings.Join(tbls[3].Rows[0], ","); row != "operations,GET /pets,stores.json,rename-with-prefix,renamed to /stores/pets" {
+		t.Errorf("openapi3.MergeWithTables() Mismatch: conflict row got [%s]", row)
+	}
+	if len(spec.Servers) != 1 || spec.Servers[0].Description != "Production" {
+		t.Errorf("openapi3.MergeWithTables() Mismatch: want master servers only without a servers policy, got [%d]", len(spec.Servers))
+	}
+}
+
+func mergeTestSpecs(t *testing.T) (*Spec, *Spec) {
+	t.Helper()
+	specMaster, err := Parse([]byte(mergeSpecMaster))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	specExtra, err := Parse([]byte(mergeSpecExtra))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	return specMaster, specExtra
+}
+
+// mergeTestSummary returns the operation id, `Pet` properties, server
+// description, tag description, bearer format and operation response count.
+func mergeTestSummary(spec *Spec) string {
+	op := spec.Paths.Find("/pets").Get
+	return strings.Join([]string{
+		op.OperationID,
+		strings.Join(mergeSortedKeys(spec.Components.Schemas["Pet"].Value.Properties), ","),
+		spec.Servers[0].Description,
+		spec.Tags[0].Description,
+		spec.Components.SecuritySchemes["bearer"].Value.BearerFormat,
+		strconv.Itoa(op.Responses.Len())}, "|")
+}
+
+// TestMergeFilesRenameLoaded ensures renaming items in a spec read with
+// resolved refs does not report equal items as collisions.
+func TestMergeFilesRenameLoaded(t *testing.T) {
+	dir := t.TempDir()
+	filenames := []string{filepath.Join(dir, "pets.json"), filepath.Join(dir, "stores.json")}
+	extra := strings.Replace(mergeSpecExtra, `"schema":{"type":"integer","maximum":100}`, `"schema":{"type":"integer"}`, 1)
+	for i, data := range []string{mergeSpecMaster, extra} {
+		if err := os.WriteFile(filenames[i], []byte(data), 0600); err != nil {
+			t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+		}
+	}
+	opts := &MergeOptions{
+		ValidateEach:  true,
+		ValidateFinal: true,
+		Policies: map[string]MergePolicy{
+			MergeTypeOperations:      MergePolicyRenameWithPrefix,
+			MergeTypeSchemas:         MergePolicyRenameWithPrefix,
+			MergeTypeSecuritySchemes: MergePolicyRenameWithPrefix}}
+	spec, err := MergeFiles(filenames, opts)
+	if err != nil {
+		t.Fatalf("openapi3.MergeFiles() Error [%s]", err.Error())
+	}
+	got := strings.Join([]string{
+		strings.Join(mergeSortedKeys(spec.Components.Parameters), ","),
+		strings.Join(mergeSortedKeys(spec.Components.Schemas), ","),
+		strings.Join(mergeSortedKeys(spec.Components.SecuritySchemes), ","),
+		spec.Paths.Find("/stores/pets").Get.Parameters[0].Ref}, "|")
+	want := "Limit|Pet,storesPet|bearer,storesbearer|#/components/parameters/Limit"
+	if got != want {
+		t.Errorf("openapi3.MergeFiles() Mismatch: want [%s], got [%s]", want, got)
+	}
+}
@@ -0,0 +1,153 @@
+// openapi3bruno converts OpenAPI 3 specs to Bruno collection directories.
+package openapi3bruno
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	ConfigFilename       = "bruno.json"
+	FolderFilename       = "folder.bru"
+	EnvironmentsDir      = "environments"
+	FileExtension        = ".bru"
+	ConfigVersion        = "1"
+	ConfigTypeCollection = "collection"
+)
+
+// Config is the `bruno.json` collection configuration.
+type Config struct {
+	Version string   `json:"version"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Ignore  []string `json:"ignore,omitempty"`
+}
+
+// File is a collection file with a slash separated path relative to the
+// collection directory.
+type File struct {
+	Path    string
+	Content string
+}
+
+// Collection is a Bruno collection: `bruno.json` plus `.bru` request,
+// folder and environment files.
+type Collection struct {
+	Config Config
+	Files  []File
+}
+
+// WriteDir writes the collection to `dir`, creating folders as needed.
+func (col Collection) WriteDir(dir string, perm os.FileMode) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(col.Config, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ConfigFilename), append(data, '\n'), perm); err != nil {
+		return err
+	}
+	for _, f := range col.Files {
+		filename := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filename, []byte(f.Content), perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Block is a `.bru` block such as `meta`, `headers` or `body:json`. Blocks
+// with `Text` hold raw text. Other blocks hold `key: value` pairs, where
+// disabled pairs are prefixed with `~`, or, with `List`, a list of values.
+type Block struct {
+	Name  string
+	Pairs []Pair
+	Text  string
+	List  bool
+}
+
+// Pair is a block entry.
+type Pair struct {
+	Key      string
+	Value    string
+	Disabled bool
+}
+
+// BlocksString returns the `.bru` file content for blocks.
+func BlocksString(blocks []Block) string {
+	parts := []string{}
+	for _, b := range blocks {
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, "\n")
+}
+
+func (b Block) String() string {
+	var sb strings.Builder
+	if b.List {
+		sb.WriteString(b.Name + " [\n")
+		for _, p := range b.Pairs {
+			sb.WriteString("  " + p.Key + "\n")
+		}
+		sb.WriteString("]\n")
+		return sb.String()
+	}
+	sb.WriteString(b.Name + " {\n")
+	if len(b.Text) > 0 {
+		for _, line := range strings.Split(strings.TrimRight(b.Text, "\n"), "\n") {
+			if len(line) > 0 {
+				sb.WriteString("  " + line)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	for _, p := range b.Pairs {
+		sb.WriteString("  ")
+		if p.Disabled {
+			sb.WriteString("~")
+		}
+		sb.WriteString(strings.TrimRight(p.Key+": "+singleLine(p.Value), " ") + "\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\n", " ")), " ")
+}
+
+var (
+	rxFilenameUnsafe = regexp.MustCompile(`[/\\:*?"<>|]+`)
+	rxPathParameter  = regexp.MustCompile(`/:([A-Za-z0-9_.\-]+)`)
+)
+
+// Filename returns a file or directory name for a request, folder or
+// environment name.
+func Filename(name string) string {
+	name = strings.TrimSpace(rxFilenameUnsafe.ReplaceAllString(name, "-"))
+	name = strings.Trim(name, ".")
+	if len(name) == 0 {
+		return "untitled"
+	}
+	return name
+}
+
+// uniqueName returns `name` or `name-N` so names in a directory do not
+// repeat.
+func uniqueName(used map[string]bool, name string) string {
+	unique := name
+	for i := 2; used[strings.ToLower(unique)]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
@@ -0,0 +1,274 @@
+package openapi3bruno
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/grokify/mogo/net/http/httputilmore"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3/openapi3postman2"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const (
+	BodyTypeNone          = "none"
+	BodyTypeJSON          = "json"
+	BodyTypeXML           = "xml"
+	BodyTypeText          = "text"
+	BodyTypeFormURLEncode = "form-urlencoded"
+	BodyTypeMultipartForm = "multipart-form"
+	BodyTypeGraphQL       = "graphql"
+
+	AuthModeNone   = "none"
+	AuthModeAPIKey = "apikey"
+	AuthModeBasic  = "basic"
+	AuthModeBearer = "bearer"
+	AuthModeOAuth2 = "oauth2"
+)
+
+// ConvertFile reads an OpenAPI 3 spec file and returns a Bruno collection.
+func ConvertFile(cfg openapi3postman2.Configuration, filename string) (Collection, error) {
+	spec, err := openapi3.ReadFile(filename, true)
+	if err != nil {
+		return Collection{}, err
+	}
+	return Convert(cfg, spec)
+}
+
+// Convert returns a Bruno collection for a spec. Requests are built like
+// `openapi3postman2.ConvertSpec()`, so `cfg` server URL, base path and
+// header settings apply. Folders become directories, and unless a server
+// URL is configured, URLs use the `{{baseUrl}}` variable with an
+// environment for each server. Auth is set per request.
+func Convert(cfg openapi3postman2.Configuration, spec *openapi3.Spec) (Collection, error) {
+	cfg = cfg.WithDefaultBaseURLVariable()
+	pman, err := openapi3postman2.ConvertSpec(cfg, spec)
+	if err != nil {
+		return Collection{}, err
+	}
+	col := Collection{
+		Config: Config{
+			Version: ConfigVersion,
+			Name:    pman.Info.Name,
+			Type:    ConfigTypeCollection,
+			Ignore:  []string{"node_modules", ".git"}},
+		Files: []File{}}
+	conv := converter{pman: pman, col: &col}
+	conv.items(pman.Item, "")
+
+	used := map[string]bool{}
+	for _, env := range openapi3postman2.Environments(cfg, spec) {
+		vars := Block{Name: "vars"}
+		secrets := Block{Name: "vars:secret", List: true}
+		for _, val := range env.Values {
+			if val.Type == postman2.EnvironmentValueTypeSecret {
+				secrets.Pairs = append(secrets.Pairs, Pair{Key: val.Key})
+			} else {
+				vars.Pairs = append(vars.Pairs, Pair{Key: val.Key, Value: val.Value})
+			}
+		}
+		blocks := []Block{vars}
+		if len(secrets.Pairs) > 0 {
+			blocks = append(blocks, secrets)
+		}
+		col.Files = append(col.Files, File{
+			Path:    path.Join(EnvironmentsDir, uniqueName(used, Filename(env.Name))+FileExtension),
+			Content: BlocksString(blocks)})
+	}
+	return col, nil
+}
+
+type converter struct {
+	pman postman2.Collection
+	col  *Collection
+}
+
+func (conv *converter) items(items []*postman2.Item, dir string) {
+	used := map[string]bool{}
+	if len(dir) == 0 {
+		used[strings.ToLower(EnvironmentsDir)] = true
+	}
+	seq := 0
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		seq++
+		name := uniqueName(used, Filename(item.DisplayName()))
+		if item.Request == nil {
+			folderDir := path.Join(dir, name)
+			conv.col.Files = append(conv.col.Files, File{
+				Path: path.Join(folderDir, FolderFilename),
+				Content: BlocksString([]Block{{Name: "meta", Pairs: []Pair{
+					{Key: "name", Value: item.Name},
+					{Key: "seq", Value: fmt.Sprint(seq)}}}})})
+			conv.items(item.Item, folderDir)
+			continue
+		}
+		conv.col.Files = append(conv.col.Files, File{
+			Path:    path.Join(dir, name+FileExtension),
+			Content: BlocksString(conv.request(item, seq))})
+	}
+}
+
+func (conv *converter) request(item *postman2.Item, seq int) []Block {
+	req := item.Request
+	blocks := []Block{{Name: "meta", Pairs: []Pair{
+		{Key: "name", Value: item.DisplayName()},
+		{Key: "type", Value: "http"},
+		{Key: "seq", Value: fmt.Sprint(seq)}}}}
+
+	rawURL := ""
+	query := Block{Name: "params:query"}
+	params := Block{Name: "params:path"}
+	if req.URL != nil {
+		rawURL = req.URL.Raw
+		enabled := []string{}
+		for _, q := range req.URL.Query {
+			query.Pairs = append(query.Pairs, Pair{Key: q.Key, Value: q.Value, Disabled: q.Disabled})
+			if !q.Disabled {
+				enabled = append(enabled, q.Key+"="+q.Value)
+			}
+		}
+		if len(enabled) > 0 {
+			rawURL += "?" + strings.Join(enabled, "&")
+		}
+		for _, m := range rxPathParameter.FindAllStringSubmatch(req.URL.Raw, -1) {
+			value := ""
+			for _, v := range req.URL.Variable {
+				if (v.Key == m[1] || v.ID == m[1]) && v.Value != nil {
+					value = fmt.Sprint(v.Value)
+				}
+			}
+			params.Pairs = append(params.Pairs, Pair{Key: m[1], Value: value})
+		}
+	}
+
+	headers := Block{Name: "headers"}
+	contentType := ""
+	for _, h := range req.Header {
+		if strings.EqualFold(h.Key, httputilmore.HeaderContentType) {
+			contentType = h.Value
+			if req.Body != nil && req.Body.Mode == postman2.BodyModeFormData {
+				// Bruno sets the multipart boundary.
+				continue
+			}
+		}
+		headers.Pairs = append(headers.Pairs, Pair{Key: h.Key, Value: h.Value, Disabled: h.Disabled})
+	}
+
+	auth := req.Auth
+	if auth == nil {
+		auth = conv.pman.Auth
+	}
+	authMode, authBlock := AuthBlock(auth)
+	bodyType, bodyBlocks := BodyBlocks(req.Body, contentType)
+
+	blocks = append(blocks, Block{Name: strings.ToLower(req.Method), Pairs: []Pair{
+		{Key: "url", Value: rawURL},
+		{Key: "body", Value: bodyType},
+		{Key: "auth", Value: authMode}}})
+	for _, b := range []Block{query, params, headers} {
+		if len(b.Pairs) > 0 {
+			blocks = append(blocks, b)
+		}
+	}
+	if authBlock != nil {
+		blocks = append(blocks, *authBlock)
+	}
+	blocks = append(blocks, bodyBlocks...)
+	if desc := strings.TrimSpace(req.Description.String()); len(desc) > 0 {
+		blocks = append(blocks, Block{Name: "docs", Text: desc})
+	}
+	return blocks
+}
+
+// BodyBlocks returns the Bruno body type and body blocks for a Postman
+// request body.
+func BodyBlocks(body *postman2.RequestBody, contentType string) (string, []Block) {
+	if body == nil || body.Disabled {
+		return BodyTypeNone, nil
+	}
+	switch body.Mode {
+	case postman2.BodyModeRaw:
+		bodyType := BodyTypeText
+		ct := strings.ToLower(contentType)
+		if strings.Contains(ct, "json") {
+			bodyType = BodyTypeJSON
+		} else if strings.Contains(ct, "xml") {
+			bodyType = BodyTypeXML
+		}
+		return bodyType, []Block{{Name: "body:" + bodyType, Text: body.Raw}}
+	case postman2.BodyModeURLEncoded:
+		b := Block{Name: "body:" + BodyTypeFormURLEncode}
+		for _, p := range body.URLEncoded {
+			b.Pairs = append(b.Pairs, Pair{Key: p.Key, Value: p.Value, Disabled: p.Disabled})
+		}
+		return BodyTypeFormURLEncode, []Block{b}
+	case postman2.BodyModeFormData:
+		b := Block{Name: "body:" + BodyTypeMultipartForm}
+		for _, p := range body.FormData {
+			value := p.Value
+			if p.Type == postman2.FormDataTypeFile {
+				src, _ := p.Src.(string)
+				value = "@file(" + src + ")"
+			}
+			b.Pairs = append(b.Pairs, Pair{Key: p.Key, Value: value, Disabled: p.Disabled})
+		}
+		return BodyTypeMultipartForm, []Block{b}
+	case postman2.BodyModeGraphQL:
+		if body.GraphQL == nil {
+			return BodyTypeNone, nil
+		}
+		blocks := []Block{{Name: "body:graphql", Text: body.GraphQL.Query}}
+		if vars := strings.TrimSpace(body.GraphQL.Variables); len(vars) > 0 {
+			blocks = append(blocks, Block{Name: "body:graphql:vars", Text: vars})
+		}
+		return BodyTypeGraphQL, blocks
+	}
+	return BodyTypeNone, nil
+}
+
+// AuthBlock returns the Bruno auth mode and auth block for Postman auth.
+// Postman auth types without a Bruno equivalent use mode `none`.
+func AuthBlock(auth *postman2.Auth) (string, *Block) {
+	if auth == nil {
+		return AuthModeNone, nil
+	}
+	pairs := func(keys ...string) []Pair {
+		ps := []Pair{}
+		for i := 0; i+1 < len(keys); i += 2 {
+			ps = append(ps, Pair{Key: keys[i], Value: auth.AttributeString(keys[i+1])})
+		}
+		return ps
+	}
+	switch auth.Type {
+	case postman2.AuthTypeBearer:
+		return AuthModeBearer, &Block{Name: "auth:bearer", Pairs: pairs("token", "token")}
+	case postman2.AuthTypeBasic:
+		return AuthModeBasic, &Block{Name: "auth:basic", Pairs: pairs("username", "username", "password", "password")}
+	case postman2.AuthTypeAPIKey:
+		placement := "header"
+		if auth.AttributeString("in") == openapi3.InQuery {
+			placement = "queryparams"
+		}
+		b := &Block{Name: "auth:apikey", Pairs: pairs("key", "key", "value", "value")}
+		b.Pairs = append(b.Pairs, Pair{Key: "placement", Value: placement})
+		return AuthModeAPIKey, b
+	case postman2.AuthTypeOAuth2:
+		b := &Block{Name: "auth:oauth2", Pairs: pairs("grant_type", "grant_type")}
+		if auth.AttributeString("grant_type") == "authorization_code" {
+			b.Pairs = append(b.Pairs, pairs(
+				"callback_url", "redirect_uri",
+				"authorization_url", "authUrl")...)
+		}
+		b.Pairs = append(b.Pairs, pairs(
+			"access_token_url", "accessTokenUrl",
+			"client_id", "clientId",
+			"client_secret", "clientSecret",
+			"scope", "scope")...)
+		return AuthModeOAuth2, b
+	}
+	return AuthModeNone, nil
+}
@@ -0,0 +1,255 @@
+package openapi3bruno
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3/openapi3postman2"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const convertSpec = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"servers":[{"url":"https://api.example.com/v1","description":"Production"}],
+"security":[{"bearerAuth":[]}],
+"paths":{
+  "/pets/{petId}":{"get":{"operationId":"getPet","summary":"Get pet","tags":["pets"],
+    "parameters":[
+      {"name":"petId","in":"path","required":true,"schema":{"type":"string","example":"42"}},
+      {"name":"fields","in":"query","schema":{"type":"string"},"example":"name"}],
+    "responses":{"200":{"description":"OK"}}}},
+  "/pets":{"post":{"operationId":"createPet","summary":"Create pet","tags":["pets"],"security":[{"apiKeyQuery":[]}],
+    "requestBody":{"content":{"application/json":{"example":{"name":"Tom"}}}},
+    "responses":{"201":{"description":"Created"}}}},
+  "/status":{"get":{"operationId":"getStatus","summary":"Status","security":[],
+    "responses":{"200":{"description":"OK"}}}}},
+"components":{"securitySchemes":{
+  "bearerAuth":{"type":"http","scheme":"bearer"},
+  "apiKeyQuery":{"type":"apiKey","in":"query","name":"api_key"}}}}`
+
+var convertTests = []struct {
+	path    string
+	content string
+}{
+	{"pets/folder.bru", `meta {
+  name: pets
+  seq: 1
+}
+`},
+	{"pets/Create pet.bru", `meta {
+  name: Create pet
+  type: http
+  seq: 1
+}
+
+post {
+  url: {{baseUrl}}/pets
+  body: json
+  auth: apikey
+}
+
+headers {
+  Content-Type: application/json
+}
+
+auth:apikey {
+  key: api_key
+  value: {{apiKeyQuery_apiKey}}
+  placement: queryparams
+}
+
+body:json {
+  {
+    "name": "Tom"
+  }
+}
+`},
+	{"pets/Get pet.bru", `meta {
+  name: Get pet
+  type: http
+  seq: 2
+}
+
+get {
+  url: {{baseUrl}}/pets/:petId
+  body: none
+  auth: bearer
+}
+
+params:query {
+  ~fields: <string>
+}
+
+params:path {
+  petId:
+}
+
+auth:bearer {
+  token: {{bearerAuth_token}}
+}
+`},
+	{"Status.bru", `meta {
+  name: Status
+  type: http
+  seq: 2
+}
+
+get {
+  url: {{baseUrl}}/status
+  body: none
+  auth: none
+}
+`},
+	{"environments/Pets - Production.bru", `vars {
+  baseUrl: https://api.example.com/v1
+}
+
+vars:secret [
+  apiKeyQuery_apiKey
+  bearerAuth_token
+]
+`},
+}
+
+// TestConvert ensures folders, requests, path and query parameters, auth,
+// JSON bodies and environments match golden `.bru` output.
+func TestConvert(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(convertSpec))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	col, err := Convert(openapi3postman2.Configuration{}, spec)
+	if err != nil {
+		t.Fatalf("openapi3bruno.Convert() Error [%s]", err.Error())
+	}
+	if col.Config.Name != "Pets" || col.Config.Type != ConfigTypeCollection {
+		t.Errorf("openapi3bruno.Convert() Config Mismatch: got [%v]", col.Config)
+	}
+	if len(col.Files) != len(convertTests) {
+		t.Fatalf("openapi3bruno.Convert() Files Mismatch: want [%d], got [%d]", len(convertTests), len(col.Files))
+	}
+	for i, tt := range convertTests {
+		if col.Files[i].Path != tt.path || col.Files[i].Content != tt.content {
+			t.Errorf("openapi3bruno.Convert() Mismatch at [%d]: want [%s] [%s], got [%s] [%s]",
+				i, tt.path, tt.content, col.Files[i].Path, col.Files[i].Content)
+		}
+	}
+}
+
+// TestConvertFileServerURL ensures a configured server URL replaces the
+// `{{baseUrl}}` variable and the collection is written to a directory.
+func TestConvertFileServerURL(t *testing.T) {
+	dir := t.TempDir()
+	specFile := filepath.Join(dir, "spec.json")
+	if err := os.WriteFile(specFile, []byte(convertSpec), 0600); err != nil {
+		t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+	}
+	col, err := ConvertFile(openapi3postman2.Configuration{PostmanServerURL: "https://sandbox.example.com"}, specFile)
+	if err != nil {
+		t.Fatalf("openapi3bruno.ConvertFile() Error [%s]", err.Error())
+	}
+	outDir := filepath.Join(dir, "collection")
+	if err := col.WriteDir(outDir, 0600); err != nil {
+		t.Fatalf("openapi3bruno.Collection.WriteDir() Error [%s]", err.Error())
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "Status.bru"))
+	if err != nil {
+		t.Fatalf("os.ReadFile() Error [%s]", err.Error())
+	}
+	if !strings.Contains(string(data), "url: https://sandbox.example.com/status\n") || strings.Contains(string(data), "{{baseUrl}}") {
+		t.Errorf("openapi3bruno.ConvertFile() Mismatch: want server URL override, got [%s]", string(data))
+	}
+	if _, err := os.Stat(filepath.Join(outDir, ConfigFilename)); err != nil {
+		t.Errorf("openapi3bruno.Collection.WriteDir() Mismatch: want [%s] Error [%s]", ConfigFilename, err.Error())
+	}
+}
+
+var bodyBlocksTests = []struct {
+	body        *postman2.RequestBody
+	contentType string
+	want        string
+}{
+	{&postman2.RequestBody{Mode: postman2.BodyModeRaw, Raw: `{"name":"Tom"}`}, "application/json",
+		"json\nbody:json {\n  {\"name\":\"Tom\"}\n}\n"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeRaw, Raw: "<pet/>"}, "application/xml; charset=utf-8",
+		"xml\nbody:xml {\n  <pet/>\n}\n"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeRaw, Raw: "hello"}, "",
+		"text\nbody:text {\n  hello\n}\n"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeURLEncoded, URLEncoded: []postman2.URLEncodedParam{
+		{Key: "name", Value: "Tom"}, {Key: "age", Value: "3", Disabled: true}}}, "",
+		"form-urlencoded\nbody:form-urlencoded {\n  name: Tom\n  ~age: 3\n}\n"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeFormData, FormData: []postman2.FormDataParam{
+		{Key: "name", Value: "Tom", Type: postman2.FormDataTypeText},
+		{Key: "photo", Type: postman2.FormDataTypeFile, Src: "./photo.png"}}}, "",
+		"multipart-form\nbody:multipart-form {\n  name: Tom\n  photo: @file(./photo.png)\n}\n"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeGraphQL, GraphQL: &postman2.RequestBodyGraphQL{
+		Query: "query { pets { name } }", Variables: `{"limit": 10}`}}, "",
+		"graphql\nbody:graphql {\n  query { pets { name } }\n}\n\nbody:graphql:vars {\n  {\"limit\": 10}\n}\n"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeFile, File: &postman2.RequestBodyFile{Src: "./pet.bin"}}, "",
+		"none\n"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeRaw, Raw: "hello", Disabled: true}, "", "none\n"},
+	{nil, "", "none\n"},
+}
+
+// TestBodyBlocks ensures each Postman body mode maps to its Bruno body
+// type and block.
+func TestBodyBlocks(t *testing.T) {
+	for i, tt := range bodyBlocksTests {
+		bodyType, blocks := BodyBlocks(tt.body, tt.contentType)
+		if got := bodyType + "\n" + BlocksString(blocks); got != tt.want {
+			t.Errorf("openapi3bruno.BodyBlocks() Mismatch at [%d]: want [%s], got [%s]", i, tt.want, got)
+		}
+	}
+}
+
+var authBlockTests = []struct {
+	auth *postman2.Auth
+	want string
+}{
+	{postman2.NewAuth(postman2.AuthTypeBearer, postman2.NewAuthAttribute("token", "{{token}}")),
+		"bearer\nauth:bearer {\n  token: {{token}}\n}\n"},
+	{postman2.NewAuth(postman2.AuthTypeBasic,
+		postman2.NewAuthAttribute("username", "{{user}}"), postman2.NewAuthAttribute("password", "{{pass}}")),
+		"basic\nauth:basic {\n  username: {{user}}\n  password: {{pass}}\n}\n"},
+	{postman2.NewAuth(postman2.AuthTypeAPIKey, postman2.NewAuthAttribute("key", "X-API-Key"),
+		postman2.NewAuthAttribute("value", "{{apiKey}}"), postman2.NewAuthAttribute("in", "header")),
+		"apikey\nauth:apikey {\n  key: X-API-Key\n  value: {{apiKey}}\n  placement: header\n}\n"},
+	{postman2.NewAuth(postman2.AuthTypeAPIKey, postman2.NewAuthAttribute("key", "api_key"),
+		postman2.NewAuthAttribute("value", "{{apiKey}}"), postman2.NewAuthAttribute("in", "query")),
+		"apikey\nauth:apikey {\n  key: api_key\n  value: {{apiKey}}\n  placement: queryparams\n}\n"},
+	{postman2.NewAuth(postman2.AuthTypeOAuth2,
+		postman2.NewAuthAttribute("grant_type", "authorization_code"),
+		postman2.NewAuthAttribute("authUrl", "https://auth.example.com/authorize"),
+		postman2.NewAuthAttribute("redirect_uri", "{{redirectUri}}"),
+		postman2.NewAuthAttribute("accessTokenUrl", "https://auth.example.com/token"),
+		postman2.NewAuthAttribute("clientId", "{{clientId}}"),
+		postman2.NewAuthAttribute("clientSecret", "{{clientSecret}}"),
+		postman2.NewAuthAttribute("scope", "read write")),
+		"oauth2\nauth:oauth2 {\n  grant_type: authorization_code\n  callback_url: {{redirectUri}}\n  authorization_url: https://auth.example.com/authorize\n  access_token_url: https://auth.example.com/token\n  client_id: {{clientId}}\n  client_secret: {{clientSecret}}\n  scope: read write\n}\n"},
+	{postman2.NewAuth(postman2.AuthTypeOAuth2,
+		postman2.NewAuthAttribute("grant_type", "client_credentials"),
+		postman2.NewAuthAttribute("accessTokenUrl", "https://auth.example.com/token"),
+		postman2.NewAuthAttribute("clientId", "{{clientId}}"),
+		postman2.NewAuthAttribute("clientSecret", "{{clientSecret}}")),
+		"oauth2\nauth:oauth2 {\n  grant_type: client_credentials\n  access_token_url: https://auth.example.com/token\n  client_id: {{clientId}}\n  client_secret: {{clientSecret}}\n  scope:\n}\n"},
+	{&postman2.Auth{Type: postman2.AuthTypeNoAuth}, "none\n"},
+	{nil, "none\n"},
+}
+
+// TestAuthBlock ensures bearer, basic, API key and OAuth 2.0 auth map to
+// Bruno auth modes and blocks.
+func TestAuthBlock(t *testing.T) {
+	for i, tt := range authBlockTests {
+		mode, block := AuthBlock(tt.auth)
+		got := mode + "\n"
+		if block != nil {
+			got += block.String()
+		}
+		if got != tt.want {
+			t.Errorf("openapi3bruno.AuthBlock() Mismatch at [%d]: want [%s], got [%s]", i, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package openapi3bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grokify/spectrum/openapi3"
+)
+
+// Bundle is a spec bundled from files with relative `$ref`s.
+type Bundle struct {
+	Document map[string]any
+	// Renamed maps the source of each component renamed to avoid a name
+	// collision, as `file#pointer` relative to the root file, to its name.
+	Renamed map[string]string
+}
+
+// Spec returns the bundled spec.
+func (b Bundle) Spec() (*openapi3.Spec, error) {
+	return DocumentSpec(b.Document)
+}
+
+// WriteFile writes the bundled spec as YAML or JSON based on the filename
+// extension.
+func (b Bundle) WriteFile(filename string, perm os.FileMode) error {
+	return WriteDocument(filename, b.Document, perm)
+}
+
+// BundleFile reads a spec file and resolves refs to other files into local
+// refs. Refs to components, e.g. `common.yaml#/components/schemas/Error`,
+// and refs to whole files in a component position, e.g. a schema
+// `schemas/Pet.yaml`, become components named by the component or file
+// name. When that name is taken by a different source, a number is
+// appended. Components in the root file that ref a whole file keep their
+// names. Other refs, such as path items, are inlined. Remote `http(s)`
+// refs are kept.
+func BundleFile(filename string) (Bundle, error) {
+	root, err := filepath.Abs(filename)
+	if err != nil {
+		return Bundle{}, err
+	}
+	b := &bundler{
+		root:     root,
+		docs:     map[string]map[string]any{},
+		names:    map[string]string{},
+		used:     map[string]map[string]string{},
+		loaded:   map[string]bool{},
+		inlining: map[string]bool{},
+		added:    map[string]map[string]any{},
+		renamed:  map[string]string{}}
+	doc, err := b.document(root)
+	if err != nil {
+		return Bundle{}, err
+	}
+	doc = copyValue(doc).(map[string]any)
+	if err := b.reserve(doc); err != nil {
+		return Bundle{}, err
+	}
+	out, err := b.walk(doc, root, []string{})
+	if err != nil {
+		return Bundle{}, err
+	}
+	doc = out.(map[string]any)
+	if len(b.added) > 0 {
+		comps, ok := doc[openapi3.PathComponents].(map[string]any)
+		if !ok {
+			comps = map[string]any{}
+			doc[openapi3.PathComponents] = comps
+		}
+		for kind, values := range b.added {
+			typeComps, ok := comps[kind].(map[string]any)
+			if !ok {
+				typeComps = map[string]any{}
+				comps[kind] = typeComps
+			}
+			for name, v := range values {
+				typeComps[name] = v
+			}
+		}
+	}
+	return Bundle{Document: doc, Renamed: b.renamed}, nil
+}
+
+type bundler struct {
+	root     string
+	docs     map[string]map[string]any
+	names    map[string]string            // source -> `#/components/<type>/<name>`
+	used     map[string]map[string]string // type -> name -> source
+	loaded   map[string]bool              // sources added to components
+	inlining map[string]bool              // sources being inlined
+	added    map[string]map[string]any    // type -> name -> bundled component
+	renamed  map[string]string
+}
+
+func (b *bundler) document(filename string) (map[string]any, error) {
+	if doc, ok := b.docs[filename]; ok {
+		return doc, nil
+	}
+	doc, err := ReadDocument(filename)
+	if err != nil {
+		return nil, err
+	}
+	b.docs[filename] = doc
+	return doc, nil
+}
+
+func sourceKey(file string, pointer []string) string {
+	return file + "#" + pointerString(pointer)
+}
+
+// reserve registers root component names. Root components that ref
+// another file keep their name for that file.
+func (b *bundler) reserve(doc map[string]any) error {
+	comps, _ := doc[openapi3.PathComponents].(map[string]any)
+	for _, kind := range sortedKeys(comps) {
+		typeComps, ok := comps[kind].(map[string]any)
+		if !ok || !isComponentType(kind) {
+			continue
+		}
+		if b.used[kind] == nil {
+			b.used[kind] = map[string]string{}
+		}
+		for _, name := range sortedKeys(typeComps) {
+			src := sourceKey(b.root, []string{openapi3.PathComponents, kind, name})
+			if m, ok := typeComps[name].(map[string]any); ok {
+				if ref, ok := m[Ref].(string); ok && !strings.HasPrefix(ref, "#") {
+					file, pointer, err := splitRef(ref)
+					if err != nil {
+						return err
+					}
+					if !isRemoteRef(file) {
+						src = sourceKey(filepath.Join(filepath.Dir(b.root), filepath.FromSlash(file)), pointer)
+					}
+				}
+			}
+			b.used[kind][name] = src
+			if _, ok := b.names[src]; !ok {
+				b.names[src] = "#" + escapeFragment(pointerString([]string{openapi3.PathComponents, kind, name}))
+			}
+		}
+	}
+	return nil
+}
+
+func (b *bundler) walk(node any, file string, ctx []string) (any, error) {
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v[Ref].(string); ok {
+			return b.ref(v, ref, file, ctx)
+		}
+		for _, k := range sortedKeys(v) {
+			out, err := b.walk(v[k], file, append(ctx[:len(ctx):len(ctx)], k))
+			if err != nil {
+				return nil, err
+			}
+			v[k] = out
+		}
+		return v, nil
+	case []any:
+		for i := range v {
+			out, err := b.walk(v[i], file, append(ctx[:len(ctx):len(ctx)], fmt.Sprint(i)))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = out
+		}
+		return v, nil
+	}
+	return node, nil
+}
+
+func (b *bundler) ref(m map[string]any, ref, file string, ctx []string) (any, error) {
+	refFile, pointer, err := splitRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	if isRemoteRef(refFile) {
+		return m, nil
+	}
+	target := file
+	if len(refFile) > 0 {
+		target = filepath.Join(filepath.Dir(file), filepath.FromSlash(refFile))
+	}
+	siblings, err := b.siblings(m, file, ctx)
+	if err != nil {
+		return nil, err
+	}
+	if target == b.root {
+		if len(refFile) == 0 {
+			return refObject(ref, siblings), nil
+		}
+		return refObject("#"+escapeFragment(pointerString(pointer)), siblings), nil
+	}
+	src := sourceKey(target, pointer)
+	if local, ok := b.names[src]; ok && b.loaded[src] {
+		return refObject(local, siblings), nil
+	}
+	if local, ok := b.names[sourceKey(target, nil)]; ok && len(pointer) > 0 {
+		// A ref into a file already bundled as a component.
+		return refObject(local+escapeFragment(pointerString(pointer)), siblings), nil
+	}
+
+	kind, name := "", ""
+	if len(pointer) == 3 && pointer[0] == openapi3.PathComponents && isComponentType(pointer[1]) {
+		kind, name = pointer[1], pointer[2]
+	} else {
+		kind = refKind(ctx)
+		if len(pointer) > 0 {
+			name = pointer[len(pointer)-1]
+		} else {
+			name = strings.TrimSuffix(filepath.Base(target), filepath.Ext(target))
+		}
+	}
+
+	doc, err := b.document(target)
+	if err != nil {
+		return nil, err
+	}
+	val, ok := resolvePointer(doc, pointer)
+	if !ok {
+		return nil, fmt.Errorf("cannot resolve $ref [%s] in [%s]", ref, file)
+	}
+
+	if len(kind) == 0 || kind == "pathItems" {
+		if b.inlining[src] {
+			return nil, fmt.Errorf("circular $ref [%s] in [%s] cannot be inlined", ref, file)
+		}
+		b.inlining[src] = true
+		out, err := b.walk(copyValue(val), target, ctx)
+		delete(b.inlining, src)
+		if err != nil {
+			return nil, err
+		}
+		if outMap, ok := out.(map[string]any); ok {
+			for k, v := range siblings {
+				outMap[k] = v
+			}
+		}
+		return out, nil
+	}
+
+	local, ok := b.names[src]
+	if !ok {
+		name = b.uniqueName(kind, name, src)
+		local = "#" + escapeFragment(pointerString([]string{openapi3.PathComponents, kind, name}))
+		b.names[src] = local
+	} else if _, localPointer, err := splitRef(local); err == nil && len(localPointer) == 3 {
+		name = localPointer[2]
+	}
+	b.loaded[src] = true
+	out, err := b.walk(copyValue(val), target, []string{openapi3.PathComponents, kind, name})
+	if err != nil {
+		return nil, err
+	}
+	if b.added[kind] == nil {
+		b.added[kind] = map[string]any{}
+	}
+	b.added[kind][name] = out
+	return refObject(local, siblings), nil
+}
+
+// siblings returns the walked members of a ref object other than `$ref`.
+func (b *bundler) siblings(m map[string]any, file string, ctx []string) (map[string]any, error) {
+	siblings := map[string]any{}
+	for k, v := range m {
+		if k == Ref {
+			continue
+		}
+		out, err := b.walk(v, file, append(ctx[:len(ctx):len(ctx)], k))
+		if err != nil {
+			return nil, err
+		}
+		siblings[k] = out
+	}
+	return siblings, nil
+}
+
+var rxComponentNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (b *bundler) uniqueName(kind, name, src string) string {
+	name = rxComponentNameUnsafe.ReplaceAllString(name, "_")
+	if b.used[kind] == nil {
+		b.used[kind] = map[string]string{}
+	}
+	unique := name
+	for i := 2; len(b.used[kind][unique]) > 0; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	b.used[kind][unique] = src
+	if unique != name {
+		file, pointer, _ := strings.Cut(src, "#")
+		if rel, err := filepath.Rel(filepath.Dir(b.root), file); err == nil {
+			file = filepath.ToSlash(rel)
+		}
+		b.renamed[file+"#"+pointer] = unique
+	}
+	return unique
+}
+
+var schemaKeys = map[string]bool{
+	"schema": true, "items": true, "additionalProperties": true, "not": true,
+	"allOf": true, "anyOf": true, "oneOf": true, "prefixItems": true,
+	"contains": true, "if": true, "then": true, "else": true,
+	"propertyNames": true, "unevaluatedItems": true, "unevaluatedProperties": true}
+
+var schemaMapKeys = map[string]bool{
+	"properties": true, "patternProperties": true, "$defs": true,
+	"definitions": true, "dependentSchemas": true}
+
+var refKinds = map[string]string{
+	"parameters":      "parameters",
+	"responses":       "responses",
+	"requestBody":     "requestBodies",
+	"headers":         "headers",
+	"examples":        "examples",
+	"links":           "links",
+	"callbacks":       "callbacks",
+	"securitySchemes": "securitySchemes",
+	"paths":           "pathItems"}
+
+// refKind returns the component type for a ref at a root document
+// location, or an empty string when the ref should be inlined.
+func refKind(ctx []string) string {
+	for i := len(ctx) - 1; i >= 0; i-- {
+		if i > 0 {
+			prev := ctx[i-1]
+			if schemaMapKeys[prev] {
+				return "schemas"
+			}
+			if prev == openapi3.PathComponents && i == 1 && isComponentType(ctx[i]) {
+				return ctx[i]
+			}
+		}
+		if schemaKeys[ctx[i]] {
+			return "schemas"
+		}
+		if kind, ok := refKinds[ctx[i]]; ok {
+			return kind
+		}
+		if ctx[i] == "content" || ctx[i] == "example" {
+			return ""
+		}
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,138 @@
+package openapi3bundle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var bundleTestFiles = map[string]string{
+	"openapi.yaml": `openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    $ref: paths/pets.yaml
+  /pets/{petId}:
+    get:
+      parameters:
+        - $ref: common.yaml#/components/parameters/PetId
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema: {$ref: schemas/Pet.yaml}
+        default: {$ref: 'common.yaml#/components/responses/Error'}
+components:
+  schemas:
+    Error:
+      type: object
+      properties: {message: {type: string}}
+`,
+	"paths/pets.yaml": `get:
+  responses:
+    '200':
+      description: OK
+      content:
+        application/json:
+          schema: {type: array, items: {$ref: ../schemas/Pet.yaml}}
+`,
+	"schemas/Pet.yaml": `type: object
+properties:
+  id: {type: integer, maximum: 9007199254740993}
+  owner: {$ref: Owner.yaml}
+  children: {type: array, items: {$ref: Pet.yaml}}
+`,
+	"schemas/Owner.yaml": `type: object
+properties: {name: {type: string}}
+`,
+	"common.yaml": `components:
+  parameters:
+    PetId: {name: petId, in: path, required: true, schema: {type: integer}}
+  responses:
+    Error:
+      description: Error
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Error'}
+  schemas:
+    Error:
+      type: object
+      properties: {code: {type: integer}}
+`}
+
+var bundleRefTests = []struct {
+	pointer string
+	want    string
+}{
+	{"/paths/~1pets/get/responses/200/content/application~1json/schema/items/$ref", "#/components/schemas/Pet"},
+	{"/paths/~1pets~1{petId}/get/parameters/0/$ref", "#/components/parameters/PetId"},
+	{"/paths/~1pets~1{petId}/get/responses/default/$ref", "#/components/responses/Error"},
+	{"/components/responses/Error/content/application~1json/schema/$ref", "#/components/schemas/Error2"},
+	{"/components/schemas/Pet/properties/owner/$ref", "#/components/schemas/Owner"},
+	{"/components/schemas/Pet/properties/children/items/$ref", "#/components/schemas/Pet"},
+}
+
+// TestBundleFile ensures file refs become local component refs with name
+// collisions renamed, and that split files bundle to the same spec.
+func TestBundleFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range bundleTestFiles {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bundle, err := BundleFile(filepath.Join(dir, "openapi.yaml"))
+	if err != nil {
+		t.Fatalf("openapi3bundle.BundleFile() Error [%s]", err.Error())
+	}
+	for _, tt := range bundleRefTests {
+		got, ok := resolvePointer(bundle.Document, parsePointer(tt.pointer))
+		if !ok || got != tt.want {
+			t.Errorf("openapi3bundle.BundleFile() Mismatch: pointer [%s] want [%v], got [%v]", tt.pointer, tt.want, got)
+		}
+	}
+	if want := map[string]string{"common.yaml#/components/schemas/Error": "Error2"}; !reflect.DeepEqual(bundle.Renamed, want) {
+		t.Errorf("openapi3bundle.BundleFile() Mismatch: renamed want [%v], got [%v]", want, bundle.Renamed)
+	}
+	spec, err := bundle.Spec()
+	if err != nil {
+		t.Fatalf("openapi3bundle.Bundle.Spec() Error [%s]", err.Error())
+	}
+	if err := spec.Validate(context.Background()); err != nil {
+		t.Errorf("openapi3bundle.Bundle.Spec() Validate Error [%s]", err.Error())
+	}
+
+	splitDir := filepath.Join(dir, "split")
+	files := Split(bundle.Document, "openapi.yaml")
+	paths := []string{}
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	sort.Strings(paths)
+	wantPaths := "components/parameters/PetId.yaml,components/responses/Error.yaml," +
+		"components/schemas/Error.yaml,components/schemas/Error2.yaml,components/schemas/Owner.yaml," +
+		"components/schemas/Pet.yaml,openapi.yaml,paths/pets.yaml,paths/pets@{petId}.yaml"
+	if got := strings.Join(paths, ","); got != wantPaths {
+		t.Errorf("openapi3bundle.Split() Mismatch: want [%s], got [%s]", wantPaths, got)
+	}
+	if err := WriteFiles(splitDir, files, 0600); err != nil {
+		t.Fatalf("openapi3bundle.WriteFiles() Error [%s]", err.Error())
+	}
+	rebundle, err := BundleFile(filepath.Join(splitDir, "openapi.yaml"))
+	if err != nil {
+		t.Fatalf("openapi3bundle.BundleFile() Error [%s]", err.Error())
+	}
+	if !reflect.DeepEqual(rebundle.Document, bundle.Document) {
+		t.Errorf("openapi3bundle.BundleFile() Mismatch: split and bundled spec differs from original")
+	}
+}
@@ -0,0 +1,212 @@
+// openapi3bundle bundles OpenAPI 3 specs split across files with relative
+// `$ref`s into a single spec, and splits a spec into such files.
+package openapi3bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/spectrum/openapi3"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	ComponentsDir = "components"
+	PathsDir      = "paths"
+	PathsMember   = "paths"
+	Ref           = "$ref"
+)
+
+// ComponentTypes are the `components` members that are bundled and split.
+var ComponentTypes = []string{
+	"schemas", "responses", "parameters", "examples", "requestBodies",
+	"headers", "securitySchemes", "links", "callbacks", "pathItems"}
+
+func isComponentType(s string) bool {
+	for _, t := range ComponentTypes {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
+
+var rxYAMLExtension = regexp.MustCompile(`(?i)\.ya?ml$`)
+
+// ReadDocument reads a JSON or YAML file. Numbers are kept as
+// `json.Number` so they are written unchanged.
+func ReadDocument(filename string) (map[string]any, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDocument(data)
+}
+
+// ParseDocument parses JSON or YAML bytes.
+func ParseDocument(data []byte) (map[string]any, error) {
+	if !json.Valid(data) {
+		var err error
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+	doc := map[string]any{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// SpecDocument returns the document for a spec.
+func SpecDocument(spec *openapi3.Spec) (map[string]any, error) {
+	data, err := spec.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return ParseDocument(data)
+}
+
+// DocumentSpec returns the spec for a document with local refs resolved.
+func DocumentSpec(doc map[string]any) (*openapi3.Spec, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return oas3.NewLoader().LoadFromData(data)
+}
+
+// DocumentBytes returns YAML for `.yaml` and `.yml` filenames and indented
+// JSON otherwise.
+func DocumentBytes(filename string, doc any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if rxYAMLExtension.MatchString(filename) {
+		return yaml.JSONToYAML(buf.Bytes())
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteDocument writes a document as YAML or JSON based on the filename
+// extension, creating directories as needed.
+func WriteDocument(filename string, doc any, perm os.FileMode) error {
+	data, err := DocumentBytes(filename, doc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, perm)
+}
+
+// splitRef returns the file and JSON pointer of a `$ref`. The file is empty
+// for local refs.
+func splitRef(ref string) (string, []string, error) {
+	file, fragment, _ := strings.Cut(ref, "#")
+	fragment, err := url.PathUnescape(fragment)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid $ref [%s]: %w", ref, err)
+	}
+	if len(fragment) > 0 && !strings.HasPrefix(fragment, "/") {
+		return "", nil, fmt.Errorf("unsupported $ref fragment [%s]", ref)
+	}
+	return file, parsePointer(fragment), nil
+}
+
+func parsePointer(s string) []string {
+	if len(s) == 0 {
+		return []string{}
+	}
+	parts := strings.Split(strings.TrimPrefix(s, "/"), "/")
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(strings.ReplaceAll(p, "~1", "/"), "~0", "~")
+	}
+	return parts
+}
+
+func pointerString(parts []string) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		sb.WriteString("/" + strings.ReplaceAll(strings.ReplaceAll(p, "~", "~0"), "/", "~1"))
+	}
+	return sb.String()
+}
+
+// resolvePointer returns the value at a JSON pointer.
+func resolvePointer(doc any, pointer []string) (any, bool) {
+	cur := doc
+	for _, p := range pointer {
+		switch v := cur.(type) {
+		case map[string]any:
+			next, ok := v[p]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			i := -1
+			if _, err := fmt.Sscanf(p, "%d", &i); err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			cur = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// copyValue returns a deep copy of a decoded JSON value.
+func copyValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(val))
+		for k, item := range val {
+			m[k] = copyValue(item)
+		}
+		return m
+	case []any:
+		s := make([]any, len(val))
+		for i, item := range val {
+			s[i] = copyValue(item)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// escapeFragment percent-encodes characters that are not allowed in a URI
+// fragment, such as the braces of path templates.
+func escapeFragment(s string) string {
+	return strings.NewReplacer("%", "%25", "{", "%7B", "}", "%7D", " ", "%20").Replace(s)
+}
+
+func refObject(ref string, siblings map[string]any) map[string]any {
+	m := map[string]any{Ref: ref}
+	for k, v := range siblings {
+		if k != Ref {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+func isRemoteRef(file string) bool {
+	return strings.Contains(file, "://")
+}
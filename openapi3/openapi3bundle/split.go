@@ -0,0 +1,179 @@
+package openapi3bundle
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/grokify/spectrum/openapi3"
+)
+
+// File is a split spec file with a slash separated path relative to the
+// output directory.
+type File struct {
+	Path     string
+	Document any
+}
+
+// SplitFile reads a spec file, splits it with `Split()` and writes the
+// files to `dir`. The root file has the same name as `filename`.
+func SplitFile(filename, dir string, perm os.FileMode) ([]File, error) {
+	doc, err := ReadDocument(filename)
+	if err != nil {
+		return nil, err
+	}
+	files := Split(doc, filepath.Base(filename))
+	return files, WriteFiles(dir, files, perm)
+}
+
+// WriteFiles writes files to `dir` as YAML or JSON based on their
+// extensions.
+func WriteFiles(dir string, files []File, perm os.FileMode) error {
+	for _, f := range files {
+		if err := WriteDocument(filepath.Join(dir, filepath.FromSlash(f.Path)), f.Document, perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Split returns a root file named `rootFilename`, a file for each path item
+// in `paths/`, e.g. `paths/pets@{petId}.yaml` for `/pets/{petId}`, and a
+// file for each component in `components/<type>/<name>`. Files use the
+// root file extension. Local refs are rewritten as relative file refs, so
+// `BundleFile()` on the root file returns the original spec.
+func Split(doc map[string]any, rootFilename string) []File {
+	ext := path.Ext(rootFilename)
+	s := splitter{root: rootFilename, locations: map[string]string{}}
+	root := copyValue(doc).(map[string]any)
+
+	type splitItem struct {
+		parent  map[string]any
+		key     string
+		pointer []string
+		file    string
+	}
+	items := []splitItem{}
+	used := map[string]bool{}
+	unique := func(p string) string {
+		base := strings.TrimSuffix(p, ext)
+		for i := 2; used[strings.ToLower(p)]; i++ {
+			p = fmt.Sprintf("%s-%d%s", base, i, ext)
+		}
+		used[strings.ToLower(p)] = true
+		return p
+	}
+
+	if comps, ok := root[openapi3.PathComponents].(map[string]any); ok {
+		for _, kind := range sortedKeys(comps) {
+			typeComps, ok := comps[kind].(map[string]any)
+			if !ok || !isComponentType(kind) {
+				continue
+			}
+			for _, name := range sortedKeys(typeComps) {
+				items = append(items, splitItem{
+					parent:  typeComps,
+					key:     name,
+					pointer: []string{openapi3.PathComponents, kind, name},
+					file:    unique(path.Join(ComponentsDir, kind, Filename(name)+ext))})
+			}
+		}
+	}
+	if paths, ok := root[PathsMember].(map[string]any); ok {
+		for _, p := range sortedKeys(paths) {
+			items = append(items, splitItem{
+				parent:  paths,
+				key:     p,
+				pointer: []string{PathsMember, p},
+				file:    unique(path.Join(PathsDir, PathFilename(p)+ext))})
+		}
+	}
+	for _, item := range items {
+		s.locations[pointerString(item.pointer)] = item.file
+	}
+
+	files := []File{}
+	for _, item := range items {
+		files = append(files, File{
+			Path:     item.file,
+			Document: s.rewrite(item.parent[item.key], item.file)})
+		item.parent[item.key] = map[string]any{Ref: item.file}
+	}
+	rootDoc := s.rewrite(root, rootFilename)
+	return append([]File{{Path: rootFilename, Document: rootDoc}}, files...)
+}
+
+type splitter struct {
+	root      string
+	locations map[string]string // root JSON pointer -> file
+}
+
+// rewrite rewrites local refs in a value written to file `from`.
+func (s splitter) rewrite(node any, from string) any {
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v[Ref].(string); ok && strings.HasPrefix(ref, "#") {
+			v[Ref] = s.rewriteRef(ref, from)
+		}
+		for k, item := range v {
+			if k != Ref {
+				v[k] = s.rewrite(item, from)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = s.rewrite(item, from)
+		}
+	}
+	return node
+}
+
+func (s splitter) rewriteRef(ref, from string) string {
+	_, pointer, err := splitRef(ref)
+	if err != nil {
+		return ref
+	}
+	file, rest := s.root, pointer
+	for n := 2; n <= 3 && n <= len(pointer); n++ {
+		if loc, ok := s.locations[pointerString(pointer[:n])]; ok {
+			file, rest = loc, pointer[n:]
+			break
+		}
+	}
+	if file == from && file == s.root {
+		return ref
+	}
+	rel, err := filepath.Rel(path.Dir(from), file)
+	if err != nil {
+		return ref
+	}
+	rel = filepath.ToSlash(rel)
+	if len(rest) > 0 {
+		return rel + "#" + escapeFragment(pointerString(rest))
+	}
+	return rel
+}
+
+var rxFilenameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._@{}-]+`)
+
+// Filename returns a filename, without extension, for a component name.
+func Filename(name string) string {
+	name = strings.Trim(rxFilenameUnsafe.ReplaceAllString(name, "_"), ".")
+	if len(name) == 0 {
+		return "_"
+	}
+	return name
+}
+
+// PathFilename returns a filename, without extension, for a path, with
+// `/` separators replaced by `@`.
+func PathFilename(p string) string {
+	p = strings.Trim(p, "/")
+	if len(p) == 0 {
+		return "_root"
+	}
+	return Filename(strings.ReplaceAll(p, "/", "@"))
+}
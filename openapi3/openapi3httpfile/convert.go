@@ -0,0 +1,305 @@
+package openapi3httpfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/grokify/mogo/net/http/httputilmore"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3/openapi3postman2"
+	"github.com/grokify/spectrum/postman2"
+)
+
+// ConvertFile reads an OpenAPI 3 spec file and returns `.http` files.
+func ConvertFile(cfg openapi3postman2.Configuration, filename string) (Collection, error) {
+	spec, err := openapi3.ReadFile(filename, true)
+	if err != nil {
+		return Collection{}, err
+	}
+	return Convert(cfg, spec)
+}
+
+// Convert returns a `.http` file for each top-level folder, with nested
+// folders flattened, and a file for requests outside folders. Requests are
+// built like `openapi3postman2.ConvertSpec()`, so `cfg` server URL, base
+// path and header settings apply. Path parameters are declared as file
+// variables. Unless a server URL is configured, URLs use the `{{baseUrl}}`
+// variable, which with the auth variables is set per server in
+// `http-client.env.json`, with secrets in `http-client.private.env.json`.
+// VS Code REST Client users can copy these into the
+// `rest-client.environmentVariables` setting.
+func Convert(cfg openapi3postman2.Configuration, spec *openapi3.Spec) (Collection, error) {
+	cfg = cfg.WithDefaultBaseURLVariable()
+	pman, err := openapi3postman2.ConvertSpec(cfg, spec)
+	if err != nil {
+		return Collection{}, err
+	}
+	col := Collection{Files: []File{}}
+	used := map[string]bool{}
+	root := []*postman2.Item{}
+	for _, item := range pman.Item {
+		if item == nil {
+			continue
+		}
+		if item.Request != nil {
+			root = append(root, item)
+			continue
+		}
+		col.Files = append(col.Files, File{
+			Path:    uniqueFilename(used, item.Name),
+			Content: fileContent(pman, item.Item, nil)})
+	}
+	if len(root) > 0 {
+		col.Files = append(col.Files, File{
+			Path:    uniqueFilename(used, pman.Info.Name),
+			Content: fileContent(pman, root, nil)})
+	}
+
+	public := map[string]map[string]string{}
+	private := map[string]map[string]string{}
+	for _, env := range openapi3postman2.Environments(cfg, spec) {
+		public[env.Name] = map[string]string{}
+		for _, val := range env.Values {
+			if val.Type == postman2.EnvironmentValueTypeSecret {
+				if private[env.Name] == nil {
+					private[env.Name] = map[string]string{}
+				}
+				private[env.Name][val.Key] = val.Value
+			} else {
+				public[env.Name][val.Key] = val.Value
+			}
+		}
+	}
+	for _, env := range []struct {
+		filename string
+		data     map[string]map[string]string
+	}{{EnvironmentFilename, public}, {PrivateEnvironmentFile, private}} {
+		if len(env.data) == 0 {
+			continue
+		}
+		data, err := json.MarshalIndent(env.data, "", "  ")
+		if err != nil {
+			return col, err
+		}
+		col.Files = append(col.Files, File{Path: env.filename, Content: string(data) + "\n"})
+	}
+	return col, nil
+}
+
+func uniqueFilename(used map[string]bool, name string) string {
+	filename := Filename(name)
+	base := strings.TrimSuffix(filename, FileExtension)
+	for i := 2; used[strings.ToLower(filename)]; i++ {
+		filename = fmt.Sprintf("%s_%d%s", base, i, FileExtension)
+	}
+	used[strings.ToLower(filename)] = true
+	return path.Clean(filename)
+}
+
+// fileContent returns the file variables for path parameters followed by
+// the requests in `items` and their sub folders.
+func fileContent(pman postman2.Collection, items []*postman2.Item, folder []string) string {
+	vars := map[string]string{}
+	varNames := []string{}
+	requests := requestsContent(pman, items, folder, vars, &varNames)
+	var sb strings.Builder
+	for _, name := range varNames {
+		sb.WriteString(strings.TrimRight("@"+name+" = "+vars[name], " ") + "\n")
+	}
+	if len(varNames) > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(strings.Join(requests, "\n"))
+	return sb.String()
+}
+
+func requestsContent(pman postman2.Collection, items []*postman2.Item, folder []string, vars map[string]string, varNames *[]string) []string {
+	requests := []string{}
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		if item.Request == nil {
+			requests = append(requests, requestsContent(pman, item.Item,
+				append(append([]string{}, folder...), item.Name), vars, varNames)...)
+			continue
+		}
+		if item.Request.URL != nil {
+			for _, m := range rxPathParameter.FindAllStringSubmatch(item.Request.URL.Raw, -1) {
+				if _, ok := vars[m[1]]; !ok {
+					vars[m[1]] = ""
+					*varNames = append(*varNames, m[1])
+				}
+				for _, v := range item.Request.URL.Variable {
+					if (v.Key == m[1] || v.ID == m[1]) && v.Value != nil && len(vars[m[1]]) == 0 {
+						vars[m[1]] = fmt.Sprint(v.Value)
+					}
+				}
+			}
+		}
+		requests = append(requests, Request(pman, item, folder))
+	}
+	return requests
+}
+
+var rxPathParameter = regexp.MustCompile(`/:([A-Za-z0-9_.\-]+)`)
+
+// Request returns the `.http` text for a request item. Collection auth is
+// used when the request has none. Disabled query parameters are listed as
+// comments.
+func Request(pman postman2.Collection, item *postman2.Item, folder []string) string {
+	req := item.Request
+	lines := []string{RequestSeparator + " " + item.DisplayName()}
+	if len(folder) > 0 {
+		lines = append(lines, "# Folder: "+strings.Join(folder, " / "))
+	}
+	for _, line := range strings.Split(strings.TrimSpace(req.Description.String()), "\n") {
+		if len(strings.TrimSpace(line)) > 0 {
+			lines = append(lines, strings.TrimRight("# "+line, " "))
+		}
+	}
+
+	rawURL := ""
+	query := []string{}
+	if req.URL != nil {
+		rawURL = rxPathParameter.ReplaceAllString(req.URL.Raw, "/{{$1}}")
+		for _, q := range req.URL.Query {
+			if q.Disabled {
+				comment := "# Query: " + q.Key + "=" + q.Value
+				if desc := strings.TrimSpace(q.Description.String()); len(desc) > 0 {
+					comment += " - " + strings.Join(strings.Fields(desc), " ")
+				}
+				lines = append(lines, comment)
+				continue
+			}
+			query = append(query, q.Key+"="+q.Value)
+		}
+	}
+
+	headers := []string{}
+	contentType := ""
+	for _, h := range req.Header {
+		if h.Disabled {
+			continue
+		}
+		if strings.EqualFold(h.Key, httputilmore.HeaderContentType) {
+			contentType = h.Value
+			if req.Body != nil && req.Body.Mode == postman2.BodyModeFormData {
+				headers = append(headers, h.Key+": "+httputilmore.ContentTypeMultipartFormData+"; boundary="+MultipartBoundary)
+				continue
+			}
+		}
+		headers = append(headers, h.Key+": "+h.Value)
+	}
+
+	auth := req.Auth
+	if auth == nil {
+		auth = pman.Auth
+	}
+	authHeaders, authQuery := AuthHeadersQuery(auth)
+	headers = append(headers, authHeaders...)
+	query = append(query, authQuery...)
+	if len(query) > 0 {
+		rawURL += "?" + strings.Join(query, "&")
+	}
+
+	lines = append(lines, strings.TrimSpace(req.Method+" "+rawURL))
+	lines = append(lines, headers...)
+	if body := Body(req.Body, contentType); len(body) > 0 {
+		lines = append(lines, "", body)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Body returns the `.http` request body for a Postman request body. Files
+// are referenced with `< ./<file>`.
+func Body(body *postman2.RequestBody, contentType string) string {
+	if body == nil || body.Disabled {
+		return ""
+	}
+	switch body.Mode {
+	case postman2.BodyModeRaw:
+		return body.Raw
+	case postman2.BodyModeURLEncoded:
+		params := []string{}
+		for _, p := range body.URLEncoded {
+			if !p.Disabled {
+				params = append(params, p.Key+"="+p.Value)
+			}
+		}
+		return strings.Join(params, "&")
+	case postman2.BodyModeFormData:
+		lines := []string{}
+		for _, p := range body.FormData {
+			if p.Disabled {
+				continue
+			}
+			lines = append(lines, "--"+MultipartBoundary)
+			if p.Type == postman2.FormDataTypeFile {
+				src, _ := p.Src.(string)
+				if len(src) == 0 {
+					src = p.Key
+				}
+				lines = append(lines,
+					`Content-Disposition: form-data; name="`+p.Key+`"; filename="`+path.Base(src)+`"`,
+					"",
+					"< ./"+strings.TrimPrefix(src, "./"))
+			} else {
+				lines = append(lines,
+					`Content-Disposition: form-data; name="`+p.Key+`"`,
+					"",
+					p.Value)
+			}
+		}
+		lines = append(lines, "--"+MultipartBoundary+"--")
+		return strings.Join(lines, "\n")
+	case postman2.BodyModeGraphQL:
+		if body.GraphQL == nil {
+			return ""
+		}
+		gql := map[string]any{"query": body.GraphQL.Query}
+		if vars := strings.TrimSpace(body.GraphQL.Variables); len(vars) > 0 {
+			gql["variables"] = json.RawMessage(vars)
+		}
+		data, err := json.MarshalIndent(gql, "", "  ")
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	case postman2.BodyModeFile:
+		if body.File != nil {
+			if src, ok := body.File.Src.(string); ok && len(src) > 0 {
+				return "< ./" + strings.TrimPrefix(src, "./")
+			}
+		}
+	}
+	return ""
+}
+
+// AuthHeadersQuery returns the headers and query parameters that send
+// Postman auth. OAuth 2.0 sends the access token as a bearer token. Auth
+// types without a `.http` equivalent return nothing.
+func AuthHeadersQuery(auth *postman2.Auth) ([]string, []string) {
+	if auth == nil {
+		return nil, nil
+	}
+	switch auth.Type {
+	case postman2.AuthTypeBearer:
+		return []string{httputilmore.HeaderAuthorization + ": Bearer " + auth.AttributeString("token")}, nil
+	case postman2.AuthTypeOAuth2:
+		return []string{httputilmore.HeaderAuthorization + ": Bearer " + auth.AttributeString("accessToken")}, nil
+	case postman2.AuthTypeBasic:
+		return []string{httputilmore.HeaderAuthorization + ": Basic " +
+			auth.AttributeString("username") + " " + auth.AttributeString("password")}, nil
+	case postman2.AuthTypeAPIKey:
+		pair := auth.AttributeString("key")
+		if auth.AttributeString("in") == openapi3.InQuery {
+			return nil, []string{pair + "=" + auth.AttributeString("value")}
+		}
+		return []string{pair + ": " + auth.AttributeString("value")}, nil
+	}
+	return nil, nil
+}
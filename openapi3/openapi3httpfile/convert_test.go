@@ -0,0 +1,189 @@
+package openapi3httpfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3/openapi3postman2"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const convertSpec = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"servers":[{"url":"https://api.example.com/v1","description":"Production"}],
+"security":[{"bearerAuth":[]}],
+"paths":{
+  "/pets/{petId}":{"get":{"operationId":"getPet","summary":"Get pet","tags":["pets"],
+    "parameters":[
+      {"name":"petId","in":"path","required":true,"schema":{"type":"string","example":"42"}},
+      {"name":"fields","in":"query","schema":{"type":"string"},"example":"name"}],
+    "responses":{"200":{"description":"OK"}}}},
+  "/pets":{"post":{"operationId":"createPet","summary":"Create pet","tags":["pets"],"security":[{"apiKeyQuery":[]}],
+    "requestBody":{"content":{"application/json":{"example":{"name":"Tom"}}}},
+    "responses":{"201":{"description":"Created"}}}},
+  "/status":{"get":{"operationId":"getStatus","summary":"Status","security":[],
+    "responses":{"200":{"description":"OK"}}}}},
+"components":{"securitySchemes":{
+  "bearerAuth":{"type":"http","scheme":"bearer"},
+  "apiKeyQuery":{"type":"apiKey","in":"query","name":"api_key"}}}}`
+
+var convertTests = []struct {
+	path    string
+	content string
+}{
+	{"pets.http", `@petId =
+
+### Create pet
+POST {{baseUrl}}/pets?api_key={{apiKeyQuery_apiKey}}
+Content-Type: application/json
+
+{
+  "name": "Tom"
+}
+
+### Get pet
+# Query: fields=<string>
+GET {{baseUrl}}/pets/{{petId}}
+Authorization: Bearer {{bearerAuth_token}}
+`},
+	{"Pets_2.http", `### Status
+GET {{baseUrl}}/status
+`},
+	{"http-client.env.json", `{
+  "Pets - Production": {
+    "baseUrl": "https://api.example.com/v1"
+  }
+}
+`},
+	{"http-client.private.env.json", `{
+  "Pets - Production": {
+    "apiKeyQuery_apiKey": "",
+    "bearerAuth_token": ""
+  }
+}
+`},
+}
+
+// TestConvert ensures a spec converts to `.http` files grouped by tag,
+// with path parameter file variables, per-operation auth and environment
+// files.
+func TestConvert(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(convertSpec))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	col, err := Convert(openapi3postman2.Configuration{}, spec)
+	if err != nil {
+		t.Fatalf("openapi3httpfile.Convert() Error [%s]", err.Error())
+	}
+	if len(col.Files) != len(convertTests) {
+		t.Fatalf("openapi3httpfile.Convert() Files Mismatch: want [%d], got [%d]", len(convertTests), len(col.Files))
+	}
+	for i, tt := range convertTests {
+		if col.Files[i].Path != tt.path || col.Files[i].Content != tt.content {
+			t.Errorf("openapi3httpfile.Convert() Mismatch at [%d]: want [%s] [%s], got [%s] [%s]",
+				i, tt.path, tt.content, col.Files[i].Path, col.Files[i].Content)
+		}
+	}
+}
+
+// TestConvertFileServerURL ensures a configured server URL replaces the
+// `{{baseUrl}}` variable and the files are written to a directory.
+func TestConvertFileServerURL(t *testing.T) {
+	dir := t.TempDir()
+	specFile := filepath.Join(dir, "spec.json")
+	if err := os.WriteFile(specFile, []byte(convertSpec), 0600); err != nil {
+		t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+	}
+	col, err := ConvertFile(openapi3postman2.Configuration{PostmanServerURL: "https://sandbox.example.com"}, specFile)
+	if err != nil {
+		t.Fatalf("openapi3httpfile.ConvertFile() Error [%s]", err.Error())
+	}
+	outDir := filepath.Join(dir, "http")
+	if err := col.WriteDir(outDir, 0600); err != nil {
+		t.Fatalf("openapi3httpfile.Collection.WriteDir() Error [%s]", err.Error())
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "pets.http"))
+	if err != nil {
+		t.Fatalf("os.ReadFile() Error [%s]", err.Error())
+	}
+	if !strings.Contains(string(data), "GET https://sandbox.example.com/pets/{{petId}}\n") || strings.Contains(string(data), "{{baseUrl}}") {
+		t.Errorf("openapi3httpfile.ConvertFile() Mismatch: want server URL override, got [%s]", string(data))
+	}
+	if _, err := os.Stat(filepath.Join(outDir, EnvironmentFilename)); err != nil {
+		t.Errorf("openapi3httpfile.Collection.WriteDir() Mismatch: want [%s] Error [%s]", EnvironmentFilename, err.Error())
+	}
+}
+
+var bodyTests = []struct {
+	body        *postman2.RequestBody
+	contentType string
+	want        string
+}{
+	{&postman2.RequestBody{Mode: postman2.BodyModeRaw, Raw: `{"name":"Tom"}`}, "application/json",
+		`{"name":"Tom"}`},
+	{&postman2.RequestBody{Mode: postman2.BodyModeURLEncoded, URLEncoded: []postman2.URLEncodedParam{
+		{Key: "name", Value: "Tom"}, {Key: "age", Value: "3", Disabled: true}, {Key: "tag", Value: "cat"}}}, "",
+		"name=Tom&tag=cat"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeFormData, FormData: []postman2.FormDataParam{
+		{Key: "name", Value: "Tom", Type: postman2.FormDataTypeText},
+		{Key: "photo", Type: postman2.FormDataTypeFile, Src: "./img/photo.png"}}}, "",
+		"--boundary\nContent-Disposition: form-data; name=\"name\"\n\nTom\n" +
+			"--boundary\nContent-Disposition: form-data; name=\"photo\"; filename=\"photo.png\"\n\n< ./img/photo.png\n" +
+			"--boundary--"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeGraphQL, GraphQL: &postman2.RequestBodyGraphQL{
+		Query: "query { pets { name } }", Variables: `{"limit":10}`}}, "",
+		"{\n  \"query\": \"query { pets { name } }\",\n  \"variables\": {\n    \"limit\": 10\n  }\n}"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeFile, File: &postman2.RequestBodyFile{Src: "pet.bin"}}, "",
+		"< ./pet.bin"},
+	{&postman2.RequestBody{Mode: postman2.BodyModeRaw, Raw: "hello", Disabled: true}, "", ""},
+	{nil, "", ""},
+}
+
+// TestBody ensures each Postman body mode maps to a `.http` request body.
+func TestBody(t *testing.T) {
+	for i, tt := range bodyTests {
+		if got := Body(tt.body, tt.contentType); got != tt.want {
+			t.Errorf("openapi3httpfile.Body() Mismatch at [%d]: want [%s], got [%s]", i, tt.want, got)
+		}
+	}
+}
+
+var authHeadersQueryTests = []struct {
+	auth    *postman2.Auth
+	headers []string
+	query   []string
+}{
+	{postman2.NewAuth(postman2.AuthTypeBearer, postman2.NewAuthAttribute("token", "{{token}}")),
+		[]string{"Authorization: Bearer {{token}}"}, nil},
+	{postman2.NewAuth(postman2.AuthTypeBasic,
+		postman2.NewAuthAttribute("username", "{{user}}"), postman2.NewAuthAttribute("password", "{{pass}}")),
+		[]string{"Authorization: Basic {{user}} {{pass}}"}, nil},
+	{postman2.NewAuth(postman2.AuthTypeAPIKey, postman2.NewAuthAttribute("key", "X-API-Key"),
+		postman2.NewAuthAttribute("value", "{{apiKey}}"), postman2.NewAuthAttribute("in", "header")),
+		[]string{"X-API-Key: {{apiKey}}"}, nil},
+	{postman2.NewAuth(postman2.AuthTypeAPIKey, postman2.NewAuthAttribute("key", "api_key"),
+		postman2.NewAuthAttribute("value", "{{apiKey}}"), postman2.NewAuthAttribute("in", "query")),
+		nil, []string{"api_key={{apiKey}}"}},
+	{postman2.NewAuth(postman2.AuthTypeOAuth2, postman2.NewAuthAttribute("accessToken", "{{accessToken}}"),
+		postman2.NewAuthAttribute("grant_type", "client_credentials")),
+		[]string{"Authorization: Bearer {{accessToken}}"}, nil},
+	{&postman2.Auth{Type: postman2.AuthTypeNoAuth}, nil, nil},
+	{nil, nil, nil},
+}
+
+// TestAuthHeadersQuery ensures bearer, basic, API key and OAuth 2.0 auth
+// map to `.http` headers and query parameters.
+func TestAuthHeadersQuery(t *testing.T) {
+	for i, tt := range authHeadersQueryTests {
+		headers, query := AuthHeadersQuery(tt.auth)
+		if strings.Join(headers, "\n") != strings.Join(tt.headers, "\n") ||
+			strings.Join(query, "&") != strings.Join(tt.query, "&") {
+			t.Errorf("openapi3httpfile.AuthHeadersQuery() Mismatch at [%d]: want [%v] [%v], got [%v] [%v]",
+				i, tt.headers, tt.query, headers, query)
+		}
+	}
+}
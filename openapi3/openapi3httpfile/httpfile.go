@@ -0,0 +1,53 @@
+// openapi3httpfile converts OpenAPI 3 specs to `.http` request files used
+// by the JetBrains HTTP Client and the VS Code REST Client.
+package openapi3httpfile
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	FileExtension          = ".http"
+	EnvironmentFilename    = "http-client.env.json"
+	PrivateEnvironmentFile = "http-client.private.env.json"
+	RequestSeparator       = "###"
+	MultipartBoundary      = "boundary"
+)
+
+// File is an output file with a path relative to the output directory.
+type File struct {
+	Path    string
+	Content string
+}
+
+// Collection is a set of `.http` files and environment files.
+type Collection struct {
+	Files []File
+}
+
+// WriteDir writes the files to `dir`.
+func (col Collection) WriteDir(dir string, perm os.FileMode) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, f := range col.Files {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(f.Path)), []byte(f.Content), perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var rxFilenameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Filename returns a `.http` filename for a folder or collection name.
+func Filename(name string) string {
+	name = strings.Trim(rxFilenameUnsafe.ReplaceAllString(strings.TrimSpace(name), "_"), "._")
+	if len(name) == 0 {
+		name = "requests"
+	}
+	return name + FileExtension
+}
@@ -0,0 +1,300 @@
+package openapi3insomnia4
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grokify/mogo/net/http/httputilmore"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3/openapi3postman2"
+	"github.com/grokify/spectrum/postman2"
+)
+
+// ConvertFile reads an OpenAPI 3 spec file and returns an Insomnia export.
+func ConvertFile(cfg openapi3postman2.Configuration, filename string) (Export, error) {
+	spec, err := openapi3.ReadFile(filename, true)
+	if err != nil {
+		return Export{}, err
+	}
+	return Convert(cfg, spec)
+}
+
+// Convert returns an Insomnia export for a spec. Requests are built like
+// `openapi3postman2.ConvertSpec()`, so `cfg` server URL, base path and
+// header settings apply. Unless a server URL is configured, URLs use the
+// `{{ _.baseUrl }}` variable with a sub environment for each server. Path
+// parameters become environment variables and auth is set per request.
+func Convert(cfg openapi3postman2.Configuration, spec *openapi3.Spec) (Export, error) {
+	cfg = cfg.WithDefaultBaseURLVariable()
+	col, err := openapi3postman2.ConvertSpec(cfg, spec)
+	if err != nil {
+		return Export{}, err
+	}
+	exp := Export{
+		Type:         ExportType,
+		ExportFormat: ExportFormat,
+		ExportDate:   time.Now().UTC().Format(time.RFC3339),
+		ExportSource: ExportSource,
+		Resources:    []Resource{}}
+
+	wrkID := resourceID("wrk", col.Info.Name)
+	exp.Resources = append(exp.Resources, Resource{
+		ID:          wrkID,
+		Type:        TypeWorkspace,
+		Name:        col.Info.Name,
+		Description: col.Info.Description.String(),
+		Scope:       ScopeCollection})
+
+	conv := converter{col: col, data: map[string]any{}}
+	for _, v := range col.Variable {
+		conv.data[v.Key] = Variables(variableString(v.Value))
+	}
+	conv.items(col.Item, wrkID, nil)
+
+	envID := resourceID("env", wrkID, BaseEnvironmentName)
+	exp.Resources = append(exp.Resources, Resource{
+		ID:       envID,
+		Type:     TypeEnvironment,
+		ParentID: &wrkID,
+		Name:     BaseEnvironmentName,
+		Data:     conv.data})
+	for _, env := range openapi3postman2.Environments(cfg, spec) {
+		data := map[string]any{}
+		for _, val := range env.Values {
+			data[val.Key] = Variables(val.Value)
+		}
+		exp.Resources = append(exp.Resources, Resource{
+			ID:       resourceID("env", wrkID, env.Name),
+			Type:     TypeEnvironment,
+			ParentID: &envID,
+			Name:     env.Name,
+			Data:     data})
+	}
+	exp.Resources = append(exp.Resources, conv.resources...)
+	return exp, nil
+}
+
+type converter struct {
+	col       postman2.Collection
+	data      map[string]any
+	resources []Resource
+}
+
+func (conv *converter) items(items []*postman2.Item, parentID string, path []string) {
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		itemPath := append(append([]string{}, path...), item.Name)
+		if item.Request == nil {
+			fldID := resourceID("fld", itemPath...)
+			conv.resources = append(conv.resources, Resource{
+				ID:          fldID,
+				Type:        TypeRequestGroup,
+				ParentID:    &parentID,
+				Name:        item.Name,
+				Description: item.Description.String()})
+			conv.items(item.Item, fldID, itemPath)
+			continue
+		}
+		conv.resources = append(conv.resources, conv.request(item, parentID, itemPath))
+	}
+}
+
+func (conv *converter) request(item *postman2.Item, parentID string, path []string) Resource {
+	req := item.Request
+	res := Resource{
+		ID:          resourceID("req", append(path, req.Method, requestURLRaw(req))...),
+		Type:        TypeRequest,
+		ParentID:    &parentID,
+		Name:        item.DisplayName(),
+		Description: req.Description.String(),
+		Method:      req.Method}
+	if req.URL != nil {
+		res.URL = Variables(req.URL.Raw)
+		for _, m := range rxPathParameter.FindAllStringSubmatch(req.URL.Raw, -1) {
+			if _, ok := conv.data[m[1]]; !ok {
+				conv.data[m[1]] = ""
+			}
+		}
+		for _, v := range req.URL.Variable {
+			name := v.Key
+			if len(name) == 0 {
+				name = v.ID
+			}
+			if s := variableString(v.Value); len(s) > 0 {
+				conv.data[name] = Variables(s)
+			}
+		}
+		for _, q := range req.URL.Query {
+			res.Parameters = append(res.Parameters, Parameter{
+				Name:        q.Key,
+				Value:       Variables(q.Value),
+				Description: q.Description.String(),
+				Disabled:    q.Disabled})
+		}
+	}
+	contentType := ""
+	for _, h := range req.Header {
+		if strings.EqualFold(h.Key, httputilmore.HeaderContentType) {
+			contentType = h.Value
+			if req.Body != nil && req.Body.Mode == postman2.BodyModeFormData {
+				// Insomnia sets the multipart boundary.
+				continue
+			}
+		}
+		res.Headers = append(res.Headers, Parameter{
+			Name:        h.Key,
+			Value:       Variables(h.Value),
+			Description: h.Description.String(),
+			Disabled:    h.Disabled})
+	}
+	res.Body = requestBody(req.Body, contentType)
+
+	auth := req.Auth
+	if auth == nil {
+		auth = conv.col.Auth
+	}
+	res.Authentication = Authentication(auth)
+	if apiKeyInQuery(auth) {
+		res.Parameters = append(res.Parameters, Parameter{
+			Name:  auth.AttributeString("key"),
+			Value: Variables(auth.AttributeString("value"))})
+	}
+	return res
+}
+
+func requestBody(body *postman2.RequestBody, contentType string) *Body {
+	if body == nil || body.Disabled {
+		return nil
+	}
+	switch body.Mode {
+	case postman2.BodyModeRaw:
+		if len(contentType) == 0 {
+			contentType = httputilmore.ContentTypeTextPlain
+		}
+		return &Body{MimeType: contentType, Text: body.Raw}
+	case postman2.BodyModeURLEncoded:
+		b := &Body{MimeType: httputilmore.ContentTypeAppFormURLEncoded, Params: []Parameter{}}
+		for _, p := range body.URLEncoded {
+			b.Params = append(b.Params, Parameter{
+				Name:     p.Key,
+				Value:    Variables(p.Value),
+				Disabled: p.Disabled})
+		}
+		return b
+	case postman2.BodyModeFormData:
+		b := &Body{MimeType: httputilmore.ContentTypeMultipartFormData, Params: []Parameter{}}
+		for _, p := range body.FormData {
+			param := Parameter{Name: p.Key, Value: Variables(p.Value), Disabled: p.Disabled}
+			if p.Type == postman2.FormDataTypeFile {
+				param.Type = postman2.FormDataTypeFile
+				param.Value = ""
+				if src, ok := p.Src.(string); ok {
+					param.FileName = src
+				}
+			}
+			b.Params = append(b.Params, param)
+		}
+		return b
+	case postman2.BodyModeGraphQL:
+		if body.GraphQL == nil {
+			return nil
+		}
+		gql := map[string]any{"query": body.GraphQL.Query}
+		if vars := strings.TrimSpace(body.GraphQL.Variables); len(vars) > 0 {
+			gql["variables"] = json.RawMessage(vars)
+		}
+		text, err := json.Marshal(gql)
+		if err != nil {
+			return nil
+		}
+		return &Body{MimeType: "application/graphql", Text: string(text)}
+	case postman2.BodyModeFile:
+		b := &Body{MimeType: httputilmore.ContentTypeAppOctetStream}
+		if body.File != nil {
+			if src, ok := body.File.Src.(string); ok {
+				b.FileName = src
+			}
+		}
+		return b
+	}
+	return nil
+}
+
+// Authentication returns the Insomnia authentication for Postman auth.
+// Postman auth types without an Insomnia equivalent return nil.
+func Authentication(auth *postman2.Auth) map[string]any {
+	if auth == nil {
+		return nil
+	}
+	attr := func(key string) string { return Variables(auth.AttributeString(key)) }
+	switch auth.Type {
+	case postman2.AuthTypeBearer:
+		return map[string]any{"type": "bearer", "token": attr("token")}
+	case postman2.AuthTypeBasic:
+		return map[string]any{"type": "basic", "username": attr("username"), "password": attr("password")}
+	case postman2.AuthTypeAPIKey:
+		if apiKeyInQuery(auth) {
+			// Sent as a query parameter, see `apiKeyInQuery`.
+			return nil
+		}
+		return map[string]any{"type": "apikey", "key": attr("key"), "value": attr("value"), "addTo": "header"}
+	case postman2.AuthTypeOAuth2:
+		return map[string]any{
+			"type":             "oauth2",
+			"grantType":        attr("grant_type"),
+			"authorizationUrl": attr("authUrl"),
+			"accessTokenUrl":   attr("accessTokenUrl"),
+			"redirectUrl":      attr("redirect_uri"),
+			"clientId":         attr("clientId"),
+			"clientSecret":     attr("clientSecret"),
+			"scope":            attr("scope")}
+	}
+	return nil
+}
+
+// apiKeyInQuery reports whether auth is an API key sent as a query
+// parameter, which is added to the request parameters.
+func apiKeyInQuery(auth *postman2.Auth) bool {
+	return auth != nil && auth.Type == postman2.AuthTypeAPIKey &&
+		auth.AttributeString("in") == openapi3.InQuery
+}
+
+var (
+	rxPostmanVariable = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+	rxPathParameter   = regexp.MustCompile(`/:([A-Za-z0-9_.\-]+)`)
+)
+
+// Variables converts Postman `{{name}}` variables and `:name` path
+// parameters to Insomnia `{{ _.name }}` template variables.
+func Variables(s string) string {
+	s = rxPostmanVariable.ReplaceAllString(s, "{{ _.$1 }}")
+	return rxPathParameter.ReplaceAllString(s, "/{{ _.$1 }}")
+}
+
+func requestURLRaw(req *postman2.Request) string {
+	if req.URL == nil {
+		return ""
+	}
+	return req.URL.Raw
+}
+
+func variableString(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// resourceID returns a stable ID so re-exports can be re-imported over
+// earlier imports.
+func resourceID(prefix string, parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return prefix + "_" + hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,261 @@
+package openapi3insomnia4
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3/openapi3postman2"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const convertSpec = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"servers":[{"url":"https://api.example.com/v1","description":"Production"}],
+"security":[{"bearerAuth":[]}],
+"paths":{
+  "/pets/{petId}":{"get":{"operationId":"getPet","summary":"Get pet","tags":["pets"],
+    "parameters":[
+      {"name":"petId","in":"path","required":true,"schema":{"type":"string","example":"42"}},
+      {"name":"fields","in":"query","schema":{"type":"string"},"example":"name"}],
+    "responses":{"200":{"description":"OK"}}}},
+  "/pets":{"post":{"operationId":"createPet","summary":"Create pet","tags":["pets"],"security":[{"apiKeyQuery":[]}],
+    "requestBody":{"content":{"application/json":{"example":{"name":"Tom"}}}},
+    "responses":{"201":{"description":"Created"}}}},
+  "/status":{"get":{"operationId":"getStatus","summary":"Status","security":[],
+    "responses":{"200":{"description":"OK"}}}}},
+"components":{"securitySchemes":{
+  "bearerAuth":{"type":"http","scheme":"bearer"},
+  "apiKeyQuery":{"type":"apiKey","in":"query","name":"api_key"}}}}`
+
+const convertGolden = `{
+  "_type": "export",
+  "__export_format": 4,
+  "__export_source": "spectrum",
+  "resources": [
+    {
+      "_id": "wrk_7dc1cd7eaff67e81b0faba59c56df962",
+      "_type": "workspace",
+      "parentId": null,
+      "name": "Pets",
+      "scope": "collection"
+    },
+    {
+      "_id": "env_b085aa93429c9d58060793d5dc8f709b",
+      "_type": "environment",
+      "parentId": "wrk_7dc1cd7eaff67e81b0faba59c56df962",
+      "name": "Base Environment",
+      "data": {
+        "apiKeyQuery_apiKey": "",
+        "baseUrl": "https://api.example.com/v1",
+        "bearerAuth_token": "",
+        "petId": ""
+      }
+    },
+    {
+      "_id": "env_a5419e99ec724f28f92b790df271fa93",
+      "_type": "environment",
+      "parentId": "env_b085aa93429c9d58060793d5dc8f709b",
+      "name": "Pets - Production",
+      "data": {
+        "apiKeyQuery_apiKey": "",
+        "baseUrl": "https://api.example.com/v1",
+        "bearerAuth_token": ""
+      }
+    },
+    {
+      "_id": "fld_3be1d422a46085e17f4a19d3ed707c86",
+      "_type": "request_group",
+      "parentId": "wrk_7dc1cd7eaff67e81b0faba59c56df962",
+      "name": "pets"
+    },
+    {
+      "_id": "req_9060b7d857f0d5410f17a1b7f4d9025d",
+      "_type": "request",
+      "parentId": "fld_3be1d422a46085e17f4a19d3ed707c86",
+      "name": "Create pet",
+      "method": "POST",
+      "url": "{{ _.baseUrl }}/pets",
+      "body": {
+        "mimeType": "application/json",
+        "text": "{\n  \"name\": \"Tom\"\n}"
+      },
+      "parameters": [
+        {
+          "name": "api_key",
+          "value": "{{ _.apiKeyQuery_apiKey }}"
+        }
+      ],
+      "headers": [
+        {
+          "name": "Content-Type",
+          "value": "application/json"
+        }
+      ]
+    },
+    {
+      "_id": "req_75cfd4d37761e2627629d904471e2bcf",
+      "_type": "request",
+      "parentId": "fld_3be1d422a46085e17f4a19d3ed707c86",
+      "name": "Get pet",
+      "method": "GET",
+      "url": "{{ _.baseUrl }}/pets/{{ _.petId }}",
+      "parameters": [
+        {
+          "name": "fields",
+          "value": "<string>",
+          "disabled": true
+        }
+      ],
+      "authentication": {
+        "token": "{{ _.bearerAuth_token }}",
+        "type": "bearer"
+      }
+    },
+    {
+      "_id": "req_7d2e86da4b56921ab1d363665cc832bf",
+      "_type": "request",
+      "parentId": "wrk_7dc1cd7eaff67e81b0faba59c56df962",
+      "name": "Status",
+      "method": "GET",
+      "url": "{{ _.baseUrl }}/status"
+    }
+  ]
+}
+`
+
+// TestConvert ensures a spec converts to an Insomnia export with a
+// workspace, environments per server, folders per tag, path parameter
+// variables and per-request auth.
+func TestConvert(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(convertSpec))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	exp, err := Convert(openapi3postman2.Configuration{}, spec)
+	if err != nil {
+		t.Fatalf("openapi3insomnia4.Convert() Error [%s]", err.Error())
+	}
+	if len(exp.ExportDate) == 0 {
+		t.Errorf("openapi3insomnia4.Convert() Mismatch: want [__export_date], got [%s]", exp.ExportDate)
+	}
+	exp.ExportDate = ""
+	data, err := exp.Bytes()
+	if err != nil {
+		t.Fatalf("openapi3insomnia4.Export.Bytes() Error [%s]", err.Error())
+	}
+	if string(data) != convertGolden {
+		t.Errorf("openapi3insomnia4.Convert() Mismatch: want [%s], got [%s]", convertGolden, string(data))
+	}
+}
+
+// TestConvertFileServerURL ensures a configured server URL replaces the
+// `{{ _.baseUrl }}` variable and the export is written to a file.
+func TestConvertFileServerURL(t *testing.T) {
+	dir := t.TempDir()
+	specFile := filepath.Join(dir, "spec.json")
+	if err := os.WriteFile(specFile, []byte(convertSpec), 0600); err != nil {
+		t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+	}
+	exp, err := ConvertFile(openapi3postman2.Configuration{PostmanServerURL: "https://sandbox.example.com"}, specFile)
+	if err != nil {
+		t.Fatalf("openapi3insomnia4.ConvertFile() Error [%s]", err.Error())
+	}
+	outFile := filepath.Join(dir, "insomnia.json")
+	if err := exp.WriteFile(outFile, 0600); err != nil {
+		t.Fatalf("openapi3insomnia4.Export.WriteFile() Error [%s]", err.Error())
+	}
+	data, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatalf("os.ReadFile() Error [%s]", err.Error())
+	}
+	if !strings.Contains(string(data), `"url": "https://sandbox.example.com/pets/{{ _.petId }}"`) ||
+		strings.Contains(string(data), "{{ _.baseUrl }}") {
+		t.Errorf("openapi3insomnia4.ConvertFile() Mismatch: want server URL override, got [%s]", string(data))
+	}
+}
+
+var requestBodyTests = []struct {
+	body        *postman2.RequestBody
+	contentType string
+	want        string
+}{
+	{&postman2.RequestBody{Mode: postman2.BodyModeRaw, Raw: `{"name":"{{petName}}"}`}, "application/json",
+		`{"mimeType":"application/json","text":"{\"name\":\"{{petName}}\"}"}`},
+	{&postman2.RequestBody{Mode: postman2.BodyModeRaw, Raw: "hello"}, "",
+		`{"mimeType":"text/plain","text":"hello"}`},
+	{&postman2.RequestBody{Mode: postman2.BodyModeURLEncoded, URLEncoded: []postman2.URLEncodedParam{
+		{Key: "name", Value: "{{petName}}"}, {Key: "age", Value: "3", Disabled: true}}}, "",
+		`{"mimeType":"application/x-www-form-urlencoded","params":[{"name":"name","value":"{{ _.petName }}"},{"name":"age","value":"3","disabled":true}]}`},
+	{&postman2.RequestBody{Mode: postman2.BodyModeFormData, FormData: []postman2.FormDataParam{
+		{Key: "name", Value: "Tom", Type: postman2.FormDataTypeText},
+		{Key: "photo", Type: postman2.FormDataTypeFile, Src: "./photo.png"}}}, "",
+		`{"mimeType":"multipart/form-data","params":[{"name":"name","value":"Tom"},{"name":"photo","value":"","type":"file","fileName":"./photo.png"}]}`},
+	{&postman2.RequestBody{Mode: postman2.BodyModeGraphQL, GraphQL: &postman2.RequestBodyGraphQL{
+		Query: "query { pets { name } }", Variables: `{"limit":10}`}}, "",
+		`{"mimeType":"application/graphql","text":"{\"query\":\"query { pets { name } }\",\"variables\":{\"limit\":10}}"}`},
+	{&postman2.RequestBody{Mode: postman2.BodyModeFile, File: &postman2.RequestBodyFile{Src: "./pet.bin"}}, "",
+		`{"mimeType":"application/octet-stream","fileName":"./pet.bin"}`},
+	{&postman2.RequestBody{Mode: postman2.BodyModeRaw, Raw: "hello", Disabled: true}, "", `null`},
+	{nil, "", `null`},
+}
+
+// TestRequestBody ensures each Postman body mode maps to an Insomnia body.
+func TestRequestBody(t *testing.T) {
+	for i, tt := range requestBodyTests {
+		data, err := json.Marshal(requestBody(tt.body, tt.contentType))
+		if err != nil {
+			t.Fatalf("json.Marshal() Error [%s]", err.Error())
+		}
+		if string(data) != tt.want {
+			t.Errorf("openapi3insomnia4.requestBody() Mismatch at [%d]: want [%s], got [%s]", i, tt.want, string(data))
+		}
+	}
+}
+
+var authenticationTests = []struct {
+	auth *postman2.Auth
+	want string
+}{
+	{postman2.NewAuth(postman2.AuthTypeBearer, postman2.NewAuthAttribute("token", "{{token}}")),
+		`{"token":"{{ _.token }}","type":"bearer"}`},
+	{postman2.NewAuth(postman2.AuthTypeBasic,
+		postman2.NewAuthAttribute("username", "{{user}}"), postman2.NewAuthAttribute("password", "{{pass}}")),
+		`{"password":"{{ _.pass }}","type":"basic","username":"{{ _.user }}"}`},
+	{postman2.NewAuth(postman2.AuthTypeAPIKey, postman2.NewAuthAttribute("key", "X-API-Key"),
+		postman2.NewAuthAttribute("value", "{{apiKey}}"), postman2.NewAuthAttribute("in", "header")),
+		`{"addTo":"header","key":"X-API-Key","type":"apikey","value":"{{ _.apiKey }}"}`},
+	{postman2.NewAuth(postman2.AuthTypeAPIKey, postman2.NewAuthAttribute("key", "api_key"),
+		postman2.NewAuthAttribute("value", "{{apiKey}}"), postman2.NewAuthAttribute("in", "query")),
+		`null`},
+	{postman2.NewAuth(postman2.AuthTypeOAuth2,
+		postman2.NewAuthAttribute("grant_type", "authorization_code"),
+		postman2.NewAuthAttribute("authUrl", "https://auth.example.com/authorize"),
+		postman2.NewAuthAttribute("redirect_uri", "{{redirectUri}}"),
+		postman2.NewAuthAttribute("accessTokenUrl", "https://auth.example.com/token"),
+		postman2.NewAuthAttribute("clientId", "{{clientId}}"),
+		postman2.NewAuthAttribute("clientSecret", "{{clientSecret}}"),
+		postman2.NewAuthAttribute("scope", "read write")),
+		`{"accessTokenUrl":"https://auth.example.com/token","authorizationUrl":"https://auth.example.com/authorize",` +
+			`"clientId":"{{ _.clientId }}","clientSecret":"{{ _.clientSecret }}","grantType":"authorization_code",` +
+			`"redirectUrl":"{{ _.redirectUri }}","scope":"read write","type":"oauth2"}`},
+	{&postman2.Auth{Type: postman2.AuthTypeNoAuth}, `null`},
+	{nil, `null`},
+}
+
+// TestAuthentication ensures bearer, basic, API key and OAuth 2.0 auth map
+// to Insomnia authentication. API keys sent in the query have none.
+func TestAuthentication(t *testing.T) {
+	for i, tt := range authenticationTests {
+		data, err := json.Marshal(Authentication(tt.auth))
+		if err != nil {
+			t.Fatalf("json.Marshal() Error [%s]", err.Error())
+		}
+		if string(data) != tt.want {
+			t.Errorf("openapi3insomnia4.Authentication() Mismatch at [%d]: want [%s], got [%s]", i, tt.want, string(data))
+		}
+	}
+}
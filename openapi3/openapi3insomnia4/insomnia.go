@@ -0,0 +1,90 @@
+// openapi3insomnia4 converts OpenAPI 3 specs to Insomnia v4 export files.
+package openapi3insomnia4
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+)
+
+const (
+	ExportType   = "export"
+	ExportFormat = 4
+	ExportSource = "spectrum"
+
+	TypeWorkspace    = "workspace"
+	TypeEnvironment  = "environment"
+	TypeRequestGroup = "request_group"
+	TypeRequest      = "request"
+
+	ScopeCollection = "collection"
+
+	BaseEnvironmentName = "Base Environment"
+)
+
+// Export is an Insomnia v4 export file. Resources refer to their parent
+// by `parentId`, starting from the workspace.
+type Export struct {
+	Type         string     `json:"_type"`
+	ExportFormat int        `json:"__export_format"`
+	ExportDate   string     `json:"__export_date,omitempty"`
+	ExportSource string     `json:"__export_source,omitempty"`
+	Resources    []Resource `json:"resources"`
+}
+
+// Resource is a workspace, environment, request group (folder) or request.
+type Resource struct {
+	ID             string         `json:"_id"`
+	Type           string         `json:"_type"`
+	ParentID       *string        `json:"parentId"`
+	Name           string         `json:"name"`
+	Description    string         `json:"description,omitempty"`
+	Scope          string         `json:"scope,omitempty"`          // workspace
+	Data           map[string]any `json:"data,omitempty"`           // environment
+	IsPrivate      bool           `json:"isPrivate,omitempty"`      // environment
+	Method         string         `json:"method,omitempty"`         // request
+	URL            string         `json:"url,omitempty"`            // request
+	Body           *Body          `json:"body,omitempty"`           // request
+	Parameters     []Parameter    `json:"parameters,omitempty"`     // request
+	Headers        []Parameter    `json:"headers,omitempty"`        // request
+	Authentication map[string]any `json:"authentication,omitempty"` // request
+}
+
+// Body is a request body. Text bodies set `Text`, form bodies set `Params`
+// and binary bodies set `FileName`.
+type Body struct {
+	MimeType string      `json:"mimeType,omitempty"`
+	Text     string      `json:"text,omitempty"`
+	Params   []Parameter `json:"params,omitempty"`
+	FileName string      `json:"fileName,omitempty"`
+}
+
+// Parameter is a query parameter, header or form parameter.
+type Parameter struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+	Type        string `json:"type,omitempty"` // `file` for form file parameters
+	FileName    string `json:"fileName,omitempty"`
+}
+
+// Bytes returns the export as indented JSON. Variables such as
+// `{{ _.name }}` are kept unescaped.
+func (exp Export) Bytes() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	err := enc.Encode(exp)
+	return buf.Bytes(), err
+}
+
+// WriteFile writes the export as indented JSON.
+func (exp Export) WriteFile(filename string, perm os.FileMode) error {
+	data, err := exp.Bytes()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, perm)
+}
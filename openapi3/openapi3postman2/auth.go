@@ -0,0 +1,204 @@
+package openapi3postman2
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const (
+	authSchemeHTTP          = "http"
+	authSchemeAPIKey        = "apiKey"
+	authSchemeOAuth2        = "oauth2"
+	authHTTPSchemeBasic     = "basic"
+	authHTTPSchemeBearer    = "bearer"
+	oauth2GrantAuthCode     = "authorization_code"
+	oauth2GrantClientCreds  = "client_credentials"
+	authVariableAccessToken = "accessToken"
+)
+
+// AuthMapping is the Postman representation of an OpenAPI security
+// scheme. `Headers` is used for API keys sent in cookies, which Postman
+// auth does not support. `Variables` are the collection variables
+// referenced by `Auth` and `Headers`.
+type AuthMapping struct {
+	Auth      *postman2.Auth
+	Headers   []postman2.Header
+	Variables []postman2.Variable
+}
+
+// SecuritySchemeToPostman maps a security scheme to Postman auth. Tokens and
+// secrets are referenced as `{{<schemeName>_<field>}}` collection variables.
+// `scopes` are the requirement scopes used for OAuth 2.0; when empty, all
+// flow scopes are used. Supported schemes are HTTP bearer and basic, API
+// keys in headers, queries and cookies, and OAuth 2.0 authorization code
+// and client credentials flows. Nil is returned for unsupported schemes.
+func SecuritySchemeToPostman(schemeName string, scheme *oas3.SecurityScheme, scopes []string) *AuthMapping {
+	if scheme == nil {
+		return nil
+	}
+	am := &AuthMapping{}
+	switch scheme.Type {
+	case authSchemeHTTP:
+		switch strings.ToLower(scheme.Scheme) {
+		case authHTTPSchemeBearer:
+			am.Auth = postman2.NewAuth(postman2.AuthTypeBearer,
+				postman2.NewAuthAttribute("token", am.variable(schemeName, "token", scheme.BearerFormat)))
+		case authHTTPSchemeBasic:
+			am.Auth = postman2.NewAuth(postman2.AuthTypeBasic,
+				postman2.NewAuthAttribute("username", am.variable(schemeName, "username", "")),
+				postman2.NewAuthAttribute("password", am.variable(schemeName, "password", "")))
+		default:
+			return nil
+		}
+	case authSchemeAPIKey:
+		value := am.variable(schemeName, "apiKey", scheme.Description)
+		switch scheme.In {
+		case openapi3.InHeader, openapi3.InQuery:
+			am.Auth = postman2.NewAuth(postman2.AuthTypeAPIKey,
+				postman2.NewAuthAttribute("key", scheme.Name),
+				postman2.NewAuthAttribute("value", value),
+				postman2.NewAuthAttribute("in", scheme.In))
+		case openapi3.InCookie:
+			am.Headers = append(am.Headers, postman2.Header{
+				Key:   "Cookie",
+				Value: scheme.Name + "=" + value})
+		default:
+			return nil
+		}
+	case authSchemeOAuth2:
+		am.Auth = am.oauth2(schemeName, scheme.Flows, scopes)
+		if am.Auth == nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return am
+}
+
+func (am *AuthMapping) oauth2(schemeName string, flows *oas3.OAuthFlows, scopes []string) *postman2.Auth {
+	if flows == nil {
+		return nil
+	}
+	var flow *oas3.OAuthFlow
+	attrs := []postman2.AuthAttribute{}
+	switch {
+	case flows.AuthorizationCode != nil:
+		flow = flows.AuthorizationCode
+		attrs = append(attrs,
+			postman2.NewAuthAttribute("grant_type", oauth2GrantAuthCode),
+			postman2.NewAuthAttribute("authUrl", flow.AuthorizationURL),
+			postman2.NewAuthAttribute("redirect_uri", am.variable(schemeName, "redirectUri", "")))
+	case flows.ClientCredentials != nil:
+		flow = flows.ClientCredentials
+		attrs = append(attrs,
+			postman2.NewAuthAttribute("grant_type", oauth2GrantClientCreds))
+	default:
+		return nil
+	}
+	if len(scopes) == 0 {
+		scopes = maputil.Keys(flow.Scopes)
+	}
+	attrs = append(attrs,
+		postman2.NewAuthAttribute("accessTokenUrl", flow.TokenURL),
+		postman2.NewAuthAttribute("clientId", am.variable(schemeName, "clientId", "")),
+		postman2.NewAuthAttribute("clientSecret", am.variable(schemeName, "clientSecret", "")),
+		postman2.NewAuthAttribute("addTokenTo", "header"))
+	if len(scopes) > 0 {
+		attrs = append(attrs, postman2.NewAuthAttribute("scope", strings.Join(scopes, " ")))
+	}
+	if len(flow.RefreshURL) > 0 {
+		attrs = append(attrs, postman2.NewAuthAttribute("refreshTokenUrl", flow.RefreshURL))
+	}
+	attrs = append(attrs, postman2.NewAuthAttribute("accessToken", am.variable(schemeName, authVariableAccessToken, "")))
+	return postman2.NewAuth(postman2.AuthTypeOAuth2, attrs...)
+}
+
+// variable adds a collection variable and returns its `{{key}}` reference.
+func (am *AuthMapping) variable(schemeName, field, description string) string {
+	key := AuthVariableName(schemeName, field)
+	am.Variables = append(am.Variables, postman2.Variable{
+		Key:         key,
+		Value:       "",
+		Type:        "string",
+		Description: postman2.NewDescription(description)})
+	return "{{" + key + "}}"
+}
+
+var rxAuthVariableName = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// AuthVariableName returns the collection variable name for a security
+// scheme field, such as `bearerAuth_token`.
+func AuthVariableName(schemeName, field string) string {
+	return rxAuthVariableName.ReplaceAllString(strings.TrimSpace(schemeName), "_") + "_" + field
+}
+
+// SecurityRequirementsToPostman maps the first security requirement that
+// has a Postman equivalent to Postman auth. Postman supports one auth per
+// request, so requirements with a single scheme are tried before those
+// listing several, which use the first supported scheme by name. Empty
+// requirements, meaning no security, map to `noauth`.
+func SecurityRequirementsToPostman(spec *openapi3.Spec, secReqs oas3.SecurityRequirements) *AuthMapping {
+	if len(secReqs) == 0 {
+		return &AuthMapping{Auth: &postman2.Auth{Type: postman2.AuthTypeNoAuth}}
+	}
+	for _, secReq := range secReqs {
+		if len(secReq) == 0 {
+			return &AuthMapping{Auth: &postman2.Auth{Type: postman2.AuthTypeNoAuth}}
+		} else if len(secReq) == 1 {
+			if am := securityRequirementToPostman(spec, secReq); am != nil {
+				return am
+			}
+		}
+	}
+	for _, secReq := range secReqs {
+		if len(secReq) > 1 {
+			if am := securityRequirementToPostman(spec, secReq); am != nil {
+				return am
+			}
+		}
+	}
+	return nil
+}
+
+func securityRequirementToPostman(spec *openapi3.Spec, secReq oas3.SecurityRequirement) *AuthMapping {
+	if spec == nil || spec.Components == nil {
+		return nil
+	}
+	for _, schemeName := range maputil.Keys(secReq) {
+		schemeRef := spec.Components.SecuritySchemes[schemeName]
+		if schemeRef == nil || schemeRef.Value == nil {
+			continue
+		}
+		if am := SecuritySchemeToPostman(schemeName, schemeRef.Value, secReq[schemeName]); am != nil {
+			return am
+		}
+	}
+	return nil
+}
+
+// SecuritySchemesPostmanVariables returns the collection variables for all
+// supported security schemes in the spec, sorted by key.
+func SecuritySchemesPostmanVariables(spec *openapi3.Spec) []postman2.Variable {
+	vars := []postman2.Variable{}
+	if spec == nil || spec.Components == nil {
+		return vars
+	}
+	for _, schemeName := range maputil.Keys(spec.Components.SecuritySchemes) {
+		schemeRef := spec.Components.SecuritySchemes[schemeName]
+		if schemeRef == nil {
+			continue
+		}
+		if am := SecuritySchemeToPostman(schemeName, schemeRef.Value, nil); am != nil {
+			vars = append(vars, am.Variables...)
+		}
+	}
+	sort.SliceStable(vars, func(i, j int) bool { return vars[i].Key < vars[j].Key })
+	return vars
+}
@@ -0,0 +1,140 @@
+package openapi3postman2
+
+import (
+	"encoding/json"
+	"testing"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const authSpec = `{
+"openapi":"3.0.3","info":{"title":"Auth","version":"1.0.0"},
+"servers":[{"url":"https://api.example.com"}],
+"security":[{"bearerAuth":[]}],
+"paths":{
+  "/default":{"get":{"operationId":"getDefault","summary":"Default","responses":{"200":{"description":"OK"}}}},
+  "/public":{"get":{"operationId":"getPublic","summary":"Public","security":[],"responses":{"200":{"description":"OK"}}}},
+  "/session":{"get":{"operationId":"getSession","summary":"Session","security":[{"cookieKey":[]}],"responses":{"200":{"description":"OK"}}}},
+  "/oidc":{"get":{"operationId":"getOIDC","summary":"OIDC","security":[{"openId":[]}],"responses":{"200":{"description":"OK"}}}}},
+"components":{"securitySchemes":{
+  "bearerAuth":{"type":"http","scheme":"bearer","bearerFormat":"JWT"},
+  "basicAuth":{"type":"http","scheme":"basic"},
+  "headerKey":{"type":"apiKey","in":"header","name":"X-API-Key"},
+  "queryKey":{"type":"apiKey","in":"query","name":"api_key"},
+  "cookieKey":{"type":"apiKey","in":"cookie","name":"session"},
+  "ccOAuth":{"type":"oauth2","flows":{"clientCredentials":{"tokenUrl":"https://auth.example.com/token","scopes":{"read":"Read","write":"Write"}}}},
+  "acOAuth":{"type":"oauth2","flows":{"authorizationCode":{"authorizationUrl":"https://auth.example.com/authorize","tokenUrl":"https://auth.example.com/token","refreshUrl":"https://auth.example.com/refresh","scopes":{"read":"Read"}}}},
+  "openId":{"type":"openIdConnect","openIdConnectUrl":"https://auth.example.com/.well-known/openid-configuration"}}}}`
+
+var authTests = []struct {
+	schemeName  string
+	scopes      []string
+	wantAuth    string
+	wantHeaders string
+}{
+	{"bearerAuth", nil, `{"type":"bearer","bearer":[{"key":"token","value":"{{bearerAuth_token}}","type":"string"}]}`, `null`},
+	{"basicAuth", nil, `{"type":"basic","basic":[{"key":"username","value":"{{basicAuth_username}}","type":"string"},{"key":"password","value":"{{basicAuth_password}}","type":"string"}]}`, `null`},
+	{"headerKey", nil, `{"type":"apikey","apikey":[{"key":"key","value":"X-API-Key","type":"string"},{"key":"value","value":"{{headerKey_apiKey}}","type":"string"},{"key":"in","value":"header","type":"string"}]}`, `null`},
+	{"queryKey", nil, `{"type":"apikey","apikey":[{"key":"key","value":"api_key","type":"string"},{"key":"value","value":"{{queryKey_apiKey}}","type":"string"},{"key":"in","value":"query","type":"string"}]}`, `null`},
+	{"cookieKey", nil, `null`, `[{"key":"Cookie","value":"session={{cookieKey_apiKey}}"}]`},
+	{"ccOAuth", []string{"read"}, `{"type":"oauth2","oauth2":[{"key":"grant_type","value":"client_credentials","type":"string"},{"key":"accessTokenUrl","value":"https://auth.example.com/token","type":"string"},{"key":"clientId","value":"{{ccOAuth_clientId}}","type":"string"},{"key":"clientSecret","value":"{{ccOAuth_clientSecret}}","type":"string"},{"key":"addTokenTo","value":"header","type":"string"},{"key":"scope","value":"read","type":"string"},{"key":"accessToken","value":"{{ccOAuth_accessToken}}","type":"string"}]}`, `null`},
+	{"acOAuth", nil, `{"type":"oauth2","oauth2":[{"key":"grant_type","value":"authorization_code","type":"string"},{"key":"authUrl","value":"https://auth.example.com/authorize","type":"string"},{"key":"redirect_uri","value":"{{acOAuth_redirectUri}}","type":"string"},{"key":"accessTokenUrl","value":"https://auth.example.com/token","type":"string"},{"key":"clientId","value":"{{acOAuth_clientId}}","type":"string"},{"key":"clientSecret","value":"{{acOAuth_clientSecret}}","type":"string"},{"key":"addTokenTo","value":"header","type":"string"},{"key":"scope","value":"read","type":"string"},{"key":"refreshTokenUrl","value":"https://auth.example.com/refresh","type":"string"},{"key":"accessToken","value":"{{acOAuth_accessToken}}","type":"string"}]}`, `null`},
+}
+
+// TestSecurityRequirementsToPostman ensures each supported security scheme
+// maps to the expected Postman auth or headers.
+func TestSecurityRequirementsToPostman(t *testing.T) {
+	spec := syncTestSpec(t, authSpec)
+	for _, tt := range authTests {
+		am := SecurityRequirementsToPostman(spec, oas3.SecurityRequirements{{tt.schemeName: tt.scopes}})
+		if am == nil {
+			t.Fatalf("openapi3postman2.SecurityRequirementsToPostman(%s) Mismatch: want mapping, got nil", tt.schemeName)
+		}
+		if got := authTestJSON(t, am.Auth); got != tt.wantAuth {
+			t.Errorf("openapi3postman2.SecurityRequirementsToPostman(%s) Auth Mismatch: want [%s], got [%s]", tt.schemeName, tt.wantAuth, got)
+		}
+		if got := authTestJSON(t, am.Headers); got != tt.wantHeaders {
+			t.Errorf("openapi3postman2.SecurityRequirementsToPostman(%s) Headers Mismatch: want [%s], got [%s]", tt.schemeName, tt.wantHeaders, got)
+		}
+	}
+
+	if am := SecurityRequirementsToPostman(spec, oas3.SecurityRequirements{{"openId": nil}}); am != nil {
+		t.Errorf("openapi3postman2.SecurityRequirementsToPostman(openId) Mismatch: want nil, got [%s]", authTestJSON(t, am.Auth))
+	}
+	if am := SecurityRequirementsToPostman(spec, oas3.SecurityRequirements{}); am == nil || authTestJSON(t, am.Auth) != `{"type":"noauth"}` {
+		t.Errorf("openapi3postman2.SecurityRequirementsToPostman() Mismatch: want [noauth] for empty requirements")
+	}
+	for _, tt := range authAlternativeTests {
+		am := SecurityRequirementsToPostman(spec, tt.secReqs)
+		got := "nil"
+		if am != nil {
+			got = am.Auth.Type
+		}
+		if got != tt.wantType {
+			t.Errorf("openapi3postman2.SecurityRequirementsToPostman(%v) Mismatch: want [%s], got [%s]", tt.secReqs, tt.wantType, got)
+		}
+	}
+}
+
+var authAlternativeTests = []struct {
+	secReqs  oas3.SecurityRequirements
+	wantType string
+}{
+	{oas3.SecurityRequirements{{"openId": nil}, {"queryKey": nil}}, postman2.AuthTypeAPIKey},
+	{oas3.SecurityRequirements{{"headerKey": nil, "openId": nil}, {"basicAuth": nil}}, postman2.AuthTypeBasic},
+	{oas3.SecurityRequirements{{"headerKey": nil, "openId": nil}}, postman2.AuthTypeAPIKey},
+	{oas3.SecurityRequirements{{"openId": nil}, {}, {"bearerAuth": nil}}, postman2.AuthTypeNoAuth},
+	{oas3.SecurityRequirements{{"openId": nil}}, "nil"},
+}
+
+var authConvertTests = []struct {
+	name        string
+	wantAuth    string
+	wantHeaders string
+}{
+	{"Default", `null`, `null`},
+	{"Public", `{"type":"noauth"}`, `null`},
+	{"Session", `{"type":"noauth"}`, `[{"key":"Cookie","value":"session={{cookieKey_apiKey}}"}]`},
+	{"OIDC", `{"type":"noauth"}`, `null`},
+}
+
+// TestConvertSpecAuth ensures the collection auth comes from the spec
+// security and operations override it, using `noauth` for operation
+// schemes that cannot be mapped.
+func TestConvertSpecAuth(t *testing.T) {
+	pman, err := ConvertSpec(Configuration{}, syncTestSpec(t, authSpec))
+	if err != nil {
+		t.Fatalf("openapi3postman2.ConvertSpec() Error [%s]", err.Error())
+	}
+	if pman.Info.Schema != postman2.SchemaURL200 {
+		t.Errorf("openapi3postman2.ConvertSpec() Schema Mismatch: want [%s], got [%s]", postman2.SchemaURL200, pman.Info.Schema)
+	}
+	if got := authTestJSON(t, pman.Auth); got != authTests[0].wantAuth {
+		t.Errorf("openapi3postman2.ConvertSpec() Auth Mismatch: want [%s], got [%s]", authTests[0].wantAuth, got)
+	}
+	if len(pman.Variable) != 13 {
+		t.Errorf("openapi3postman2.ConvertSpec() Variable Mismatch: want [13], got [%d]", len(pman.Variable))
+	}
+	for _, tt := range authConvertTests {
+		item := pman.GetOrNewFolder(tt.name)
+		if item.Request == nil {
+			t.Fatalf("openapi3postman2.ConvertSpec() Mismatch: want item [%s]", tt.name)
+		}
+		if got := authTestJSON(t, item.Request.Auth); got != tt.wantAuth {
+			t.Errorf("openapi3postman2.ConvertSpec() Auth Mismatch: item [%s] want [%s], got [%s]", tt.name, tt.wantAuth, got)
+		}
+		if got := authTestJSON(t, item.Request.Header); got != tt.wantHeaders {
+			t.Errorf("openapi3postman2.ConvertSpec() Headers Mismatch: item [%s] want [%s], got [%s]", tt.name, tt.wantHeaders, got)
+		}
+	}
+}
+
+func authTestJSON(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal() Error [%s]", err.Error())
+	}
+	return string(b)
+}
@@ -23,7 +23,22 @@ type Configuration struct {
 	PostmanURLHostname       string            `json:"postmanURLHostname,omitempty"`
 	PostmanHeaders           []postman2.Header `json:"postmanHeaders,omitempty"`
 	UseXTagGroups            bool              `json:"useXTagGroups,omitempty"`
-	RequestBodyFunc          func(urlPath string) string
+	// AddTestScripts adds `test` event scripts that check responses against the spec.
+	AddTestScripts bool `json:"addTestScripts,omitempty"`
+	// PostmanBaseURLVariable uses `{{<variable>}}` as the server URL so environments
+	// from `Environments()` can switch servers. It is ignored when PostmanServerURL is set.
+	PostmanBaseURLVariable string `json:"postmanBaseUrlVariable,omitempty"`
+	// FolderStrategy is one of the `FolderStrategy*` values. By default,
+	// `tagGroup` is used when the spec has `x-tagGroups` and `tag` otherwise.
+	FolderStrategy string `json:"folderStrategy,omitempty"`
+	// ItemSortBy orders requests within folders by `ItemSort*` keys, with later
+	// keys breaking ties. By default, requests are ordered by path and method name.
+	ItemSortBy []string `json:"itemSortBy,omitempty"`
+	// MethodOrder is the method order used by `ItemSortMethod`. It defaults to
+	// DefaultMethodOrder.
+	MethodOrder []string `json:"methodOrder,omitempty"`
+	// RequestBodyFunc overrides the generated request body when it returns a non-empty string.
+	RequestBodyFunc func(urlPath string) string
 }
 
 func ConfigurationReadFile(filename string) (Configuration, error) {
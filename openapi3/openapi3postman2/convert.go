@@ -91,19 +91,38 @@ func ConvertSpec(cfg Configuration, oas3spec *openapi3.Spec) (postman2.Collectio
 // Merge creates a Postman 2.0 collection from a configuration, base Postman
 // 2.0 collection and Swagger 2.0 spec
 func Merge(cfg Configuration, pman postman2.Collection, oas3spec *openapi3.Spec) (postman2.Collection, error) {
+	return merge(cfg, pman, oas3spec, false)
+}
+
+// mergeMethods are the operation methods converted, in item order.
+var mergeMethods = []string{http.MethodDelete, http.MethodGet, http.MethodPatch, http.MethodPost, http.MethodPut}
+
+// merge implements `Merge`. When `syncKeys` is set, generated items carry
+// the operation key used by `Sync`.
+func merge(cfg Configuration, pman postman2.Collection, oas3spec *openapi3.Spec, syncKeys bool) (postman2.Collection, error) {
 	if len(pman.Info.Name) == 0 {
 		pman.Info.Name = strings.TrimSpace(oas3spec.Info.Title)
 	}
-	if len(pman.Info.Description) == 0 {
-		pman.Info.Description = strings.TrimSpace(oas3spec.Info.Description)
+	if pman.Info.Description == nil {
+		pman.Info.Description = postman2.NewDescription(oas3spec.Info.Description)
 	}
 	if len(pman.Info.Schema) == 0 {
-		pman.Info.Schema = "https://schema.getpostman.com/json/collection/v2.0.0/collection.json"
+		pman.Info.Schema = postman2.SchemaURL200
 	}
 
-	pman, err := CreateTagsAndTagGroups(pman, oas3spec)
-	if err != nil {
-		return pman, err
+	if len(strings.TrimSpace(cfg.PostmanBaseURLVariable)) > 0 && len(oas3spec.Servers) > 0 {
+		// The first server is the default when no environment is selected.
+		for _, val := range serverEnvironmentValues(cfg, oas3spec.Servers[0]) {
+			pman.UpsertVariable(postman2.Variable{Key: val.Key, Value: val.Value, Type: "string"})
+		}
+	}
+	for _, v := range SecuritySchemesPostmanVariables(oas3spec) {
+		pman.UpsertVariable(v)
+	}
+	if pman.Auth == nil && len(oas3spec.Security) > 0 {
+		if am := SecurityRequirementsToPostman(oas3spec, oas3spec.Security); am != nil {
+			pman.Auth = am.Auth
+		}
 	}
 
 	// tagGroupSet, err := openapi3.SpecTagGroups(oas3spec)
@@ -113,6 +132,13 @@ func Merge(cfg Configuration, pman postman2.Collection, oas3spec *openapi3.Spec)
 	if err != nil {
 		return pman, err
 	}
+	strategy, err := cfg.folderStrategy(tagGroupSet)
+	if err != nil {
+		return pman, err
+	}
+	if err := cfg.checkItemSortBy(); err != nil {
+		return pman, err
+	}
 
 	var urls []string
 	pathsMap := oas3spec.Paths.Map()
@@ -122,46 +148,35 @@ func Merge(cfg Configuration, pman postman2.Collection, oas3spec *openapi3.Spec)
 	}
 	sort.Strings(urls)
 
+	pman, err = createFolders(pman, oas3spec, strategy, tagGroupSet, urls)
+	if err != nil {
+		return pman, err
+	}
+
+	mops := []mergeOperation{}
 	for _, url := range urls {
 		// path := oas3spec.Paths[url] // *PathItem // getkin v0.121.0 to v0.122.0
 		path := oas3spec.Paths.Find(url)
 
-		if path.Delete != nil {
-			pitem, err := Openapi3OperationToPostman2APIItem(cfg, oas3spec, url, http.MethodDelete, path.Delete)
-			if err != nil {
-				return pman, err
+		for _, method := range mergeMethods {
+			op := path.GetOperation(method)
+			if op == nil {
+				continue
 			}
-			pman = postmanAddItemToFolders(pman, pitem, path.Delete.Tags, tagGroupSet)
-		}
-		if path.Get != nil {
-			pitem, err := Openapi3OperationToPostman2APIItem(cfg, oas3spec, url, http.MethodGet, path.Get)
+			pitem, err := Openapi3OperationToPostman2APIItem(cfg, oas3spec, url, method, op)
 			if err != nil {
 				return pman, err
 			}
-			pman = postmanAddItemToFolders(pman, pitem, path.Get.Tags, tagGroupSet)
-		}
-		if path.Patch != nil {
-			pitem, err := Openapi3OperationToPostman2APIItem(cfg, oas3spec, url, http.MethodPatch, path.Patch)
-			if err != nil {
-				return pman, err
+			if syncKeys {
+				setItemSyncMeta(pitem, syncMeta{Key: OperationKey(method, url, op)})
 			}
-			pman = postmanAddItemToFolders(pman, pitem, path.Patch.Tags, tagGroupSet)
-		}
-		if path.Post != nil {
-			pitem, err := Openapi3OperationToPostman2APIItem(cfg, oas3spec, url, http.MethodPost, path.Post)
-			if err != nil {
-				return pman, err
-			}
-			pman = postmanAddItemToFolders(pman, pitem, path.Post.Tags, tagGroupSet)
-		}
-		if path.Put != nil {
-			pitem, err := Openapi3OperationToPostman2APIItem(cfg, oas3spec, url, http.MethodPut, path.Put)
-			if err != nil {
-				return pman, err
-			}
-			pman = postmanAddItemToFolders(pman, pitem, path.Put.Tags, tagGroupSet)
+			mops = append(mops, mergeOperation{path: url, method: method, op: op, item: pitem})
 		}
 	}
+	cfg.sortMergeOperations(mops)
+	for _, mop := range mops {
+		pman = addItemToFolders(pman, mop, strategy, tagGroupSet)
+	}
 	return pman, nil
 }
 
@@ -222,12 +237,12 @@ func Openapi3OperationToPostman2APIItem(cfg Configuration, oas3spec *openapi3.Sp
 	}
 
 	if len(strings.TrimSpace(operation.Description)) > 0 {
-		item.Request.Description = strings.TrimSpace(operation.Description)
+		item.Request.Description = postman2.NewDescription(operation.Description)
 	}
 
 	headers := cfg.PostmanHeaders
 
-	headers, _, _, err := postman2.AddOperationReqResMediaTypeHeaders(
+	headers, reqMediaType, _, err := postman2.AddOperationReqResMediaTypeHeaders(
 		headers, operation, oas3spec,
 		postman2.DefaultMediaTypePreferencesSlice(),
 		postman2.DefaultMediaTypePreferencesSlice(),
@@ -236,6 +251,27 @@ func Openapi3OperationToPostman2APIItem(cfg Configuration, oas3spec *openapi3.Sp
 		return nil, err
 	}
 
+	// Operation security overrides the collection auth. Operation schemes
+	// that cannot be mapped use `noauth` rather than inheriting the
+	// collection auth. Cookie API keys are sent as headers because Postman
+	// auth does not support them.
+	secReqs := oas3spec.Security
+	if operation.Security != nil {
+		secReqs = *operation.Security
+	}
+	if operation.Security != nil || len(secReqs) > 0 {
+		am := SecurityRequirementsToPostman(oas3spec, secReqs)
+		if operation.Security != nil {
+			item.Request.Auth = &postman2.Auth{Type: postman2.AuthTypeNoAuth}
+			if am != nil && am.Auth != nil {
+				item.Request.Auth = am.Auth
+			}
+		}
+		if am != nil && len(am.Headers) > 0 {
+			headers = append(append([]postman2.Header{}, headers...), am.Headers...)
+		}
+	}
+
 	item.Request.Header = headers
 
 	params := ParamsOpenAPI3ToPostman(operation.Parameters)
@@ -254,6 +290,31 @@ func Openapi3OperationToPostman2APIItem(cfg Configuration, oas3spec *openapi3.Sp
 				Raw:  bodyString}
 		}
 	}
+	if item.Request.Body == nil && len(reqMediaType) > 0 {
+		body, err := RequestBodyOpenAPI3ToPostman(oas3spec, operation, reqMediaType)
+		if err != nil {
+			return nil, err
+		}
+		item.Request.Body = body
+	}
+
+	resps, err := ResponsesOpenAPI3ToPostman(oas3spec, operation, item.Request)
+	if err != nil {
+		return nil, err
+	}
+	if len(resps) > 0 {
+		item.Response = resps
+	}
+
+	if cfg.AddTestScripts {
+		event, err := TestScriptOpenAPI3ToPostman(oas3spec, operation)
+		if err != nil {
+			return nil, err
+		}
+		if event != nil {
+			item.Event = append(item.Event, *event)
+		}
+	}
 
 	return item, nil
 }
@@ -263,6 +324,9 @@ func BuildPostmanURL(cfg Configuration, spec *openapi3.Spec, specPath string, op
 	specServerURL := specMore.ServerURL(0)
 	partsOverrideURL := []string{}
 	cfg.PostmanServerURL = strings.TrimSpace(cfg.PostmanServerURL)
+	if len(cfg.PostmanServerURL) == 0 && len(strings.TrimSpace(cfg.PostmanBaseURLVariable)) > 0 {
+		cfg.PostmanServerURL = "{{" + cfg.BaseURLVariable() + "}}"
+	}
 	if len(cfg.PostmanServerURL) > 0 {
 		partsOverrideURL = append(partsOverrideURL, cfg.PostmanServerURL)
 	}
@@ -328,7 +392,7 @@ func ParamsOpenAPI3ToPostman(oparams []*oas3.ParameterRef) postman2.URLParameter
 				postman2.URLQuery{
 					Key:         oparam.Name,
 					Value:       schemaToString(oparam.Schema),
-					Description: oparam.Description,
+					Description: postman2.NewDescription(oparam.Description),
 					Disabled:    true,
 				},
 			)
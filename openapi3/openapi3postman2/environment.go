@@ -0,0 +1,159 @@
+package openapi3postman2
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const (
+	DefaultBaseURLVariable = "baseUrl"
+
+	environmentFileSuffix = ".postman_environment.json"
+)
+
+// authSecretFields are the auth variable fields stored as `secret` values.
+var authSecretFields = map[string]bool{
+	"accessToken":  true,
+	"apiKey":       true,
+	"clientSecret": true,
+	"password":     true,
+	"token":        true,
+}
+
+// BaseURLVariable returns `Configuration.PostmanBaseURLVariable` or
+// `DefaultBaseURLVariable`.
+func (cfg Configuration) BaseURLVariable() string {
+	if v := strings.TrimSpace(cfg.PostmanBaseURLVariable); len(v) > 0 {
+		return v
+	}
+	return DefaultBaseURLVariable
+}
+
+// WithDefaultBaseURLVariable returns the configuration with
+// `PostmanBaseURLVariable` set to `DefaultBaseURLVariable` when neither it
+// nor `PostmanServerURL` is set, so request URLs reference the base URL
+// variable defined in `Environments()`.
+func (cfg Configuration) WithDefaultBaseURLVariable() Configuration {
+	if len(strings.TrimSpace(cfg.PostmanServerURL)) == 0 &&
+		len(strings.TrimSpace(cfg.PostmanBaseURLVariable)) == 0 {
+		cfg.PostmanBaseURLVariable = DefaultBaseURLVariable
+	}
+	return cfg
+}
+
+var rxServerVariable = regexp.MustCompile(`{([^{}]+)}`)
+
+// Environments returns a Postman environment for each OpenAPI server. Each
+// environment has a base URL variable, a variable for each server variable
+// using its default, and empty auth variables for each security scheme.
+// Server variables in the base URL are Postman variable references, e.g.
+// `https://{{region}}.example.com`, and server variable enums are listed
+// in descriptions. Use `Configuration.PostmanBaseURLVariable` so collection
+// URLs reference the base URL variable.
+func Environments(cfg Configuration, spec *openapi3.Spec) []postman2.Environment {
+	envs := []postman2.Environment{}
+	if spec == nil {
+		return envs
+	}
+	title := ""
+	if spec.Info != nil {
+		title = strings.TrimSpace(spec.Info.Title)
+	}
+	authVars := SecuritySchemesPostmanVariables(spec)
+	for i, server := range spec.Servers {
+		if server == nil {
+			continue
+		}
+		name := strings.TrimSpace(server.Description)
+		if len(name) == 0 {
+			name = strings.TrimSpace(server.URL)
+		}
+		if len(name) == 0 {
+			name = fmt.Sprintf("Server %d", i+1)
+		}
+		if len(title) > 0 {
+			name = title + " - " + name
+		}
+		env := postman2.NewEnvironment(name)
+		for _, val := range serverEnvironmentValues(cfg, server) {
+			env.Set(val)
+		}
+		for _, v := range authVars {
+			val := postman2.EnvironmentValue{
+				Key:         v.Key,
+				Type:        postman2.EnvironmentValueTypeDefault,
+				Enabled:     true,
+				Description: v.Description.String()}
+			if authSecretFields[v.Key[strings.LastIndex(v.Key, "_")+1:]] {
+				val.Type = postman2.EnvironmentValueTypeSecret
+			}
+			env.Set(val)
+		}
+		envs = append(envs, env)
+	}
+	return envs
+}
+
+// serverEnvironmentValues returns the base URL and server variable values
+// for a server.
+func serverEnvironmentValues(cfg Configuration, server *oas3.Server) []postman2.EnvironmentValue {
+	vals := []postman2.EnvironmentValue{{
+		Key:     cfg.BaseURLVariable(),
+		Value:   rxServerVariable.ReplaceAllString(strings.TrimSpace(server.URL), "{{$1}}"),
+		Type:    postman2.EnvironmentValueTypeDefault,
+		Enabled: true}}
+	for _, varName := range maputil.Keys(server.Variables) {
+		sv := server.Variables[varName]
+		if sv == nil {
+			continue
+		}
+		val := postman2.EnvironmentValue{
+			Key:         varName,
+			Value:       sv.Default,
+			Type:        postman2.EnvironmentValueTypeDefault,
+			Enabled:     true,
+			Description: strings.TrimSpace(sv.Description)}
+		if len(sv.Enum) > 0 {
+			if len(val.Description) > 0 {
+				val.Description += " (allowed values: " + strings.Join(sv.Enum, ", ") + ")"
+			} else {
+				val.Description = "Allowed values: " + strings.Join(sv.Enum, ", ")
+			}
+		}
+		vals = append(vals, val)
+	}
+	return vals
+}
+
+var rxFilenameUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// WriteEnvironmentFiles writes `Environments()` to `dir` as
+// `<name>.postman_environment.json` files and returns the filenames.
+func WriteEnvironmentFiles(cfg Configuration, spec *openapi3.Spec, dir string, perm os.FileMode) ([]string, error) {
+	filenames := []string{}
+	used := map[string]int{}
+	for _, env := range Environments(cfg, spec) {
+		base := strings.Trim(rxFilenameUnsafe.ReplaceAllString(strings.ToLower(env.Name), "-"), "-")
+		if len(base) == 0 {
+			base = "environment"
+		}
+		used[base]++
+		if used[base] > 1 {
+			base = fmt.Sprintf("%s-%d", base, used[base])
+		}
+		filename := filepath.Join(dir, base+environmentFileSuffix)
+		if err := env.WriteFile(filename, perm); err != nil {
+			return filenames, err
+		}
+		filenames = append(filenames, filename)
+	}
+	return filenames, nil
+}
@@ -0,0 +1,120 @@
+package openapi3postman2
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grokify/spectrum/postman2"
+)
+
+const environmentSpec = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"servers":[
+  {"url":"https://{region}.example.com/{basePath}","description":"Production",
+    "variables":{"region":{"default":"us","enum":["us","eu"],"description":"Region"},"basePath":{"default":"v1"}}},
+  {"url":"https://sandbox.example.com","description":"Production"}],
+"paths":{"/pets":{"get":{"operationId":"listPets","summary":"List pets","responses":{"200":{"description":"OK"}}}}},
+"components":{"securitySchemes":{"bearerAuth":{"type":"http","scheme":"bearer"},"basicAuth":{"type":"http","scheme":"basic"}}}}`
+
+var environmentsTests = []struct {
+	cfg  Configuration
+	want []string
+}{
+	{Configuration{}, []string{
+		"Pets - Production|baseUrl=https://{{region}}.example.com/{{basePath}}:default, basePath=v1:default, region=us:default:Region (allowed values: us, eu), basicAuth_password=:secret, basicAuth_username=:default, bearerAuth_token=:secret",
+		"Pets - Production|baseUrl=https://sandbox.example.com:default, basicAuth_password=:secret, basicAuth_username=:default, bearerAuth_token=:secret"}},
+	{Configuration{PostmanBaseURLVariable: "host"}, []string{
+		"Pets - Production|host=https://{{region}}.example.com/{{basePath}}:default, basePath=v1:default, region=us:default:Region (allowed values: us, eu), basicAuth_password=:secret, basicAuth_username=:default, bearerAuth_token=:secret",
+		"Pets - Production|host=https://sandbox.example.com:default, basicAuth_password=:secret, basicAuth_username=:default, bearerAuth_token=:secret"}},
+}
+
+// TestEnvironments ensures an environment is built for each server with
+// base URL, server variable and auth variable values.
+func TestEnvironments(t *testing.T) {
+	spec := syncTestSpec(t, environmentSpec)
+	for _, tt := range environmentsTests {
+		envs := Environments(tt.cfg, spec)
+		if len(envs) != len(tt.want) {
+			t.Fatalf("openapi3postman2.Environments() Count Mismatch: want [%d], got [%d]", len(tt.want), len(envs))
+		}
+		for i, env := range envs {
+			if got := environmentTestString(env); got != tt.want[i] {
+				t.Errorf("openapi3postman2.Environments() Mismatch at [%d]: want [%s], got [%s]", i, tt.want[i], got)
+			}
+		}
+	}
+}
+
+// TestWriteEnvironmentFiles ensures environment files are named from the
+// environment names, de-duplicated and readable.
+func TestWriteEnvironmentFiles(t *testing.T) {
+	spec := syncTestSpec(t, environmentSpec)
+	dir := t.TempDir()
+	filenames, err := WriteEnvironmentFiles(Configuration{}, spec, dir, 0600)
+	if err != nil {
+		t.Fatalf("openapi3postman2.WriteEnvironmentFiles() Error [%s]", err.Error())
+	}
+	want := []string{
+		filepath.Join(dir, "pets-production.postman_environment.json"),
+		filepath.Join(dir, "pets-production-2.postman_environment.json")}
+	if strings.Join(filenames, ",") != strings.Join(want, ",") {
+		t.Fatalf("openapi3postman2.WriteEnvironmentFiles() Mismatch: want [%s], got [%s]", strings.Join(want, ","), strings.Join(filenames, ","))
+	}
+	env, err := postman2.ReadEnvironmentFile(filenames[1])
+	if err != nil {
+		t.Fatalf("postman2.ReadEnvironmentFile() Error [%s]", err.Error())
+	}
+	if got := environmentTestString(env); got != environmentsTests[0].want[1] {
+		t.Errorf("postman2.ReadEnvironmentFile() Mismatch: want [%s], got [%s]", environmentsTests[0].want[1], got)
+	}
+}
+
+var baseURLVariableTests = []struct {
+	cfg             Configuration
+	wantVariable    string
+	wantCfgVariable string
+}{
+	{Configuration{}, DefaultBaseURLVariable, DefaultBaseURLVariable},
+	{Configuration{PostmanBaseURLVariable: " host "}, "host", " host "},
+	{Configuration{PostmanServerURL: "https://api.example.com"}, DefaultBaseURLVariable, ""},
+}
+
+// TestWithDefaultBaseURLVariable ensures collections use the default base
+// URL variable unless a variable or server URL is configured.
+func TestWithDefaultBaseURLVariable(t *testing.T) {
+	for _, tt := range baseURLVariableTests {
+		if got := tt.cfg.BaseURLVariable(); got != tt.wantVariable {
+			t.Errorf("Configuration.BaseURLVariable() Mismatch: want [%s], got [%s]", tt.wantVariable, got)
+		}
+		if got := tt.cfg.WithDefaultBaseURLVariable().PostmanBaseURLVariable; got != tt.wantCfgVariable {
+			t.Errorf("Configuration.WithDefaultBaseURLVariable() Mismatch: want [%s], got [%s]", tt.wantCfgVariable, got)
+		}
+	}
+
+	pman, err := ConvertSpec(Configuration{}.WithDefaultBaseURLVariable(), syncTestSpec(t, environmentSpec))
+	if err != nil {
+		t.Fatalf("openapi3postman2.ConvertSpec() Error [%s]", err.Error())
+	}
+	item := pman.GetOrNewFolder("List pets")
+	if item.Request == nil || item.Request.URL == nil || !strings.HasPrefix(item.Request.URL.Raw, "{{baseUrl}}/pets") {
+		t.Errorf("openapi3postman2.ConvertSpec() Mismatch: want request URL with [{{baseUrl}}] prefix")
+	}
+}
+
+// environmentTestString returns the environment name and its values as
+// `key=value:type[:description]`.
+func environmentTestString(env postman2.Environment) string {
+	vals := []string{}
+	for _, val := range env.Values {
+		s := val.Key + "=" + val.Value + ":" + val.Type
+		if len(val.Description) > 0 {
+			s += ":" + val.Description
+		}
+		if !val.Enabled {
+			s += ":disabled"
+		}
+		vals = append(vals, s)
+	}
+	return env.Name + "|" + strings.Join(vals, ", ")
+}
@@ -1,7 +1,10 @@
 package openapi3postman2
 
 import (
+	"cmp"
 	"fmt"
+	"net/http"
+	"sort"
 	"strings"
 
 	oas3 "github.com/getkin/kin-openapi/openapi3"
@@ -11,6 +14,224 @@ import (
 	"github.com/grokify/spectrum/postman2"
 )
 
+const (
+	// FolderStrategyTag adds a folder for each tag in the order of the spec
+	// `tags`, followed by undeclared operation tags by name.
+	FolderStrategyTag = "tag"
+	// FolderStrategyTagGroup adds a folder for each `x-tagGroups` group with
+	// sub folders for its tags. Tags outside groups are added as for `tag`.
+	FolderStrategyTagGroup = "tagGroup"
+	// FolderStrategyPathSegment adds nested folders that mirror path
+	// segments, e.g. `accounts` > `{id}` > `extensions`.
+	FolderStrategyPathSegment = "pathSegment"
+
+	ItemSortPath    = "path"
+	ItemSortMethod  = "method"
+	ItemSortSummary = "summary"
+)
+
+// DefaultMethodOrder is the method order used by `ItemSortMethod`.
+var DefaultMethodOrder = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+func (cfg Configuration) folderStrategy(tagGroupSet taggroups.TagGroupSet) (string, error) {
+	switch strings.TrimSpace(cfg.FolderStrategy) {
+	case "":
+		if len(tagGroupSet.TagGroups) > 0 {
+			return FolderStrategyTagGroup, nil
+		}
+		return FolderStrategyTag, nil
+	case FolderStrategyTag:
+		return FolderStrategyTag, nil
+	case FolderStrategyTagGroup:
+		return FolderStrategyTagGroup, nil
+	case FolderStrategyPathSegment:
+		return FolderStrategyPathSegment, nil
+	default:
+		return "", fmt.Errorf("unknown folder strategy [%s]", cfg.FolderStrategy)
+	}
+}
+
+func (cfg Configuration) checkItemSortBy() error {
+	for _, key := range cfg.ItemSortBy {
+		if key != ItemSortPath && key != ItemSortMethod && key != ItemSortSummary {
+			return fmt.Errorf("unknown item sort key [%s]", key)
+		}
+	}
+	return nil
+}
+
+// mergeOperation is a generated request item and its operation.
+type mergeOperation struct {
+	path   string
+	method string
+	op     *oas3.Operation
+	item   *postman2.Item
+}
+
+// sortMergeOperations orders operations by `cfg.ItemSortBy`, then path and
+// method. Without `ItemSortBy`, the generated order is kept.
+func (cfg Configuration) sortMergeOperations(mops []mergeOperation) {
+	if len(cfg.ItemSortBy) == 0 {
+		return
+	}
+	methodOrder := cfg.MethodOrder
+	if len(methodOrder) == 0 {
+		methodOrder = DefaultMethodOrder
+	}
+	methodIndex := func(method string) int {
+		for i, m := range methodOrder {
+			if strings.EqualFold(m, method) {
+				return i
+			}
+		}
+		return len(methodOrder)
+	}
+	keys := append(append([]string{}, cfg.ItemSortBy...), ItemSortPath, ItemSortMethod)
+	sort.SliceStable(mops, func(i, j int) bool {
+		a, b := mops[i], mops[j]
+		for _, key := range keys {
+			c := 0
+			switch key {
+			case ItemSortPath:
+				c = strings.Compare(a.path, b.path)
+			case ItemSortMethod:
+				c = cmp.Or(
+					cmp.Compare(methodIndex(a.method), methodIndex(b.method)),
+					strings.Compare(a.method, b.method))
+			case ItemSortSummary:
+				c = strings.Compare(
+					strings.ToLower(a.item.DisplayName()),
+					strings.ToLower(b.item.DisplayName()))
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// createFolders adds the folders for a strategy before items are added, so
+// folder order does not depend on item order.
+func createFolders(pman postman2.Collection, spec *openapi3.Spec, strategy string, tagGroupSet taggroups.TagGroupSet, urls []string) (postman2.Collection, error) {
+	switch strategy {
+	case FolderStrategyPathSegment:
+		for _, url := range urls {
+			if segments := pathSegments(url); len(segments) > 0 {
+				pathSegmentFolder(&pman, segments)
+			}
+		}
+		return pman, nil
+	case FolderStrategyTagGroup:
+		var err error
+		if pman, err = addFoldersFromTagGroups(pman, tagGroupSet, spec.Tags); err != nil {
+			return pman, err
+		}
+	default:
+		tagGroupSet = taggroups.NewTagGroupSet()
+		pman = addFoldersFromTags(pman, spec.Tags)
+	}
+	tagsMore := openapi3.TagsMore{Tags: spec.Tags}
+	for _, tagName := range specTagNames(spec) {
+		if tagGroupSet.Exists(tagName) {
+			continue
+		}
+		folder := pman.GetOrNewFolder(tagName)
+		if tag := tagsMore.Get(tagName); tag != nil && folder.Description == nil {
+			folder.Description = &postman2.Description{
+				Content: strings.TrimSpace(tag.Description),
+				Type:    httputilmore.ContentTypeTextPlain}
+		}
+	}
+	return pman, nil
+}
+
+// specTagNames returns the spec `tags` names in order, followed by
+// undeclared operation tags sorted by name.
+func specTagNames(spec *openapi3.Spec) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, tag := range spec.Tags {
+		if tag == nil {
+			continue
+		}
+		if name := strings.TrimSpace(tag.Name); len(name) > 0 && !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+	undeclared := []string{}
+	if spec.Paths != nil {
+		for _, pathItem := range spec.Paths.Map() {
+			for _, op := range pathItem.Operations() {
+				for _, name := range op.Tags {
+					if name = strings.TrimSpace(name); len(name) > 0 && !seen[name] {
+						undeclared = append(undeclared, name)
+						seen[name] = true
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(undeclared)
+	return append(names, undeclared...)
+}
+
+// addItemToFolders adds a generated item to its strategy folders. Items
+// without a folder, such as untagged operations, are added at the top level.
+func addItemToFolders(pman postman2.Collection, mop mergeOperation, strategy string, tagGroupSet taggroups.TagGroupSet) postman2.Collection {
+	if strategy == FolderStrategyPathSegment {
+		segments := pathSegments(mop.path)
+		if len(segments) == 0 {
+			pman.Item = append(pman.Item, mop.item)
+			return pman
+		}
+		folder := pathSegmentFolder(&pman, segments)
+		folder.Item = append(folder.Item, mop.item)
+		return pman
+	}
+	if len(mop.op.Tags) == 0 {
+		pman.Item = append(pman.Item, mop.item)
+		return pman
+	}
+	if strategy == FolderStrategyTag {
+		tagGroupSet = taggroups.NewTagGroupSet()
+	}
+	return postmanAddItemToFolders(pman, mop.item, mop.op.Tags, tagGroupSet)
+}
+
+func pathSegments(url string) []string {
+	segments := []string{}
+	for _, segment := range strings.Split(url, "/") {
+		if segment = strings.TrimSpace(segment); len(segment) > 0 {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// pathSegmentFolder returns the nested folder for path segments, creating
+// folders as needed. Request items with a segment name are not used.
+func pathSegmentFolder(pman *postman2.Collection, segments []string) *postman2.Item {
+	items := &pman.Item
+	var folder *postman2.Item
+	for _, segment := range segments {
+		folder = nil
+		for _, item := range *items {
+			if item != nil && item.Request == nil && item.Name == segment {
+				folder = item
+				break
+			}
+		}
+		if folder == nil {
+			folder = &postman2.Item{Name: segment, Item: []*postman2.Item{}}
+			*items = append(*items, folder)
+		}
+		items = &folder.Item
+	}
+	return folder
+}
+
 func CreateTagsAndTagGroups(pman postman2.Collection, spec *openapi3.Spec) (postman2.Collection, error) {
 	// oas3specMore := openapi3.SpecMore{Spec: spec}
 	tagGroupSet, err := taggroups.SpecTagGroups(spec)
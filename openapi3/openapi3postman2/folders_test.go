@@ -0,0 +1,69 @@
+package openapi3postman2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/spectrum/postman2"
+)
+
+const foldersSpec = `{
+"openapi":"3.0.3","info":{"title":"Accounts","version":"1.0.0"},
+"servers":[{"url":"https://api.example.com"}],
+"tags":[{"name":"extensions"},{"name":"accounts"}],
+"paths":{
+  "/accounts":{
+    "post":{"summary":"Create account","tags":["accounts"],"responses":{"201":{"description":"Created"}}},
+    "get":{"summary":"List accounts","tags":["accounts"],"responses":{"200":{"description":"OK"}}}},
+  "/accounts/{id}":{
+    "get":{"summary":"Get account","tags":["accounts"],"responses":{"200":{"description":"OK"}}}},
+  "/accounts/{id}/extensions":{
+    "get":{"summary":"List extensions","tags":["extensions"],"responses":{"200":{"description":"OK"}}}},
+  "/status":{
+    "get":{"summary":"Status","tags":["admin"],"responses":{"200":{"description":"OK"}}}},
+  "/version":{
+    "get":{"summary":"Version","responses":{"200":{"description":"OK"}}}}}}`
+
+var foldersTests = []struct {
+	strategy string
+	sortBy   []string
+	want     string
+}{
+	{"", nil, "[extensions: GET /accounts/:id/extensions] [accounts: GET /accounts, POST /accounts, GET /accounts/:id] [admin: GET /status] GET /version"},
+	{FolderStrategyTag, []string{ItemSortMethod}, "[extensions: GET /accounts/:id/extensions] [accounts: GET /accounts, GET /accounts/:id, POST /accounts] [admin: GET /status] GET /version"},
+	{FolderStrategyTag, []string{ItemSortSummary}, "[extensions: GET /accounts/:id/extensions] [accounts: POST /accounts, GET /accounts/:id, GET /accounts] [admin: GET /status] GET /version"},
+	{FolderStrategyPathSegment, []string{ItemSortPath}, "[accounts: [{id}: [extensions: GET /accounts/:id/extensions], GET /accounts/:id], GET /accounts, POST /accounts] [status: GET /status] [version: GET /version]"},
+}
+
+// TestFolders ensures folder strategies and item sorting give a stable
+// collection layout.
+func TestFolders(t *testing.T) {
+	spec := syncTestSpec(t, foldersSpec)
+	for _, tt := range foldersTests {
+		pman, err := ConvertSpec(Configuration{FolderStrategy: tt.strategy, ItemSortBy: tt.sortBy}, spec)
+		if err != nil {
+			t.Fatalf("openapi3postman2.ConvertSpec() Error [%s]", err.Error())
+		}
+		got := foldersTestLayout(pman.Item, " ")
+		if got != tt.want {
+			t.Errorf("openapi3postman2.ConvertSpec() Mismatch: strategy [%s] sort [%s] want [%s], got [%s]",
+				tt.strategy, strings.Join(tt.sortBy, ","), tt.want, got)
+		}
+	}
+
+	if _, err := ConvertSpec(Configuration{FolderStrategy: "bogus"}, spec); err == nil {
+		t.Errorf("openapi3postman2.ConvertSpec() Mismatch: want error for unknown folder strategy, got nil")
+	}
+}
+
+func foldersTestLayout(items []*postman2.Item, sep string) string {
+	parts := []string{}
+	for _, item := range items {
+		if item.Request == nil {
+			parts = append(parts, "["+item.Name+": "+foldersTestLayout(item.Item, ", ")+"]")
+		} else {
+			parts = append(parts, item.Request.Method+" "+strings.TrimPrefix(item.Request.URL.Raw, "https://api.example.com"))
+		}
+	}
+	return strings.Join(parts, sep)
+}
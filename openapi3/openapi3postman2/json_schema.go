@@ -0,0 +1,130 @@
+package openapi3postman2
+
+import (
+	"encoding/json"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/spectrum/openapi3"
+)
+
+// jsonSchemaKeysOpenAPI are OpenAPI schema keywords that are not JSON Schema
+// validation keywords. `format` is removed because OpenAPI formats such as
+// `int32` are unknown to JSON Schema validators.
+var jsonSchemaKeysOpenAPI = []string{
+	"allowEmptyValue", "deprecated", "discriminator", "example", "examples",
+	"externalDocs", "format", "nullable", "readOnly", "writeOnly", "xml"}
+
+// JSONSchemaOpenAPI3 converts an OpenAPI response schema to a standalone
+// JSON Schema with `$ref`s resolved inline, suitable for validators such as
+// Postman's `pm.response.to.have.jsonSchema()`. `nullable` becomes a `null`
+// type and boolean `exclusiveMinimum` and `exclusiveMaximum` become numeric.
+// `writeOnly` properties are not required. Recursive references are
+// replaced with an empty schema, which matches any value.
+func JSONSchemaOpenAPI3(spec *openapi3.Spec, schemaRef *oas3.SchemaRef) map[string]any {
+	conv := jsonSchemaConverter{spec: spec, visiting: map[string]bool{}}
+	return conv.convertRef(schemaRef)
+}
+
+type jsonSchemaConverter struct {
+	spec     *openapi3.Spec
+	visiting map[string]bool
+}
+
+func (conv jsonSchemaConverter) convertRef(schemaRef *oas3.SchemaRef) map[string]any {
+	if schemaRef == nil {
+		return map[string]any{}
+	}
+	if len(schemaRef.Ref) > 0 {
+		if conv.visiting[schemaRef.Ref] {
+			return map[string]any{}
+		}
+		conv.visiting[schemaRef.Ref] = true
+		defer delete(conv.visiting, schemaRef.Ref)
+	}
+	sch := openapi3.SchemaRefResolve(conv.spec, schemaRef)
+	if sch == nil {
+		return map[string]any{}
+	}
+	return conv.convert(sch)
+}
+
+func (conv jsonSchemaConverter) convert(sch *oas3.Schema) map[string]any {
+	out := map[string]any{}
+	if bytes, err := json.Marshal(sch); err == nil {
+		if err := json.Unmarshal(bytes, &out); err != nil {
+			out = map[string]any{}
+		}
+	}
+	for _, key := range jsonSchemaKeysOpenAPI {
+		delete(out, key)
+	}
+	for key := range out {
+		if strings.HasPrefix(key, "x-") {
+			delete(out, key)
+		}
+	}
+
+	if sch.Type != nil && sch.Nullable {
+		types := []any{}
+		for _, t := range *sch.Type {
+			types = append(types, t)
+		}
+		out["type"] = append(types, "null")
+	}
+	if sch.ExclusiveMin.Bool != nil && sch.Min != nil {
+		delete(out, "exclusiveMinimum")
+		if *sch.ExclusiveMin.Bool {
+			out["exclusiveMinimum"] = *sch.Min
+			delete(out, "minimum")
+		}
+	}
+	if sch.ExclusiveMax.Bool != nil && sch.Max != nil {
+		delete(out, "exclusiveMaximum")
+		if *sch.ExclusiveMax.Bool {
+			out["exclusiveMaximum"] = *sch.Max
+			delete(out, "maximum")
+		}
+	}
+
+	if len(sch.Properties) > 0 {
+		props := map[string]any{}
+		for name, propRef := range sch.Properties {
+			props[name] = conv.convertRef(propRef)
+		}
+		out["properties"] = props
+		var required []any
+		for _, name := range sch.Required {
+			if prop := openapi3.SchemaRefResolve(conv.spec, sch.Properties[name]); prop != nil && prop.WriteOnly {
+				continue
+			}
+			required = append(required, name)
+		}
+		if len(required) > 0 {
+			out["required"] = required
+		} else {
+			delete(out, "required")
+		}
+	}
+	if sch.Items != nil {
+		out["items"] = conv.convertRef(sch.Items)
+	}
+	if sch.AdditionalProperties.Schema != nil {
+		out["additionalProperties"] = conv.convertRef(sch.AdditionalProperties.Schema)
+	}
+	if sch.Not != nil {
+		out["not"] = conv.convertRef(sch.Not)
+	}
+	for key, refs := range map[string]oas3.SchemaRefs{
+		"allOf": sch.AllOf, "anyOf": sch.AnyOf, "oneOf": sch.OneOf} {
+		if len(refs) == 0 {
+			continue
+		}
+		subs := []any{}
+		for _, ref := range refs {
+			subs = append(subs, conv.convertRef(ref))
+		}
+		out[key] = subs
+	}
+	return out
+}
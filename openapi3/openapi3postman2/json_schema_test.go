@@ -0,0 +1,42 @@
+package openapi3postman2
+
+import (
+	"encoding/json"
+	"testing"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+)
+
+var jsonSchemaTests = []struct {
+	schemaRef *oas3.SchemaRef
+	want      string
+}{
+	{oas3.NewSchemaRef("#/components/schemas/Pet", nil),
+		`{"properties":{"id":{"type":"integer"},"name":{"type":["string","null"]},"owner":{"properties":{"age":{"exclusiveMinimum":0,"type":"integer"},"pet":{}},"type":"object"},"password":{"type":"string"}},"required":["id","name"],"type":"object"}`},
+	{oas3.NewSchemaRef("#/components/schemas/Owner", nil),
+		`{"properties":{"age":{"exclusiveMinimum":0,"type":"integer"},"pet":{"properties":{"id":{"type":"integer"},"name":{"type":["string","null"]},"owner":{},"password":{"type":"string"}},"required":["id","name"],"type":"object"}},"type":"object"}`},
+	{oas3.NewSchemaRef("", &oas3.Schema{
+		Type:     &oas3.Types{oas3.TypeArray},
+		Nullable: true,
+		Items:    oas3.NewSchemaRef("#/components/schemas/Owner", nil)}),
+		`{"items":{"properties":{"age":{"exclusiveMinimum":0,"type":"integer"},"pet":{"properties":{"id":{"type":"integer"},"name":{"type":["string","null"]},"owner":{},"password":{"type":"string"}},"required":["id","name"],"type":"object"}},"type":"object"},"type":["array","null"]}`},
+	{oas3.NewSchemaRef("", &oas3.Schema{Nullable: true, Description: "Any value"}),
+		`{"description":"Any value"}`},
+	{oas3.NewSchemaRef("#/components/schemas/Missing", nil), `{}`},
+}
+
+// TestJSONSchemaOpenAPI3 ensures `$ref`s are resolved inline, recursive
+// refs become empty schemas and OpenAPI keywords such as `nullable` and
+// boolean `exclusiveMinimum` are converted to JSON Schema.
+func TestJSONSchemaOpenAPI3(t *testing.T) {
+	spec := syncTestSpec(t, scriptSpec)
+	for _, tt := range jsonSchemaTests {
+		bytes, err := json.Marshal(JSONSchemaOpenAPI3(spec, tt.schemaRef))
+		if err != nil {
+			t.Fatalf("json.Marshal() Error [%s]", err.Error())
+		}
+		if got := string(bytes); got != tt.want {
+			t.Errorf("openapi3postman2.JSONSchemaOpenAPI3(%s) Mismatch: want [%s], got [%s]", tt.schemaRef.Ref, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package openapi3postman2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/net/http/httputilmore"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/postman2"
+)
+
+// RequestBodyOpenAPI3ToPostman returns a Postman request body for an
+// operation's request body media type. The body is built from the media
+// type `example` or `examples`, or synthesized from the schema. JSON, XML
+// and text media types produce `raw` bodies while form media types produce
+// `urlencoded` and `formdata` bodies. Nil is returned when there is no
+// request body or no example can be built.
+func RequestBodyOpenAPI3ToPostman(spec *openapi3.Spec, operation *oas3.Operation, mediaType string) (*postman2.RequestBody, error) {
+	if operation == nil || operation.RequestBody == nil {
+		return nil, nil
+	}
+	reqBodyRef := operation.RequestBody
+	if reqBodyRef.Value == nil && len(reqBodyRef.Ref) > 0 {
+		sm := openapi3.SpecMore{Spec: spec}
+		ref, err := sm.RequestBodyRef(reqBodyRef.Ref)
+		if err != nil {
+			return nil, err
+		}
+		reqBodyRef = ref
+	}
+	if reqBodyRef == nil || reqBodyRef.Value == nil {
+		return nil, nil
+	}
+	mt := reqBodyRef.Value.Content.Get(mediaType)
+	if mt == nil {
+		return nil, nil
+	}
+	value := openapi3.MediaTypeExample(spec, mt, &openapi3.SchemaExampleOpts{SkipReadOnly: true})
+	if value == nil {
+		return nil, nil
+	}
+	mediaTypeLower := strings.ToLower(mediaType)
+	switch {
+	case strings.Contains(mediaTypeLower, "json"):
+		bytes, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return rawBody(string(bytes), "json"), nil
+	case strings.HasPrefix(mediaTypeLower, httputilmore.ContentTypeAppFormURLEncoded):
+		body := &postman2.RequestBody{Mode: postman2.BodyModeURLEncoded}
+		for _, field := range formFields(spec, mt.Schema, value) {
+			body.URLEncoded = append(body.URLEncoded, postman2.URLEncodedParam{
+				Key:   field.name,
+				Value: field.value,
+				Type:  postman2.FormDataTypeText})
+		}
+		return body, nil
+	case strings.HasPrefix(mediaTypeLower, httputilmore.ContentTypeMultipartFormData):
+		body := &postman2.RequestBody{Mode: postman2.BodyModeFormData}
+		for _, field := range formFields(spec, mt.Schema, value) {
+			param := postman2.FormDataParam{Key: field.name, Type: postman2.FormDataTypeText, Value: field.value}
+			if field.binary {
+				param.Type = postman2.FormDataTypeFile
+				param.Value = ""
+			}
+			body.FormData = append(body.FormData, param)
+		}
+		return body, nil
+	}
+	if s, ok := value.(string); ok {
+		lang := "text"
+		if strings.Contains(mediaTypeLower, "xml") {
+			lang = "xml"
+		}
+		return rawBody(s, lang), nil
+	}
+	return nil, nil
+}
+
+func rawBody(raw, language string) *postman2.RequestBody {
+	return &postman2.RequestBody{
+		Mode: postman2.BodyModeRaw,
+		Raw:  raw,
+		Options: &postman2.RequestBodyOptions{
+			Raw: &postman2.RequestBodyOptionsRaw{Language: language}}}
+}
+
+type formField struct {
+	name   string
+	value  string
+	binary bool
+}
+
+// formFields converts a top-level object example to form fields. Nested
+// objects and arrays are JSON encoded. Properties with `format: binary`
+// are marked as files.
+func formFields(spec *openapi3.Spec, schemaRef *oas3.SchemaRef, value any) []formField {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	sch := openapi3.SchemaRefResolve(spec, schemaRef)
+	fields := []formField{}
+	for _, name := range maputil.Keys(obj) {
+		field := formField{name: name, value: formValueString(obj[name])}
+		if sch != nil {
+			if prop := openapi3.SchemaRefResolve(spec, sch.Properties[name]); prop != nil && prop.Format == "binary" {
+				field.binary = true
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func formValueString(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case map[string]any, []any:
+		if bytes, err := json.Marshal(val); err == nil {
+			return string(bytes)
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,91 @@
+package openapi3postman2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/spectrum/postman2"
+)
+
+const requestBodySpec = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"paths":{
+  "/example":{"post":{"requestBody":{"content":{"application/json":{"example":{"name":"Tom","tags":["cat"]}}}},"responses":{"200":{"description":"OK"}}}},
+  "/synthesized":{"post":{"requestBody":{"$ref":"#/components/requestBodies/NewPet"},"responses":{"200":{"description":"OK"}}}},
+  "/urlencoded":{"post":{"requestBody":{"content":{"application/x-www-form-urlencoded":{"schema":{"type":"object","properties":{
+    "name":{"type":"string","example":"Tom"},"age":{"type":"integer","example":3},"tags":{"type":"array","items":{"type":"string","example":"cat"}}}}}}},
+    "responses":{"200":{"description":"OK"}}}},
+  "/formdata":{"post":{"requestBody":{"content":{"multipart/form-data":{"schema":{"type":"object","properties":{
+    "name":{"type":"string","example":"Tom"},"photo":{"type":"string","format":"binary"}}}}}},
+    "responses":{"200":{"description":"OK"}}}},
+  "/composed":{"post":{"requestBody":{"content":{"application/json":{"schema":{"allOf":[
+    {"$ref":"#/components/schemas/Base"},
+    {"type":"object","properties":{"kind":{"oneOf":[{"$ref":"#/components/schemas/Cat"},{"$ref":"#/components/schemas/Dog"}]}}}]}}}},
+    "responses":{"200":{"description":"OK"}}}},
+  "/xml":{"post":{"requestBody":{"content":{"application/xml":{"example":"<pet><name>Tom</name></pet>"}}},"responses":{"200":{"description":"OK"}}}}},
+"components":{
+  "requestBodies":{"NewPet":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/NewPet"}}}}},
+  "schemas":{
+    "NewPet":{"type":"object","properties":{"id":{"type":"integer","readOnly":true},"name":{"type":"string","example":"Tom"},"age":{"type":"integer"}}},
+    "Base":{"type":"object","properties":{"id":{"type":"string","example":"p1"}}},
+    "Cat":{"type":"object","properties":{"meows":{"type":"boolean","example":true}}},
+    "Dog":{"type":"object","properties":{"barks":{"type":"boolean"}}}}}}`
+
+var requestBodyTests = []struct {
+	path      string
+	mediaType string
+	want      string
+}{
+	{"/example", "application/json", "raw|json|{\n  \"name\": \"Tom\",\n  \"tags\": [\n    \"cat\"\n  ]\n}"},
+	{"/synthesized", "application/json", "raw|json|{\n  \"age\": 0,\n  \"name\": \"Tom\"\n}"},
+	{"/urlencoded", "application/x-www-form-urlencoded", `urlencoded|age=3:text, name=Tom:text, tags=["cat"]:text`},
+	{"/formdata", "multipart/form-data", "formdata|name=Tom:text, photo=:file"},
+	{"/composed", "application/json", "raw|json|{\n  \"id\": \"p1\",\n  \"kind\": {\n    \"meows\": true\n  }\n}"},
+	{"/xml", "application/xml", "raw|xml|<pet><name>Tom</name></pet>"},
+	{"/xml", "application/json", ""},
+}
+
+// TestRequestBodyOpenAPI3ToPostman ensures request bodies are built from
+// examples or synthesized from schemas, including `$ref`, `allOf` and `oneOf`
+// schemas, for raw, `urlencoded` and `formdata` modes.
+func TestRequestBodyOpenAPI3ToPostman(t *testing.T) {
+	spec := syncTestSpec(t, requestBodySpec)
+	for _, tt := range requestBodyTests {
+		body, err := RequestBodyOpenAPI3ToPostman(spec, spec.Paths.Find(tt.path).Post, tt.mediaType)
+		if err != nil {
+			t.Fatalf("openapi3postman2.RequestBodyOpenAPI3ToPostman(%s, %s) Error [%s]", tt.path, tt.mediaType, err.Error())
+		}
+		if got := requestBodyTestString(body); got != tt.want {
+			t.Errorf("openapi3postman2.RequestBodyOpenAPI3ToPostman(%s, %s) Mismatch: want [%s], got [%s]", tt.path, tt.mediaType, tt.want, got)
+		}
+	}
+}
+
+// requestBodyTestString returns the mode followed by the raw language and
+// body, or the form fields as `key=value:type`.
+func requestBodyTestString(body *postman2.RequestBody) string {
+	if body == nil {
+		return ""
+	}
+	switch body.Mode {
+	case postman2.BodyModeRaw:
+		lang := ""
+		if body.Options != nil && body.Options.Raw != nil {
+			lang = body.Options.Raw.Language
+		}
+		return strings.Join([]string{body.Mode, lang, body.Raw}, "|")
+	case postman2.BodyModeURLEncoded:
+		fields := []string{}
+		for _, p := range body.URLEncoded {
+			fields = append(fields, p.Key+"="+p.Value+":"+p.Type)
+		}
+		return body.Mode + "|" + strings.Join(fields, ", ")
+	case postman2.BodyModeFormData:
+		fields := []string{}
+		for _, p := range body.FormData {
+			fields = append(fields, p.Key+"="+p.Value+":"+p.Type)
+		}
+		return body.Mode + "|" + strings.Join(fields, ", ")
+	}
+	return body.Mode
+}
@@ -0,0 +1,208 @@
+package openapi3postman2
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/net/http/httputilmore"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const (
+	pointerComponentsResponses = "#/components/responses/"
+	responseKeyDefault         = "default"
+)
+
+// ResponsesOpenAPI3ToPostman returns saved example responses for an
+// operation, sorted by status code. Each named media type `examples` entry
+// is saved as its own response; otherwise one response is built from the
+// media type `example` or synthesized from the schema. Response headers use
+// their example values. Range status codes such as `4XX` use the first code
+// in the range and `default` responses are saved without a status code. A
+// copy of `originalRequest` is set on each response when provided.
+func ResponsesOpenAPI3ToPostman(spec *openapi3.Spec, operation *oas3.Operation, originalRequest *postman2.Request) ([]postman2.Response, error) {
+	resps := []postman2.Response{}
+	if operation == nil || operation.Responses == nil {
+		return resps, nil
+	}
+	respsMap := operation.Responses.Map()
+	for _, statusCode := range maputil.Keys(respsMap) {
+		code, ok := responseStatusCode(statusCode)
+		if !ok {
+			continue
+		}
+		respRef := responseRefResolve(spec, respsMap[statusCode])
+		if respRef == nil || respRef.Value == nil {
+			continue
+		}
+		statusResps, err := responsesOpenAPI3ToPostman(spec, code, respRef.Value)
+		if err != nil {
+			return resps, err
+		}
+		for _, resp := range statusResps {
+			if originalRequest != nil {
+				if resp.OriginalRequest, err = requestCopy(originalRequest); err != nil {
+					return resps, err
+				}
+			}
+			resps = append(resps, resp)
+		}
+	}
+	return resps, nil
+}
+
+// responsesOpenAPI3ToPostman returns one response per named example of the
+// preferred media type, or a single response when there are none.
+func responsesOpenAPI3ToPostman(spec *openapi3.Spec, code int, oresp *oas3.Response) ([]postman2.Response, error) {
+	resp := postman2.Response{
+		Name:   responseName(code, oresp),
+		Status: http.StatusText(code),
+		Code:   code}
+	mediaTypes := maputil.Keys(oresp.Content)
+	mediaType := ""
+	if len(mediaTypes) > 0 {
+		mediaType = mediaTypes[0]
+		for _, pref := range postman2.DefaultMediaTypePreferencesSlice() {
+			if oresp.Content.Get(pref) != nil {
+				mediaType = pref
+				break
+			}
+		}
+		resp.Header = append(resp.Header, postman2.Header{
+			Key:   httputilmore.HeaderContentType,
+			Value: mediaType})
+	}
+	for _, headerName := range maputil.Keys(oresp.Headers) {
+		if strings.EqualFold(headerName, httputilmore.HeaderContentType) {
+			continue
+		}
+		header := postman2.Header{Key: headerName}
+		if hdrRef := oresp.Headers[headerName]; hdrRef != nil && hdrRef.Value != nil {
+			hdr := hdrRef.Value
+			header.Description = postman2.NewDescription(hdr.Description)
+			if hdr.Example != nil {
+				header.Value = formValueString(hdr.Example)
+			} else if v := openapi3.SchemaExample(spec, hdr.Schema, nil); v != nil {
+				header.Value = formValueString(v)
+			}
+		}
+		resp.Header = append(resp.Header, header)
+	}
+	if len(mediaType) == 0 {
+		return []postman2.Response{resp}, nil
+	}
+
+	mt := oresp.Content.Get(mediaType)
+	resps := []postman2.Response{}
+	if mt != nil && mt.Example == nil {
+		for _, exName := range maputil.Keys(mt.Examples) {
+			ex := openapi3.ExampleRefResolve(spec, mt.Examples[exName])
+			if ex == nil || ex.Value == nil {
+				continue
+			}
+			exResp := resp
+			exResp.Header = append([]postman2.Header{}, resp.Header...)
+			if summary := strings.TrimSpace(ex.Summary); len(summary) > 0 {
+				exResp.Name += " - " + summary
+			} else {
+				exResp.Name += " - " + exName
+			}
+			if err := responseBodySet(&exResp, mediaType, ex.Value); err != nil {
+				return resps, err
+			}
+			resps = append(resps, exResp)
+		}
+	}
+	if len(resps) > 0 {
+		return resps, nil
+	}
+	if value := openapi3.MediaTypeExample(spec, mt, &openapi3.SchemaExampleOpts{SkipWriteOnly: true}); value != nil {
+		if err := responseBodySet(&resp, mediaType, value); err != nil {
+			return resps, err
+		}
+	}
+	return []postman2.Response{resp}, nil
+}
+
+// responseBodySet sets the response body and preview language for a media
+// type.
+func responseBodySet(resp *postman2.Response, mediaType string, value any) error {
+	mediaTypeLower := strings.ToLower(mediaType)
+	switch {
+	case strings.Contains(mediaTypeLower, "json"):
+		bytes, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		resp.Body = string(bytes)
+		resp.PostmanPreviewLanguage = "json"
+	default:
+		resp.Body = formValueString(value)
+		resp.PostmanPreviewLanguage = "text"
+		if strings.Contains(mediaTypeLower, "xml") {
+			resp.PostmanPreviewLanguage = "xml"
+		} else if strings.Contains(mediaTypeLower, "html") {
+			resp.PostmanPreviewLanguage = "html"
+		}
+	}
+	return nil
+}
+
+// requestCopy returns a deep copy of a request so saved responses do not
+// share the item request.
+func requestCopy(req *postman2.Request) (*postman2.Request, error) {
+	bytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	reqCopy := &postman2.Request{}
+	return reqCopy, json.Unmarshal(bytes, reqCopy)
+}
+
+// responseName returns the first line of the response description or the
+// HTTP status text.
+func responseName(code int, oresp *oas3.Response) string {
+	if oresp.Description != nil {
+		if desc := strings.TrimSpace(strings.Split(strings.TrimSpace(*oresp.Description), "\n")[0]); len(desc) > 0 {
+			return desc
+		}
+	}
+	if code == 0 {
+		return responseKeyDefault
+	} else if statusText := http.StatusText(code); len(statusText) > 0 {
+		return statusText
+	}
+	return strconv.Itoa(code)
+}
+
+// responseStatusCode parses response keys such as `200` and `4XX`. The
+// `default` response has no status code and returns `0`.
+func responseStatusCode(statusCode string) (int, bool) {
+	statusCode = strings.ToUpper(strings.TrimSpace(statusCode))
+	if strings.EqualFold(statusCode, responseKeyDefault) {
+		return 0, true
+	}
+	if len(statusCode) == 3 && strings.HasSuffix(statusCode, "XX") {
+		statusCode = statusCode[:1] + "00"
+	}
+	code, err := strconv.Atoi(statusCode)
+	if err != nil || code < 100 || code > 599 {
+		return 0, false
+	}
+	return code, true
+}
+
+func responseRefResolve(spec *openapi3.Spec, respRef *oas3.ResponseRef) *oas3.ResponseRef {
+	if respRef == nil || respRef.Value != nil || spec == nil || spec.Components == nil {
+		return respRef
+	}
+	if resolved, ok := spec.Components.Responses[strings.TrimPrefix(respRef.Ref, pointerComponentsResponses)]; ok {
+		return resolved
+	}
+	return respRef
+}
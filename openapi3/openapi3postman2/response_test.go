@@ -0,0 +1,67 @@
+package openapi3postman2
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/grokify/spectrum/postman2"
+)
+
+const responsesSpec = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"paths":{"/pets/{petId}":{"get":{"operationId":"getPet","responses":{
+  "200":{"description":"The pet","headers":{"X-Rate-Limit":{"description":"Requests left","schema":{"type":"integer","example":100}}},
+    "content":{"application/json":{"examples":{
+      "cat":{"summary":"A cat","value":{"id":1,"name":"Tom"}},
+      "dog":{"$ref":"#/components/examples/Dog"}}}}},
+  "4XX":{"description":"Client error","content":{"text/plain":{"example":"bad request"}}},
+  "404":{"$ref":"#/components/responses/NotFound"},
+  "default":{"description":"","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Error"}}}}}}}},
+"components":{
+  "examples":{"Dog":{"value":{"id":2,"name":"Rex"}}},
+  "responses":{"NotFound":{"description":"Pet not found\nNo pet has the id."}},
+  "schemas":{"Error":{"type":"object","properties":{"code":{"type":"integer","example":500},"message":{"type":"string","example":"failure"}}}}}}`
+
+var responsesTests = []string{
+	"The pet - A cat|200|OK|json|{\n  \"id\": 1,\n  \"name\": \"Tom\"\n}|Content-Type: application/json, X-Rate-Limit: 100",
+	"The pet - dog|200|OK|json|{\n  \"id\": 2,\n  \"name\": \"Rex\"\n}|Content-Type: application/json, X-Rate-Limit: 100",
+	"Pet not found|404|Not Found|||",
+	"Client error|400|Bad Request|text|bad request|Content-Type: text/plain",
+	"default|0||json|{\n  \"code\": 500,\n  \"message\": \"failure\"\n}|Content-Type: application/json",
+}
+
+// TestResponsesOpenAPI3ToPostman ensures saved responses are created for
+// each named example and the `default` response, and that each response has
+// its own copy of the original request.
+func TestResponsesOpenAPI3ToPostman(t *testing.T) {
+	spec := syncTestSpec(t, responsesSpec)
+	op := spec.Paths.Find("/pets/{petId}").Get
+	req := &postman2.Request{
+		Method: "GET",
+		URL:    &postman2.URL{Raw: "{{baseUrl}}/pets/:petId"}}
+	resps, err := ResponsesOpenAPI3ToPostman(spec, op, req)
+	if err != nil {
+		t.Fatalf("openapi3postman2.ResponsesOpenAPI3ToPostman() Error [%s]", err.Error())
+	}
+	if len(resps) != len(responsesTests) {
+		t.Fatalf("openapi3postman2.ResponsesOpenAPI3ToPostman() Count Mismatch: want [%d], got [%d]", len(responsesTests), len(resps))
+	}
+	for i, want := range responsesTests {
+		resp := resps[i]
+		headers := []string{}
+		for _, h := range resp.Header {
+			headers = append(headers, h.Key+": "+h.Value)
+		}
+		got := strings.Join([]string{resp.Name, strconv.Itoa(resp.Code), resp.Status,
+			resp.PostmanPreviewLanguage, resp.Body, strings.Join(headers, ", ")}, "|")
+		if got != want {
+			t.Errorf("openapi3postman2.ResponsesOpenAPI3ToPostman() Mismatch at [%d]: want [%s], got [%s]", i, want, got)
+		}
+		if resp.OriginalRequest == nil || resp.OriginalRequest == req || resp.OriginalRequest.URL == req.URL {
+			t.Errorf("openapi3postman2.ResponsesOpenAPI3ToPostman() Mismatch at [%d]: want copy of original request", i)
+		} else if resp.OriginalRequest.URL.Raw != req.URL.Raw {
+			t.Errorf("openapi3postman2.ResponsesOpenAPI3ToPostman() Mismatch at [%d]: want original request URL [%s], got [%s]", i, req.URL.Raw, resp.OriginalRequest.URL.Raw)
+		}
+	}
+}
@@ -0,0 +1,650 @@
+package openapi3postman2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/errors/errorsutil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/postman2"
+	"github.com/grokify/spectrum/postman2/simple"
+)
+
+// SyncMetaMember is the item member that holds the operation key and the
+// hashes of the generated fields used by `Sync`.
+const SyncMetaMember = "x-spectrum-sync"
+
+type syncMeta struct {
+	Key       string            `json:"key"`
+	Generated map[string]string `json:"generated,omitempty"`
+}
+
+func itemSyncMeta(item *postman2.Item) *syncMeta {
+	raw, ok := item.Extra[SyncMetaMember]
+	if !ok {
+		return nil
+	}
+	meta := &syncMeta{}
+	if err := json.Unmarshal(raw, meta); err != nil || len(meta.Key) == 0 {
+		return nil
+	}
+	return meta
+}
+
+func setItemSyncMeta(item *postman2.Item, meta syncMeta) {
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return
+	}
+	if item.Extra == nil {
+		item.Extra = postman2.Extra{}
+	}
+	item.Extra[SyncMetaMember] = raw
+}
+
+// OperationKey returns the stable key that matches Postman items to an
+// operation: the `operationId` or, without one, the method and path.
+func OperationKey(method, path string, op *oas3.Operation) string {
+	if op != nil && len(strings.TrimSpace(op.OperationID)) > 0 {
+		return strings.TrimSpace(op.OperationID)
+	}
+	return strings.ToUpper(method) + " " + path
+}
+
+// SyncItem identifies a Postman item in a `SyncReport`.
+type SyncItem struct {
+	Key    string   `json:"key"`
+	Name   string   `json:"name"`
+	Folder []string `json:"folder,omitempty"`
+}
+
+// SyncReport lists the changes made by `Sync`. Items in `Removed` match
+// operations that are no longer in the spec. They are left in the
+// collection for review.
+type SyncReport struct {
+	Added   []SyncItem `json:"added,omitempty"`
+	Updated []SyncItem `json:"updated,omitempty"`
+	Removed []SyncItem `json:"removed,omitempty"`
+}
+
+// Sync updates a collection previously generated from a spec, keeping
+// manual edits. Items are matched to operations by the key stored in the
+// `x-spectrum-sync` member, or by method and URL path for items without
+// one. For matched items, generated fields are only updated when they
+// still equal the value last generated, so edited names, descriptions,
+// headers, bodies, scripts and examples are kept, as are user-added
+// headers, scripts, examples, items and folders. Items without a stored
+// key, such as those written by `MergeConvert`, have their generated
+// fields replaced once and are tracked from then on. Operations without
+// a matching item are added to their generated folder.
+func Sync(cfg Configuration, pman postman2.Collection, oas3spec *openapi3.Spec) (postman2.Collection, SyncReport, error) {
+	report := SyncReport{}
+	gen, err := merge(cfg, postman2.Collection{}, oas3spec, true)
+	if err != nil {
+		return pman, report, err
+	}
+
+	if len(pman.Info.Name) == 0 {
+		pman.Info.Name = gen.Info.Name
+	}
+	if pman.Info.Description == nil {
+		pman.Info.Description = gen.Info.Description
+	}
+	if len(pman.Info.Schema) == 0 {
+		pman.Info.Schema = gen.Info.Schema
+	}
+	for _, v := range gen.Variable {
+		if !collectionHasVariable(pman, v.Key) {
+			pman.Variable = append(pman.Variable, v)
+		}
+	}
+	if pman.Auth == nil {
+		pman.Auth = gen.Auth
+	}
+
+	specKeys := map[string]bool{}
+	for url, path := range oas3spec.Paths.Map() {
+		for _, method := range mergeMethods {
+			if op := path.GetOperation(method); op != nil {
+				specKeys[OperationKey(method, url, op)] = true
+			}
+		}
+	}
+
+	genRefs := syncItemRefs(gen.Item, nil)
+	exRefs := syncItemRefs(pman.Item, nil)
+	claimed := map[*syncItemRef]bool{}
+	exKeys := map[string]bool{}
+	for _, ex := range exRefs {
+		if len(ex.key) > 0 {
+			exKeys[ex.key] = true
+		}
+	}
+
+	for _, ex := range exRefs {
+		var genRef *syncItemRef
+		adopt := false
+		if meta := itemSyncMeta(ex.item); meta != nil {
+			if !specKeys[meta.Key] {
+				report.Removed = append(report.Removed, ex.syncItem(meta.Key))
+				continue
+			}
+			genRef = syncMatch(genRefs, claimed, ex, func(g *syncItemRef) bool { return g.key == meta.Key })
+		} else {
+			pathKey := syncPathKey(ex.item)
+			if len(pathKey) == 0 {
+				continue
+			}
+			genRef = syncMatch(genRefs, claimed, ex, func(g *syncItemRef) bool {
+				return !exKeys[g.key] && syncPathKey(g.item) == pathKey
+			})
+			if genRef != nil {
+				exKeys[genRef.key] = true
+				adopt = true
+			}
+		}
+		if genRef == nil {
+			continue
+		}
+		claimed[genRef] = true
+		if syncItemFields(ex.item, genRef.item, adopt) {
+			report.Updated = append(report.Updated, ex.syncItem(genRef.key))
+		}
+	}
+
+	for _, g := range genRefs {
+		if claimed[g] || exKeys[g.key] {
+			continue
+		}
+		item := syncCloneItem(g.item)
+		syncItemFields(item, g.item, true)
+		pman.Item = syncInsertItem(pman.Item, gen.Item, g.folder, item)
+		report.Added = append(report.Added, g.syncItem(g.key))
+	}
+	return pman, report, nil
+}
+
+// SyncConvert syncs the Postman collection at `pmanFilepath` with an
+// OpenAPI 3 spec and writes the result to `pmanSpecFilepath`. A missing
+// or empty `pmanFilepath` starts from an empty collection.
+func (conv *Converter) SyncConvert(openapiFilepath, pmanFilepath, pmanSpecFilepath string) (SyncReport, error) {
+	oas3spec, err := openapi3.ReadFile(openapiFilepath, true)
+	if err != nil {
+		return SyncReport{}, errorsutil.Wrap(err,
+			fmt.Sprintf(
+				"cannot read OpenAPI 3 spec [%s] openapi3postman2.Converter.SyncConvert << openapi3.ReadFile",
+				openapiFilepath))
+	}
+	pman := postman2.Collection{}
+	pmanFilepath = strings.TrimSpace(pmanFilepath)
+	if len(pmanFilepath) > 0 {
+		if _, err := os.Stat(pmanFilepath); err == nil {
+			pman, err = simple.ReadCanonicalCollection(pmanFilepath)
+			if err != nil {
+				return SyncReport{}, errorsutil.Wrap(err,
+					fmt.Sprintf(
+						"cannot read Postman Collection [%s] openapi3postman2.Converter.SyncConvert << simple.ReadCanonicalCollection",
+						pmanFilepath))
+			}
+		} else if !os.IsNotExist(err) {
+			return SyncReport{}, err
+		}
+	}
+	pm, report, err := Sync(conv.Configuration, pman, oas3spec)
+	if err != nil {
+		return report, err
+	}
+	bytes, err := json.MarshalIndent(pm, "", "  ")
+	if err != nil {
+		return report, err
+	}
+	return report, os.WriteFile(pmanSpecFilepath, bytes, 0600)
+}
+
+func collectionHasVariable(pman postman2.Collection, key string) bool {
+	for _, v := range pman.Variable {
+		if v.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+type syncItemRef struct {
+	item   *postman2.Item
+	folder []string
+	key    string
+}
+
+func (ref *syncItemRef) syncItem(key string) SyncItem {
+	return SyncItem{Key: key, Name: ref.item.Name, Folder: ref.folder}
+}
+
+// syncItemRefs returns the request items at any folder depth.
+func syncItemRefs(items []*postman2.Item, folder []string) []*syncItemRef {
+	refs := []*syncItemRef{}
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		if item.Request == nil {
+			if len(item.Item) > 0 {
+				refs = append(refs, syncItemRefs(item.Item, append(append([]string{}, folder...), item.Name))...)
+			}
+			continue
+		}
+		ref := &syncItemRef{item: item, folder: folder}
+		if meta := itemSyncMeta(item); meta != nil {
+			ref.key = meta.Key
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// syncMatch returns the generated item matching `ex`, preferring one in the
+// same folder that is not yet claimed.
+func syncMatch(genRefs []*syncItemRef, claimed map[*syncItemRef]bool, ex *syncItemRef, match func(g *syncItemRef) bool) *syncItemRef {
+	var first, unclaimed *syncItemRef
+	for _, g := range genRefs {
+		if !match(g) {
+			continue
+		}
+		if !claimed[g] && strings.Join(g.folder, "/") == strings.Join(ex.folder, "/") {
+			return g
+		}
+		if first == nil {
+			first = g
+		}
+		if unclaimed == nil && !claimed[g] {
+			unclaimed = g
+		}
+	}
+	if unclaimed != nil {
+		return unclaimed
+	}
+	return first
+}
+
+var rxSyncPathVar = regexp.MustCompile(`^(:.+|\{\{.+\}\}|\{.+\})$`)
+
+// syncPathKey returns the method and URL path of a request item with path
+// variables normalized, such as `GET /users/{}`.
+func syncPathKey(item *postman2.Item) string {
+	if item.Request == nil || item.Request.URL == nil {
+		return ""
+	}
+	segs := []string{}
+	for _, seg := range item.Request.URL.Path {
+		if rxSyncPathVar.MatchString(seg) {
+			seg = "{}"
+		}
+		segs = append(segs, seg)
+	}
+	method := strings.ToUpper(item.Request.Method)
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	return method + " /" + strings.Join(segs, "/")
+}
+
+// syncInsertItem adds `item` to the folder at `folder`, creating folders
+// from the generated collection as needed.
+func syncInsertItem(items, genItems []*postman2.Item, folder []string, item *postman2.Item) []*postman2.Item {
+	if len(folder) == 0 {
+		return append(items, item)
+	}
+	var genFolder *postman2.Item
+	for _, g := range genItems {
+		if g != nil && g.Request == nil && g.Name == folder[0] {
+			genFolder = g
+			break
+		}
+	}
+	genSubItems := []*postman2.Item{}
+	if genFolder != nil {
+		genSubItems = genFolder.Item
+	}
+	for _, ex := range items {
+		if ex != nil && ex.Request == nil && ex.Name == folder[0] {
+			ex.Item = syncInsertItem(ex.Item, genSubItems, folder[1:], item)
+			return items
+		}
+	}
+	newFolder := &postman2.Item{Name: folder[0], Item: []*postman2.Item{}}
+	if genFolder != nil {
+		newFolder.Description = genFolder.Description
+	}
+	newFolder.Item = syncInsertItem(newFolder.Item, genSubItems, folder[1:], item)
+	return append(items, newFolder)
+}
+
+func syncCloneItem(item *postman2.Item) *postman2.Item {
+	clone := &postman2.Item{}
+	if data, err := json.Marshal(item); err == nil {
+		if err := json.Unmarshal(data, clone); err == nil {
+			return clone
+		}
+	}
+	return item
+}
+
+// syncItemFields updates the generated fields of `ex` from `gen` and
+// records their hashes. Fields whose value no longer matches the recorded
+// hash were edited and are kept. Items without recorded hashes are
+// adopted. It returns true if `ex` changed.
+func syncItemFields(ex, gen *postman2.Item, adopt bool) bool {
+	recorded := map[string]string{}
+	if meta := itemSyncMeta(ex); meta != nil && len(meta.Generated) > 0 {
+		recorded = meta.Generated
+	} else {
+		adopt = true
+	}
+	genMeta := itemSyncMeta(gen)
+	if ex.Request == nil {
+		ex.Request = &postman2.Request{}
+	}
+
+	names := syncFieldNames(gen)
+	for name := range recorded {
+		if !sliceHasString(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	changed := false
+	newRecorded := map[string]string{}
+	for _, name := range names {
+		field := syncFieldFor(name)
+		rec, recOK := recorded[name]
+		cur, curOK := field.get(ex, rec)
+		genVal, genOK := field.get(gen, "")
+		edited := false
+		if recOK {
+			edited = !curOK || syncHash(cur) != rec
+		} else if !adopt {
+			edited = curOK && (!genOK || string(cur) != string(genVal))
+		}
+		if edited {
+			if recOK && genOK {
+				newRecorded[name] = rec
+			}
+			continue
+		}
+		if genOK {
+			if !curOK || string(cur) != string(genVal) {
+				field.set(ex, rec, genVal)
+				changed = true
+			}
+			newRecorded[name] = syncHash(genVal)
+		} else if curOK {
+			field.del(ex, rec)
+			changed = true
+		}
+	}
+	setItemSyncMeta(ex, syncMeta{Key: genMeta.Key, Generated: newRecorded})
+	return changed
+}
+
+func sliceHasString(s []string, v string) bool {
+	for _, try := range s {
+		if try == v {
+			return true
+		}
+	}
+	return false
+}
+
+func syncHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+const (
+	syncFieldName        = "name"
+	syncFieldDescription = "description"
+	syncFieldMethod      = "method"
+	syncFieldURL         = "url"
+	syncFieldAuth        = "auth"
+	syncFieldBody        = "body"
+	syncFieldHeader      = "header:"
+	syncFieldQuery       = "query:"
+	syncFieldVariable    = "variable:"
+	syncFieldEvent       = "event:"
+	syncFieldResponse    = "response:"
+)
+
+// syncFieldNames returns the generated fields of an item. Headers, query
+// parameters, path variables, events and responses are tracked one by one
+// so additions next to them are kept. Responses are keyed by status code
+// and name, as a code can have one response per example.
+func syncFieldNames(item *postman2.Item) []string {
+	names := []string{syncFieldName, syncFieldDescription, syncFieldMethod, syncFieldURL, syncFieldAuth, syncFieldBody}
+	if item.Request != nil {
+		for _, h := range item.Request.Header {
+			names = append(names, syncFieldHeader+strings.ToLower(h.Key))
+		}
+		if item.Request.URL != nil {
+			for _, q := range item.Request.URL.Query {
+				names = append(names, syncFieldQuery+q.Key)
+			}
+			for _, v := range item.Request.URL.Variable {
+				names = append(names, syncFieldVariable+urlVariableName(v))
+			}
+		}
+	}
+	for _, ev := range item.Event {
+		names = append(names, syncFieldEvent+ev.Listen)
+	}
+	for _, resp := range item.Response {
+		names = append(names, syncFieldResponse+strconv.Itoa(resp.Code)+":"+resp.Name)
+	}
+	return names
+}
+
+// syncField reads and writes one generated field as JSON. `rec` is the
+// recorded hash, used to pick the generated element among several with
+// the same key, such as a user-added example with the same status code.
+type syncField struct {
+	get func(item *postman2.Item, rec string) ([]byte, bool)
+	set func(item *postman2.Item, rec string, data []byte)
+	del func(item *postman2.Item, rec string)
+}
+
+func syncFieldFor(name string) syncField {
+	switch {
+	case name == syncFieldName:
+		return syncField{
+			get: func(item *postman2.Item, _ string) ([]byte, bool) {
+				return syncMarshal(item.Name, len(item.Name) > 0)
+			},
+			set: func(item *postman2.Item, _ string, data []byte) { syncUnmarshal(data, &item.Name) },
+			del: func(item *postman2.Item, _ string) { item.Name = "" },
+		}
+	case name == syncFieldDescription:
+		return syncField{
+			get: func(item *postman2.Item, _ string) ([]byte, bool) {
+				return syncMarshal(item.Request.Description, item.Request.Description != nil)
+			},
+			set: func(item *postman2.Item, _ string, data []byte) {
+				item.Request.Description = &postman2.Description{}
+				syncUnmarshal(data, item.Request.Description)
+			},
+			del: func(item *postman2.Item, _ string) { item.Request.Description = nil },
+		}
+	case name == syncFieldMethod:
+		return syncField{
+			get: func(item *postman2.Item, _ string) ([]byte, bool) {
+				return syncMarshal(item.Request.Method, len(item.Request.Method) > 0)
+			},
+			set: func(item *postman2.Item, _ string, data []byte) { syncUnmarshal(data, &item.Request.Method) },
+			del: func(item *postman2.Item, _ string) { item.Request.Method = "" },
+		}
+	case name == syncFieldURL:
+		// The URL without query parameters and path variables, which are
+		// tracked one by one.
+		return syncField{
+			get: func(item *postman2.Item, _ string) ([]byte, bool) {
+				if item.Request.URL == nil {
+					return nil, false
+				}
+				u := *item.Request.URL
+				u.Query = nil
+				u.Variable = nil
+				return syncMarshal(u, true)
+			},
+			set: func(item *postman2.Item, _ string, data []byte) {
+				u := postman2.URL{}
+				syncUnmarshal(data, &u)
+				if item.Request.URL != nil {
+					u.Query = item.Request.URL.Query
+					u.Variable = item.Request.URL.Variable
+				}
+				item.Request.URL = &u
+			},
+			del: func(item *postman2.Item, _ string) {},
+		}
+	case name == syncFieldAuth:
+		return syncField{
+			get: func(item *postman2.Item, _ string) ([]byte, bool) {
+				return syncMarshal(item.Request.Auth, item.Request.Auth != nil)
+			},
+			set: func(item *postman2.Item, _ string, data []byte) {
+				item.Request.Auth = &postman2.Auth{}
+				syncUnmarshal(data, item.Request.Auth)
+			},
+			del: func(item *postman2.Item, _ string) { item.Request.Auth = nil },
+		}
+	case name == syncFieldBody:
+		return syncField{
+			get: func(item *postman2.Item, _ string) ([]byte, bool) {
+				return syncMarshal(item.Request.Body, item.Request.Body != nil)
+			},
+			set: func(item *postman2.Item, _ string, data []byte) {
+				item.Request.Body = &postman2.RequestBody{}
+				syncUnmarshal(data, item.Request.Body)
+			},
+			del: func(item *postman2.Item, _ string) { item.Request.Body = nil },
+		}
+	case strings.HasPrefix(name, syncFieldHeader):
+		key := strings.TrimPrefix(name, syncFieldHeader)
+		match := func(h postman2.Header) bool { return strings.ToLower(h.Key) == key }
+		return syncListField(
+			func(item *postman2.Item) *[]postman2.Header { return &item.Request.Header }, match)
+	case strings.HasPrefix(name, syncFieldQuery):
+		key := strings.TrimPrefix(name, syncFieldQuery)
+		return syncListField(
+			func(item *postman2.Item) *[]postman2.URLQuery {
+				if item.Request.URL == nil {
+					item.Request.URL = &postman2.URL{}
+				}
+				return &item.Request.URL.Query
+			},
+			func(q postman2.URLQuery) bool { return q.Key == key })
+	case strings.HasPrefix(name, syncFieldVariable):
+		key := strings.TrimPrefix(name, syncFieldVariable)
+		return syncListField(
+			func(item *postman2.Item) *[]postman2.URLVariable {
+				if item.Request.URL == nil {
+					item.Request.URL = &postman2.URL{}
+				}
+				return &item.Request.URL.Variable
+			},
+			func(v postman2.URLVariable) bool { return urlVariableName(v) == key })
+	case strings.HasPrefix(name, syncFieldEvent):
+		listen := strings.TrimPrefix(name, syncFieldEvent)
+		return syncListField(
+			func(item *postman2.Item) *[]postman2.Event { return &item.Event },
+			func(ev postman2.Event) bool { return ev.Listen == listen })
+	case strings.HasPrefix(name, syncFieldResponse):
+		// Fields recorded without a name match by status code only.
+		codeStr, respName, hasName := strings.Cut(strings.TrimPrefix(name, syncFieldResponse), ":")
+		code, _ := strconv.Atoi(codeStr)
+		return syncListField(
+			func(item *postman2.Item) *[]postman2.Response { return &item.Response },
+			func(resp postman2.Response) bool { return resp.Code == code && (!hasName || resp.Name == respName) })
+	}
+	return syncField{
+		get: func(*postman2.Item, string) ([]byte, bool) { return nil, false },
+		set: func(*postman2.Item, string, []byte) {},
+		del: func(*postman2.Item, string) {},
+	}
+}
+
+// syncListField tracks an element of a list, such as a header by key. When
+// several elements match, the one with the recorded hash is used.
+func syncListField[T any](list func(item *postman2.Item) *[]T, match func(T) bool) syncField {
+	find := func(item *postman2.Item, rec string) (int, []byte) {
+		idx := -1
+		var idxData []byte
+		for i, el := range *list(item) {
+			if !match(el) {
+				continue
+			}
+			data, err := json.Marshal(el)
+			if err != nil {
+				continue
+			}
+			if len(rec) > 0 && syncHash(data) == rec {
+				return i, data
+			} else if idx < 0 {
+				idx, idxData = i, data
+			}
+		}
+		return idx, idxData
+	}
+	return syncField{
+		get: func(item *postman2.Item, rec string) ([]byte, bool) {
+			i, data := find(item, rec)
+			return data, i >= 0
+		},
+		set: func(item *postman2.Item, rec string, data []byte) {
+			var el T
+			syncUnmarshal(data, &el)
+			l := list(item)
+			if i, _ := find(item, rec); i >= 0 {
+				(*l)[i] = el
+			} else {
+				*l = append(*l, el)
+			}
+		},
+		del: func(item *postman2.Item, rec string) {
+			l := list(item)
+			if i, _ := find(item, rec); i >= 0 {
+				*l = append((*l)[:i], (*l)[i+1:]...)
+			}
+		},
+	}
+}
+
+// urlVariableName returns the path variable name, which generated URLs
+// store in `id`.
+func urlVariableName(v postman2.URLVariable) string {
+	if len(v.Key) > 0 {
+		return v.Key
+	}
+	return v.ID
+}
+
+func syncMarshal(v any, ok bool) ([]byte, bool) {
+	if !ok {
+		return nil, false
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func syncUnmarshal(data []byte, v any) {
+	_ = json.Unmarshal(data, v)
+}
@@ -0,0 +1,220 @@
+package openapi3postman2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const syncSpecV1 = `{
+"openapi":"3.0.3","info":{"title":"Users","version":"1.0.0"},
+"servers":[{"url":"https://api.example.com/v1"}],
+"paths":{
+  "/users":{"get":{"operationId":"listUsers","summary":"List users","tags":["users"],
+    "responses":{"200":{"description":"OK","content":{"application/json":{"example":[{"id":"1"}]}}}}}},
+  "/users/{userId}":{
+    "get":{"operationId":"getUser","summary":"Get user","description":"Returns a user.","tags":["users"],
+      "parameters":[{"name":"userId","in":"path","required":true,"schema":{"type":"string","default":"me"}}],
+      "responses":{"200":{"description":"OK"}}},
+    "delete":{"summary":"Delete user","tags":["users"],
+      "parameters":[{"name":"userId","in":"path","required":true,"schema":{"type":"string"}}],
+      "responses":{"204":{"description":"Deleted"}}}}}}`
+
+const syncSpecV2 = `{
+"openapi":"3.0.3","info":{"title":"Users","version":"2.0.0"},
+"servers":[{"url":"https://api.example.com/v1"}],
+"paths":{
+  "/users":{
+    "get":{"operationId":"listUsers","summary":"List users","tags":["users"],
+      "parameters":[{"name":"limit","in":"query","schema":{"type":"integer"}}],
+      "responses":{"200":{"description":"OK","content":{"application/json":{"example":[{"id":"1"}]}}}}},
+    "post":{"operationId":"createUser","summary":"Create user","tags":["users"],
+      "responses":{"201":{"description":"Created"}}}},
+  "/users/{userId}":{
+    "get":{"operationId":"getUser","summary":"Get a user","description":"Returns a user by ID.","tags":["users"],
+      "parameters":[{"name":"userId","in":"path","required":true,"schema":{"type":"string","default":"self"}}],
+      "responses":{"200":{"description":"OK"}}}}}}`
+
+func syncTestSpec(t *testing.T, data string) *openapi3.Spec {
+	spec, err := openapi3.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	return spec
+}
+
+func syncTestReadWrite(t *testing.T, pman postman2.Collection) postman2.Collection {
+	data, err := json.Marshal(pman)
+	if err != nil {
+		t.Fatalf("json.Marshal() Error [%s]", err.Error())
+	}
+	pman, err = postman2.NewCollectionFromBytes(data)
+	if err != nil {
+		t.Fatalf("postman2.NewCollectionFromBytes() Error [%s]", err.Error())
+	}
+	return pman
+}
+
+func syncTestItem(pman postman2.Collection, key string) *postman2.Item {
+	for _, ref := range syncItemRefs(pman.Item, nil) {
+		if ref.key == key {
+			return ref.item
+		}
+	}
+	return nil
+}
+
+func syncTestKeys(items []SyncItem) string {
+	keys := []string{}
+	for _, item := range items {
+		keys = append(keys, item.Key)
+	}
+	return strings.Join(keys, ",")
+}
+
+// TestSync ensures `Sync()` updates generated fields, keeps manual edits
+// and reports removed operations.
+func TestSync(t *testing.T) {
+	cfg := Configuration{}
+	pman, report, err := Sync(cfg, postman2.Collection{}, syncTestSpec(t, syncSpecV1))
+	if err != nil {
+		t.Fatalf("openapi3postman2.Sync() Error [%s]", err.Error())
+	}
+	if got := syncTestKeys(report.Added); got != "listUsers,DELETE /users/{userId},getUser" {
+		t.Errorf("openapi3postman2.Sync() Added Mismatch: want [%s], got [%s]",
+			"listUsers,DELETE /users/{userId},getUser", got)
+	}
+
+	pman = syncTestReadWrite(t, pman)
+	pman, report, err = Sync(cfg, pman, syncTestSpec(t, syncSpecV1))
+	if err != nil {
+		t.Fatalf("openapi3postman2.Sync() Error [%s]", err.Error())
+	}
+	if len(report.Added)+len(report.Updated)+len(report.Removed) > 0 {
+		t.Errorf("openapi3postman2.Sync() unchanged spec Mismatch: want [no changes], got [%v]", report)
+	}
+
+	getUser := syncTestItem(pman, "getUser")
+	getUser.Name = "Fetch user"
+	getUser.Request.Header = append(getUser.Request.Header, postman2.Header{Key: "X-Custom", Value: "1"})
+	getUser.Event = append(getUser.Event, postman2.Event{
+		Listen: postman2.EventListenTest,
+		Script: postman2.Script{Type: postman2.ScriptTypeJavaScript, Exec: []string{"pm.test('ok')"}}})
+	listUsers := syncTestItem(pman, "listUsers")
+	listUsers.Response = append(listUsers.Response, postman2.Response{Name: "Custom", Code: 200, Body: "[]"})
+	pman.Item = append(pman.Item, &postman2.Item{Name: "Scratch", Item: []*postman2.Item{
+		{Name: "Ping", Request: &postman2.Request{Method: "GET", URL: &postman2.URL{Raw: "https://example.com/ping"}}}}})
+	pman = syncTestReadWrite(t, pman)
+
+	pman, report, err = Sync(cfg, pman, syncTestSpec(t, syncSpecV2))
+	if err != nil {
+		t.Fatalf("openapi3postman2.Sync() Error [%s]", err.Error())
+	}
+	reportTests := []struct {
+		section string
+		want    string
+		got     string
+	}{
+		{"Added", "createUser", syncTestKeys(report.Added)},
+		{"Updated", "listUsers,getUser", syncTestKeys(report.Updated)},
+		{"Removed", "DELETE /users/{userId}", syncTestKeys(report.Removed)},
+	}
+	for _, tt := range reportTests {
+		if tt.got != tt.want {
+			t.Errorf("openapi3postman2.Sync() %s Mismatch: want [%s], got [%s]", tt.section, tt.want, tt.got)
+		}
+	}
+
+	getUser = syncTestItem(pman, "getUser")
+	listUsers = syncTestItem(pman, "listUsers")
+	itemTests := []struct {
+		name string
+		want string
+		got  string
+	}{
+		{"edited name", "Fetch user", getUser.Name},
+		{"generated description", "Returns a user by ID.", getUser.Request.Description.String()},
+		{"user header", "X-Custom", getUser.Request.Header[len(getUser.Request.Header)-1].Key},
+		{"user test script", "pm.test('ok')", strings.Join(getUser.Event[0].Script.Exec, "")},
+		{"generated query", "limit", listUsers.Request.URL.Query[0].Key},
+		{"generated path variable", "self", fmt.Sprint(getUser.Request.URL.Variable[0].Value)},
+		{"user example", "Custom", listUsers.Response[len(listUsers.Response)-1].Name},
+		{"user folder", "Scratch", pman.Item[len(pman.Item)-1].Name},
+	}
+	for _, tt := range itemTests {
+		if tt.got != tt.want {
+			t.Errorf("openapi3postman2.Sync() %s Mismatch: want [%s], got [%s]", tt.name, tt.want, tt.got)
+		}
+	}
+}
+
+// TestSyncAddThenChange ensures items added by `Sync()` are updated by the
+// next sync, without a sync in between to record their fields.
+func TestSyncAddThenChange(t *testing.T) {
+	cfg := Configuration{}
+	pman, _, err := Sync(cfg, postman2.Collection{}, syncTestSpec(t, syncSpecV1))
+	if err != nil {
+		t.Fatalf("openapi3postman2.Sync() Error [%s]", err.Error())
+	}
+	for i := 0; i < 2; i++ {
+		pman = syncTestReadWrite(t, pman)
+		var report SyncReport
+		pman, report, err = Sync(cfg, pman, syncTestSpec(t, syncSpecV2))
+		if err != nil {
+			t.Fatalf("openapi3postman2.Sync() Error [%s]", err.Error())
+		}
+		want := "listUsers,getUser"
+		if i > 0 {
+			want = ""
+		}
+		if got := syncTestKeys(report.Updated); got != want {
+			t.Errorf("openapi3postman2.Sync() Updated Mismatch at [%d]: want [%s], got [%s]", i, want, got)
+		}
+		getUser := syncTestItem(pman, "getUser")
+		if getUser.Name != "Get a user" || getUser.Request.Description.String() != "Returns a user by ID." {
+			t.Errorf("openapi3postman2.Sync() Mismatch at [%d]: want [Get a user] [Returns a user by ID.], got [%s] [%s]",
+				i, getUser.Name, getUser.Request.Description.String())
+		}
+	}
+}
+
+const syncExamplesSpecV1 = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"paths":{"/pets":{"get":{"operationId":"listPets","responses":{"200":{"description":"OK","content":{"application/json":{
+  "examples":{"a":{"value":[{"name":"Tom"}]},"b":{"value":[]}}}}}}}}}}`
+
+const syncExamplesSpecV2 = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"2.0.0"},
+"paths":{"/pets":{"get":{"operationId":"listPets","responses":{"200":{"description":"OK","content":{"application/json":{
+  "examples":{"a":{"value":[{"name":"Felix"}]},"b":{"value":[]},"c":{"value":[{"name":"Rex"}]}}}}}}}}}}`
+
+// TestSyncExamples ensures responses with the same status code are synced
+// one by one, as `ResponsesOpenAPI3ToPostman()` adds one per example.
+func TestSyncExamples(t *testing.T) {
+	cfg := Configuration{}
+	pman, _, err := Sync(cfg, postman2.Collection{}, syncTestSpec(t, syncExamplesSpecV1))
+	if err != nil {
+		t.Fatalf("openapi3postman2.Sync() Error [%s]", err.Error())
+	}
+	pman = syncTestReadWrite(t, pman)
+	listPets := syncTestItem(pman, "listPets")
+	listPets.Response[1].Body = "[{\"name\":\"Edited\"}]"
+
+	pman, _, err = Sync(cfg, syncTestReadWrite(t, pman), syncTestSpec(t, syncExamplesSpecV2))
+	if err != nil {
+		t.Fatalf("openapi3postman2.Sync() Error [%s]", err.Error())
+	}
+	listPets = syncTestItem(pman, "listPets")
+	got := []string{}
+	for _, resp := range listPets.Response {
+		got = append(got, resp.Name+"="+strings.Join(strings.Fields(resp.Body), ""))
+	}
+	want := `OK - a=[{"name":"Felix"}];OK - b=[{"name":"Edited"}];OK - c=[{"name":"Rex"}]`
+	if strings.Join(got, ";") != want {
+		t.Errorf("openapi3postman2.Sync() Response Mismatch: want [%s], got [%s]", want, strings.Join(got, ";"))
+	}
+}
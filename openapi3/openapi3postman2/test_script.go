@@ -0,0 +1,135 @@
+package openapi3postman2
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const statusCodeDefault = "default"
+
+// TestScriptOpenAPI3ToPostman returns a Postman `test` event for an
+// operation. The script asserts the response status code is documented,
+// the `Content-Type` is one of the documented media types and, for JSON
+// responses, that the body matches the response schema. Schemas are
+// embedded with `$ref`s resolved. Nil is returned when the operation
+// has no responses.
+func TestScriptOpenAPI3ToPostman(spec *openapi3.Spec, operation *oas3.Operation) (*postman2.Event, error) {
+	if operation == nil || operation.Responses == nil || operation.Responses.Len() == 0 {
+		return nil, nil
+	}
+	respsMap := operation.Responses.Map()
+	var codes []int
+	var ranges []int
+	var branches []string
+	for _, statusCode := range maputil.Keys(respsMap) {
+		if statusCode == statusCodeDefault {
+			continue
+		}
+		code, ok := responseStatusCode(statusCode)
+		if !ok {
+			continue
+		}
+		cond := fmt.Sprintf("pm.response.code === %d", code)
+		if strings.HasSuffix(strings.ToUpper(statusCode), "XX") {
+			ranges = append(ranges, code/100)
+			cond = fmt.Sprintf("Math.floor(pm.response.code / 100) === %d", code/100)
+		} else {
+			codes = append(codes, code)
+		}
+		lines, err := testScriptResponseLines(spec, statusCode, respsMap[statusCode])
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) > 0 {
+			branches = append(branches, testScriptBranch(cond, lines))
+		}
+	}
+
+	exec := []string{}
+	if defRespRef, ok := respsMap[statusCodeDefault]; ok {
+		// Any status code is documented when there is a default response.
+		lines, err := testScriptResponseLines(spec, statusCodeDefault, defRespRef)
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) > 0 {
+			branches = append(branches, testScriptBranch("", lines))
+		}
+	} else if len(codes) > 0 || len(ranges) > 0 {
+		exec = append(exec,
+			"pm.test(\"Status code is documented\", function () {",
+			fmt.Sprintf("    pm.expect(%s.includes(pm.response.code) || %s.includes(Math.floor(pm.response.code / 100))).to.be.true;",
+				jsIntArray(codes), jsIntArray(ranges)),
+			"});")
+	}
+	if len(branches) > 0 {
+		exec = append(exec, strings.Split(strings.Join(branches, " else "), "\n")...)
+	}
+	if len(exec) == 0 {
+		return nil, nil
+	}
+	return &postman2.Event{
+		Listen: postman2.EventListenTest,
+		Script: postman2.Script{
+			Type: postman2.ScriptTypeJavaScript,
+			Exec: exec}}, nil
+}
+
+func testScriptBranch(cond string, lines []string) string {
+	head := "{"
+	if len(cond) > 0 {
+		head = "if (" + cond + ") {"
+	}
+	return head + "\n    " + strings.Join(lines, "\n    ") + "\n}"
+}
+
+// testScriptResponseLines returns the assertions for one documented
+// response. Media types with wildcards skip the `Content-Type` check.
+func testScriptResponseLines(spec *openapi3.Spec, statusCode string, respRef *oas3.ResponseRef) ([]string, error) {
+	respRef = responseRefResolve(spec, respRef)
+	if respRef == nil || respRef.Value == nil || len(respRef.Value.Content) == 0 {
+		return nil, nil
+	}
+	content := respRef.Value.Content
+	mediaTypes := maputil.Keys(content)
+	lines := []string{}
+	if !strings.Contains(strings.Join(mediaTypes, ","), "*") {
+		mtBytes, err := json.Marshal(mediaTypes)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines,
+			fmt.Sprintf("pm.test(\"%s Content-Type is documented\", function () {", statusCode),
+			fmt.Sprintf("    pm.expect((pm.response.headers.get(\"Content-Type\") || \"\").split(\";\")[0].trim()).to.be.oneOf(%s);", string(mtBytes)),
+			"});")
+	}
+	for _, mediaType := range mediaTypes {
+		if !strings.Contains(strings.ToLower(mediaType), "json") || content[mediaType].Schema == nil {
+			continue
+		}
+		schemaBytes, err := json.Marshal(JSONSchemaOpenAPI3(spec, content[mediaType].Schema))
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines,
+			fmt.Sprintf("pm.test(\"%s response body matches schema\", function () {", statusCode),
+			fmt.Sprintf("    pm.response.to.have.jsonSchema(%s);", string(schemaBytes)),
+			"});")
+		break
+	}
+	return lines, nil
+}
+
+func jsIntArray(ints []int) string {
+	strs := []string{}
+	for _, i := range ints {
+		strs = append(strs, fmt.Sprintf("%d", i))
+	}
+	return "[" + strings.Join(strs, ", ") + "]"
+}
@@ -0,0 +1,81 @@
+package openapi3postman2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/spectrum/postman2"
+)
+
+const scriptSpec = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"paths":{
+  "/pets/{petId}":{"get":{"operationId":"getPet","responses":{
+    "200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}},
+    "404":{"description":"Not Found","content":{"text/plain":{"schema":{"type":"string"}}}},
+    "5XX":{"description":"Server Error"}}}},
+  "/pets":{"post":{"operationId":"createPet","responses":{
+    "201":{"description":"Created"},
+    "default":{"description":"Error","content":{"application/json":{"schema":{"type":"object"}},"*/*":{}}}}}}},
+"components":{"schemas":{
+  "Pet":{"type":"object","required":["id","name","password"],"x-internal":true,"properties":{
+    "id":{"type":"integer","format":"int64","example":1},
+    "name":{"type":"string","nullable":true},
+    "password":{"type":"string","writeOnly":true},
+    "owner":{"$ref":"#/components/schemas/Owner"}}},
+  "Owner":{"type":"object","properties":{
+    "age":{"type":"integer","minimum":0,"exclusiveMinimum":true},
+    "pet":{"$ref":"#/components/schemas/Pet"}}}}}}`
+
+const scriptGetPet = `pm.test("Status code is documented", function () {
+    pm.expect([200, 404].includes(pm.response.code) || [5].includes(Math.floor(pm.response.code / 100))).to.be.true;
+});
+if (pm.response.code === 200) {
+    pm.test("200 Content-Type is documented", function () {
+        pm.expect((pm.response.headers.get("Content-Type") || "").split(";")[0].trim()).to.be.oneOf(["application/json"]);
+    });
+    pm.test("200 response body matches schema", function () {
+        pm.response.to.have.jsonSchema({"properties":{"id":{"type":"integer"},"name":{"type":["string","null"]},"owner":{"properties":{"age":{"exclusiveMinimum":0,"type":"integer"},"pet":{}},"type":"object"},"password":{"type":"string"}},"required":["id","name"],"type":"object"});
+    });
+} else if (pm.response.code === 404) {
+    pm.test("404 Content-Type is documented", function () {
+        pm.expect((pm.response.headers.get("Content-Type") || "").split(";")[0].trim()).to.be.oneOf(["text/plain"]);
+    });
+}`
+
+const scriptCreatePet = `{
+    pm.test("default response body matches schema", function () {
+        pm.response.to.have.jsonSchema({"type":"object"});
+    });
+}`
+
+var testScriptTests = []struct {
+	path   string
+	method string
+	want   string
+}{
+	{"/pets/{petId}", "GET", scriptGetPet},
+	{"/pets", "POST", scriptCreatePet},
+}
+
+// TestTestScriptOpenAPI3ToPostman ensures the generated test script checks
+// documented status codes, media types and response schemas.
+func TestTestScriptOpenAPI3ToPostman(t *testing.T) {
+	spec := syncTestSpec(t, scriptSpec)
+	for _, tt := range testScriptTests {
+		op := spec.Paths.Find(tt.path).GetOperation(tt.method)
+		event, err := TestScriptOpenAPI3ToPostman(spec, op)
+		if err != nil {
+			t.Fatalf("openapi3postman2.TestScriptOpenAPI3ToPostman(%s %s) Error [%s]", tt.method, tt.path, err.Error())
+		} else if event == nil {
+			t.Fatalf("openapi3postman2.TestScriptOpenAPI3ToPostman(%s %s) Mismatch: want event, got nil", tt.method, tt.path)
+		}
+		if event.Listen != postman2.EventListenTest || event.Script.Type != postman2.ScriptTypeJavaScript {
+			t.Errorf("openapi3postman2.TestScriptOpenAPI3ToPostman(%s %s) Mismatch: want test JavaScript event, got [%s] [%s]",
+				tt.method, tt.path, event.Listen, event.Script.Type)
+		}
+		if got := strings.Join(event.Script.Exec, "\n"); got != tt.want {
+			t.Errorf("openapi3postman2.TestScriptOpenAPI3ToPostman(%s %s) Mismatch: want [%s], got [%s]", tt.method, tt.path, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package openapi3
+
+import (
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/type/maputil"
+)
+
+const schemaExampleMaxDepthDefault = 8
+
+// SchemaExampleOpts controls `SchemaExample()`. `SkipReadOnly` is used for
+// request bodies and `SkipWriteOnly` for responses.
+type SchemaExampleOpts struct {
+	SkipReadOnly  bool
+	SkipWriteOnly bool
+	MaxDepth      int
+}
+
+// SchemaExample returns an example value for a schema. It uses `example`,
+// `default` and the first `enum` value when present, merges `allOf` and
+// uses the first `oneOf` or `anyOf` schema. Otherwise a value is synthesized
+// from the type and format. `$ref`s are resolved through the spec's
+// components and recursive references are omitted.
+func SchemaExample(spec *Spec, schemaRef *oas3.SchemaRef, opts *SchemaExampleOpts) any {
+	ex := schemaExampler{spec: spec, visiting: map[string]bool{}}
+	if opts != nil {
+		ex.opts = *opts
+	}
+	if ex.opts.MaxDepth <= 0 {
+		ex.opts.MaxDepth = schemaExampleMaxDepthDefault
+	}
+	return ex.example(schemaRef, 0)
+}
+
+// SchemaRefResolve returns the schema for a schema ref, looking up
+// `#/components/schemas/` references that have not been loaded.
+func SchemaRefResolve(spec *Spec, schemaRef *oas3.SchemaRef) *oas3.Schema {
+	if schemaRef == nil {
+		return nil
+	} else if schemaRef.Value != nil {
+		return schemaRef.Value
+	}
+	if spec == nil || spec.Components == nil ||
+		!strings.HasPrefix(schemaRef.Ref, PointerComponentsSchemas+"/") {
+		return nil
+	}
+	if ref, ok := spec.Components.Schemas[strings.TrimPrefix(schemaRef.Ref, PointerComponentsSchemas+"/")]; ok {
+		return SchemaRefResolve(spec, ref)
+	}
+	return nil
+}
+
+type schemaExampler struct {
+	spec     *Spec
+	opts     SchemaExampleOpts
+	visiting map[string]bool
+}
+
+func (ex schemaExampler) example(schemaRef *oas3.SchemaRef, depth int) any {
+	if schemaRef == nil || depth > ex.opts.MaxDepth {
+		return nil
+	}
+	if ref := schemaRef.Ref; len(ref) > 0 {
+		if ex.visiting[ref] {
+			return nil
+		}
+		ex.visiting[ref] = true
+		defer delete(ex.visiting, ref)
+	}
+	sch := SchemaRefResolve(ex.spec, schemaRef)
+	if sch == nil {
+		return nil
+	}
+	switch {
+	case sch.Example != nil:
+		return sch.Example
+	case sch.Default != nil:
+		return sch.Default
+	case len(sch.Enum) > 0:
+		return sch.Enum[0]
+	case len(sch.AllOf) > 0:
+		return ex.exampleAllOf(sch, depth)
+	case len(sch.OneOf) > 0:
+		return ex.example(sch.OneOf[0], depth+1)
+	case len(sch.AnyOf) > 0:
+		return ex.example(sch.AnyOf[0], depth+1)
+	}
+	switch {
+	case TypesRefIs(sch.Type, TypeObject) || len(sch.Properties) > 0:
+		return ex.exampleObject(sch, depth)
+	case TypesRefIs(sch.Type, TypeArray):
+		if item := ex.example(sch.Items, depth+1); item != nil {
+			return []any{item}
+		}
+		return []any{}
+	case TypesRefIs(sch.Type, TypeString):
+		return stringExample(sch.Format)
+	case TypesRefIs(sch.Type, TypeInteger):
+		if sch.Min != nil {
+			return int64(*sch.Min)
+		}
+		return 0
+	case TypesRefIs(sch.Type, TypeNumber):
+		if sch.Min != nil {
+			return *sch.Min
+		}
+		return 0.0
+	case TypesRefIs(sch.Type, TypeBoolean):
+		return true
+	}
+	return nil
+}
+
+func (ex schemaExampler) exampleAllOf(sch *oas3.Schema, depth int) any {
+	merged := map[string]any{}
+	var other any
+	for _, subRef := range sch.AllOf {
+		val := ex.example(subRef, depth+1)
+		if m, ok := val.(map[string]any); ok {
+			for k, v := range m {
+				merged[k] = v
+			}
+		} else if val != nil && other == nil {
+			other = val
+		}
+	}
+	if len(sch.Properties) > 0 {
+		if m, ok := ex.exampleObject(sch, depth).(map[string]any); ok {
+			for k, v := range m {
+				merged[k] = v
+			}
+		}
+	}
+	if len(merged) == 0 && other != nil {
+		return other
+	}
+	return merged
+}
+
+func (ex schemaExampler) exampleObject(sch *oas3.Schema, depth int) any {
+	obj := map[string]any{}
+	for _, propName := range maputil.Keys(sch.Properties) {
+		propRef := sch.Properties[propName]
+		if prop := SchemaRefResolve(ex.spec, propRef); prop != nil {
+			if (ex.opts.SkipReadOnly && prop.ReadOnly) ||
+				(ex.opts.SkipWriteOnly && prop.WriteOnly) {
+				continue
+			}
+		}
+		if val := ex.example(propRef, depth+1); val != nil {
+			obj[propName] = val
+		}
+	}
+	if len(sch.Properties) == 0 && sch.AdditionalProperties.Schema != nil {
+		if val := ex.example(sch.AdditionalProperties.Schema, depth+1); val != nil {
+			obj["key"] = val
+		}
+	}
+	return obj
+}
+
+func stringExample(format string) string {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatDate:
+		return "2024-01-01"
+	case FormatDateTime:
+		return "2024-01-01T00:00:00Z"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "byte":
+		return "c3RyaW5n"
+	case "binary":
+		return ""
+	case "password":
+		return "********"
+	}
+	return "string"
+}
+
+// MediaTypeExample returns the media type `example`, the first of its
+// `examples` by name, or an example synthesized from its schema.
+func MediaTypeExample(spec *Spec, mediaType *oas3.MediaType, opts *SchemaExampleOpts) any {
+	if mediaType == nil {
+		return nil
+	} else if mediaType.Example != nil {
+		return mediaType.Example
+	}
+	for _, exName := range maputil.Keys(mediaType.Examples) {
+		if ex := ExampleRefResolve(spec, mediaType.Examples[exName]); ex != nil && ex.Value != nil {
+			return ex.Value
+		}
+	}
+	return SchemaExample(spec, mediaType.Schema, opts)
+}
+
+// ExampleRefResolve returns the example for an example ref, looking up
+// `#/components/examples/` references that have not been loaded.
+func ExampleRefResolve(spec *Spec, exampleRef *oas3.ExampleRef) *oas3.Example {
+	if exampleRef == nil {
+		return nil
+	} else if exampleRef.Value != nil {
+		return exampleRef.Value
+	}
+	prefix := "#/components/examples/"
+	if spec == nil || spec.Components == nil || !strings.HasPrefix(exampleRef.Ref, prefix) {
+		return nil
+	}
+	if ref, ok := spec.Components.Examples[strings.TrimPrefix(exampleRef.Ref, prefix)]; ok {
+		return ExampleRefResolve(spec, ref)
+	}
+	return nil
+}
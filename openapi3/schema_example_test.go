@@ -0,0 +1,46 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const schemaExampleSpecJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},"paths":{},
+"components":{"schemas":{
+  "Base":{"type":"object","properties":{"id":{"type":"string","format":"uuid","readOnly":true},"created":{"type":"string","format":"date-time"}}},
+  "Pet":{"allOf":[{"$ref":"#/components/schemas/Base"},{"type":"object","properties":{
+    "name":{"type":"string","example":"Rex"},
+    "status":{"type":"string","enum":["available","sold"]},
+    "age":{"type":"integer","default":3},
+    "owner":{"oneOf":[{"$ref":"#/components/schemas/Owner"},{"type":"string"}]},
+    "tags":{"type":"array","items":{"type":"string"}}}}]},
+  "Owner":{"type":"object","properties":{"name":{"type":"string"},"pets":{"type":"array","items":{"$ref":"#/components/schemas/Pet"}}}}}}}`
+
+var schemaExampleTests = []struct {
+	schemaName   string
+	skipReadOnly bool
+	want         string
+}{
+	{"Base", false, `{"created":"2024-01-01T00:00:00Z","id":"00000000-0000-0000-0000-000000000000"}`},
+	{"Pet", true, `{"age":3,"created":"2024-01-01T00:00:00Z","name":"Rex","owner":{"name":"string","pets":[{"age":3,"created":"2024-01-01T00:00:00Z","name":"Rex","status":"available","tags":["string"]}]},"status":"available","tags":["string"]}`},
+}
+
+// TestSchemaExample ensures examples are built with refs, allOf, oneOf, enums and defaults resolved.
+func TestSchemaExample(t *testing.T) {
+	spec, err := Parse([]byte(schemaExampleSpecJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	for _, tt := range schemaExampleTests {
+		ex := SchemaExample(spec, spec.Components.Schemas[tt.schemaName], &SchemaExampleOpts{SkipReadOnly: tt.skipReadOnly})
+		bytes, err := json.Marshal(ex)
+		if err != nil {
+			t.Fatalf("json.Marshal() Error [%s]", err.Error())
+		}
+		if string(bytes) != tt.want {
+			t.Errorf("openapi3.SchemaExample(\"%s\") Mismatch: want [%s], got [%s]",
+				tt.schemaName, tt.want, string(bytes))
+		}
+	}
+}
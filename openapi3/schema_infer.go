@@ -0,0 +1,88 @@
+package openapi3
+
+import (
+	"math"
+	"time"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/type/maputil"
+)
+
+// SchemaInfer returns a schema inferred from a value decoded from JSON.
+// Whole numbers are `integer`, RFC 3339 strings are `date-time` and `date`
+// strings and `null` is a `nullable` schema without a type. Array item
+// schemas are inferred from all elements, with object properties merged.
+// Properties are not marked as required since a single example cannot
+// show which are optional.
+func SchemaInfer(v any) *oas3.Schema {
+	switch val := v.(type) {
+	case nil:
+		return &oas3.Schema{Nullable: true}
+	case bool:
+		return oas3.NewBoolSchema()
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return oas3.NewIntegerSchema()
+		}
+		return oas3.NewFloat64Schema()
+	case int, int32, int64:
+		return oas3.NewIntegerSchema()
+	case string:
+		sch := oas3.NewStringSchema()
+		if _, err := time.Parse(time.RFC3339, val); err == nil {
+			sch.Format = FormatDateTime
+		} else if _, err := time.Parse(time.DateOnly, val); err == nil {
+			sch.Format = FormatDate
+		}
+		return sch
+	case []any:
+		sch := oas3.NewArraySchema()
+		var items *oas3.Schema
+		for _, item := range val {
+			items = schemaInferMerge(items, SchemaInfer(item))
+		}
+		if items == nil {
+			items = &oas3.Schema{}
+		}
+		sch.Items = oas3.NewSchemaRef("", items)
+		return sch
+	case map[string]any:
+		sch := oas3.NewObjectSchema()
+		for _, key := range maputil.Keys(val) {
+			sch.WithProperty(key, SchemaInfer(val[key]))
+		}
+		return sch
+	}
+	return &oas3.Schema{}
+}
+
+// schemaInferMerge combines schemas inferred from array elements. Object
+// properties are merged, `integer` widens to `number` and `null` makes the
+// other schema nullable. Otherwise the first schema is kept.
+func schemaInferMerge(a, b *oas3.Schema) *oas3.Schema {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.Type == nil:
+		b.Nullable = b.Nullable || a.Nullable
+		return b
+	case b.Type == nil:
+		a.Nullable = a.Nullable || b.Nullable
+		return a
+	case a.Type.Is(TypeInteger) && b.Type.Is(TypeNumber):
+		return b
+	case a.Type.Is(TypeObject) && b.Type.Is(TypeObject):
+		for name, propRef := range b.Properties {
+			if existing, ok := a.Properties[name]; ok {
+				a.Properties[name] = oas3.NewSchemaRef("", schemaInferMerge(existing.Value, propRef.Value))
+			} else {
+				a.Properties[name] = propRef
+			}
+		}
+	case a.Type.Is(TypeArray) && b.Type.Is(TypeArray) && a.Items != nil && b.Items != nil:
+		a.Items = oas3.NewSchemaRef("", schemaInferMerge(a.Items.Value, b.Items.Value))
+	}
+	return a
+}
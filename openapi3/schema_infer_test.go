@@ -0,0 +1,38 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var schemaInferTests = []struct {
+	value    string
+	wantJSON string
+}{
+	{`"abc"`, `{"type":"string"}`},
+	{`"2024-01-02T03:04:05Z"`, `{"format":"date-time","type":"string"}`},
+	{`1`, `{"type":"integer"}`},
+	{`1.5`, `{"type":"number"}`},
+	{`null`, `{"nullable":true}`},
+	{`[1, 2.5]`, `{"items":{"type":"number"},"type":"array"}`},
+	{`[{"a":1},{"b":null}]`, `{"items":{"properties":{"a":{"type":"integer"},"b":{"nullable":true}},"type":"object"},"type":"array"}`},
+	{`{"id":"x","ok":true}`, `{"properties":{"id":{"type":"string"},"ok":{"type":"boolean"}},"type":"object"}`},
+}
+
+// TestSchemaInfer ensures `SchemaInfer()` infers schemas from JSON values.
+func TestSchemaInfer(t *testing.T) {
+	for _, tt := range schemaInferTests {
+		var v any
+		if err := json.Unmarshal([]byte(tt.value), &v); err != nil {
+			t.Fatalf("json.Unmarshal() Error [%s]", err.Error())
+		}
+		got, err := json.Marshal(SchemaInfer(v))
+		if err != nil {
+			t.Fatalf("json.Marshal() Error [%s]", err.Error())
+		}
+		if string(got) != tt.wantJSON {
+			t.Errorf("openapi3.SchemaInfer(\"%s\") Mismatch: want [%s], got [%s]",
+				tt.value, tt.wantJSON, string(got))
+		}
+	}
+}
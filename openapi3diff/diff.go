@@ -0,0 +1,737 @@
+package openapi3diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/encoding/jsonpointer"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/spectrum/openapi3"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	ChangeTypeAdded   = "added"
+	ChangeTypeRemoved = "removed"
+	ChangeTypeChanged = "changed"
+
+	CategoryOperation   = "operation"
+	CategoryParameter   = "parameter"
+	CategoryRequestBody = "requestBody"
+	CategoryResponse    = "response"
+	CategoryHeader      = "header"
+	CategorySchema      = "schema"
+	CategoryProperty    = "property"
+)
+
+// Change represents a single difference between two specs. `Location` is a
+// JSON pointer into the spec where the change was found, using the new spec
+// for additions and changes and the old spec for removals.
+type Change struct {
+	Type        string `json:"type"`
+	Category    string `json:"category"`
+	Location    string `json:"location"`
+	Breaking    bool   `json:"breaking"`
+	Description string `json:"description"`
+	Old         string `json:"old,omitempty"`
+	New         string `json:"new,omitempty"`
+}
+
+// Diff compares `specOld` to `specNew` and returns a `Report` of added, removed
+// and changed operations, parameters, request bodies, response codes, response
+// headers and schema properties, each classified as breaking or non-breaking.
+// Schema changes are classified by whether the schema is sent in requests,
+// returned in responses or both.
+func Diff(specOld, specNew *openapi3.Spec) *Report {
+	d := differ{report: NewReport()}
+	if specOld == nil {
+		specOld = &openapi3.Spec{}
+	}
+	if specNew == nil {
+		specNew = &openapi3.Spec{}
+	}
+	d.diffPathItems(specOld, specNew)
+	d.diffOperations(specOld, specNew)
+	d.diffComponentSchemas(specOld, specNew)
+	d.report.Sort()
+	return d.report
+}
+
+type differ struct {
+	report *Report
+}
+
+func (d *differ) add(chg Change) {
+	d.report.Changes = append(d.report.Changes, chg)
+}
+
+// diffPathItems compares parameters shared by all operations of a path.
+func (d *differ) diffPathItems(specOld, specNew *openapi3.Spec) {
+	if specOld.Paths == nil || specNew.Paths == nil {
+		return
+	}
+	pathItemsNew := specNew.Paths.Map()
+	for path, piOld := range specOld.Paths.Map() {
+		piNew, ok := pathItemsNew[path]
+		if !ok || piOld == nil || piNew == nil {
+			continue
+		}
+		d.diffParameters(jsonpointer.PointerSubEscapeAll("#/paths/%s", path), piOld.Parameters, piNew.Parameters)
+	}
+}
+
+func operationsMap(spec *openapi3.Spec) map[string]*oas3.Operation {
+	ops := map[string]*oas3.Operation{}
+	if spec.Paths == nil {
+		return ops
+	}
+	openapi3.VisitOperations(spec, func(path, method string, op *oas3.Operation) {
+		if op == nil {
+			return
+		}
+		ops[opPointer(path, method)] = op
+	})
+	return ops
+}
+
+func opPointer(path, method string) string {
+	return jsonpointer.PointerSubEscapeAll("#/paths/%s/%s", path, strings.ToLower(method))
+}
+
+func (d *differ) diffOperations(specOld, specNew *openapi3.Spec) {
+	opsOld := operationsMap(specOld)
+	opsNew := operationsMap(specNew)
+	for _, ptr := range maputil.Keys(opsOld) {
+		opOld := opsOld[ptr]
+		opNew, ok := opsNew[ptr]
+		if !ok {
+			d.add(Change{
+				Type:        ChangeTypeRemoved,
+				Category:    CategoryOperation,
+				Location:    ptr,
+				Breaking:    true,
+				Description: "operation removed",
+				Old:         opOld.OperationID})
+			continue
+		}
+		d.diffOperation(ptr, opOld, opNew)
+	}
+	for _, ptr := range maputil.Keys(opsNew) {
+		if _, ok := opsOld[ptr]; !ok {
+			d.add(Change{
+				Type:        ChangeTypeAdded,
+				Category:    CategoryOperation,
+				Location:    ptr,
+				Description: "operation added",
+				New:         opsNew[ptr].OperationID})
+		}
+	}
+}
+
+func (d *differ) diffOperation(ptr string, opOld, opNew *oas3.Operation) {
+	d.diffParameters(ptr, opOld.Parameters, opNew.Parameters)
+	d.diffRequestBody(ptr+"/requestBody", opOld.RequestBody, opNew.RequestBody)
+	d.diffResponses(ptr+"/responses", opOld.Responses, opNew.Responses)
+}
+
+type indexedParameter struct {
+	index int
+	param *oas3.ParameterRef
+}
+
+func parametersMap(params oas3.Parameters) map[string]indexedParameter {
+	m := map[string]indexedParameter{}
+	for i, paramRef := range params {
+		if paramRef == nil {
+			continue
+		}
+		key := paramRef.Ref
+		if paramRef.Value != nil {
+			key = paramRef.Value.In + " " + paramRef.Value.Name
+		}
+		m[key] = indexedParameter{index: i, param: paramRef}
+	}
+	return m
+}
+
+func (d *differ) diffParameters(opPtr string, paramsOld, paramsNew oas3.Parameters) {
+	mOld := parametersMap(paramsOld)
+	mNew := parametersMap(paramsNew)
+	for _, key := range maputil.Keys(mOld) {
+		ipOld := mOld[key]
+		ipNew, ok := mNew[key]
+		if !ok {
+			d.add(Change{
+				Type:        ChangeTypeRemoved,
+				Category:    CategoryParameter,
+				Location:    fmt.Sprintf("%s/parameters/%d", opPtr, ipOld.index),
+				Breaking:    true,
+				Description: "parameter removed",
+				Old:         key})
+			continue
+		}
+		ptr := fmt.Sprintf("%s/parameters/%d", opPtr, ipNew.index)
+		pOld := ipOld.param.Value
+		pNew := ipNew.param.Value
+		if pOld == nil || pNew == nil {
+			if ipOld.param.Ref != ipNew.param.Ref {
+				d.add(Change{
+					Type:        ChangeTypeChanged,
+					Category:    CategoryParameter,
+					Location:    ptr,
+					Breaking:    true,
+					Description: "parameter reference changed",
+					Old:         ipOld.param.Ref,
+					New:         ipNew.param.Ref})
+			}
+			continue
+		}
+		if !pOld.Required && pNew.Required {
+			d.add(Change{
+				Type:        ChangeTypeChanged,
+				Category:    CategoryParameter,
+				Location:    ptr + "/required",
+				Breaking:    true,
+				Description: "parameter became required",
+				Old:         key})
+		} else if pOld.Required && !pNew.Required {
+			d.add(Change{
+				Type:        ChangeTypeChanged,
+				Category:    CategoryParameter,
+				Location:    ptr + "/required",
+				Description: "parameter became optional",
+				Old:         key})
+		}
+		d.diffSchema(ptr+"/schema", directionRequest, pOld.Schema, pNew.Schema, 0)
+	}
+	for _, key := range maputil.Keys(mNew) {
+		if _, ok := mOld[key]; ok {
+			continue
+		}
+		ipNew := mNew[key]
+		required := ipNew.param.Value != nil && ipNew.param.Value.Required
+		desc := "optional parameter added"
+		if required {
+			desc = "required parameter added"
+		}
+		d.add(Change{
+			Type:        ChangeTypeAdded,
+			Category:    CategoryParameter,
+			Location:    fmt.Sprintf("%s/parameters/%d", opPtr, ipNew.index),
+			Breaking:    required,
+			Description: desc,
+			New:         key})
+	}
+}
+
+func (d *differ) diffRequestBody(ptr string, rbOld, rbNew *oas3.RequestBodyRef) {
+	hasOld := rbOld != nil && (rbOld.Value != nil || rbOld.Ref != "")
+	hasNew := rbNew != nil && (rbNew.Value != nil || rbNew.Ref != "")
+	switch {
+	case !hasOld && !hasNew:
+		return
+	case hasOld && !hasNew:
+		d.add(Change{
+			Type:        ChangeTypeRemoved,
+			Category:    CategoryRequestBody,
+			Location:    ptr,
+			Breaking:    true,
+			Description: "request body removed"})
+		return
+	case !hasOld && hasNew:
+		required := rbNew.Value != nil && rbNew.Value.Required
+		d.add(Change{
+			Type:        ChangeTypeAdded,
+			Category:    CategoryRequestBody,
+			Location:    ptr,
+			Breaking:    required,
+			Description: "request body added"})
+		return
+	}
+	if rbOld.Value == nil || rbNew.Value == nil {
+		if rbOld.Ref != rbNew.Ref {
+			d.add(Change{
+				Type:        ChangeTypeChanged,
+				Category:    CategoryRequestBody,
+				Location:    ptr,
+				Breaking:    true,
+				Description: "request body reference changed",
+				Old:         rbOld.Ref,
+				New:         rbNew.Ref})
+		}
+		return
+	}
+	if !rbOld.Value.Required && rbNew.Value.Required {
+		d.add(Change{
+			Type:        ChangeTypeChanged,
+			Category:    CategoryRequestBody,
+			Location:    ptr + "/required",
+			Breaking:    true,
+			Description: "request body became required"})
+	}
+	d.diffContent(ptr+"/content", CategoryRequestBody, directionRequest, rbOld.Value.Content, rbNew.Value.Content)
+}
+
+// diffContent compares media types. A removed media type is breaking for
+// requests since clients may be sending it, and for responses since clients
+// may be expecting it.
+func (d *differ) diffContent(ptr, category string, dir direction, cOld, cNew oas3.Content) {
+	for _, mt := range maputil.Keys(cOld) {
+		mtPtr := ptr + "/" + jsonpointer.PropertyNameEscape(mt)
+		mtNew, ok := cNew[mt]
+		if !ok {
+			d.add(Change{
+				Type:        ChangeTypeRemoved,
+				Category:    category,
+				Location:    mtPtr,
+				Breaking:    true,
+				Description: "media type removed",
+				Old:         mt})
+			continue
+		}
+		mtOld := cOld[mt]
+		if mtOld == nil || mtNew == nil {
+			continue
+		}
+		d.diffSchema(mtPtr+"/schema", dir, mtOld.Schema, mtNew.Schema, 0)
+	}
+	for _, mt := range maputil.Keys(cNew) {
+		if _, ok := cOld[mt]; !ok {
+			d.add(Change{
+				Type:        ChangeTypeAdded,
+				Category:    category,
+				Location:    ptr + "/" + jsonpointer.PropertyNameEscape(mt),
+				Description: "media type added",
+				New:         mt})
+		}
+	}
+}
+
+func (d *differ) diffResponses(ptr string, respsOld, respsNew *oas3.Responses) {
+	mOld := map[string]*oas3.ResponseRef{}
+	mNew := map[string]*oas3.ResponseRef{}
+	if respsOld != nil {
+		mOld = respsOld.Map()
+	}
+	if respsNew != nil {
+		mNew = respsNew.Map()
+	}
+	for _, status := range maputil.Keys(mOld) {
+		statusPtr := ptr + "/" + status
+		respNew, ok := mNew[status]
+		if !ok {
+			d.add(Change{
+				Type:        ChangeTypeRemoved,
+				Category:    CategoryResponse,
+				Location:    statusPtr,
+				Breaking:    true,
+				Description: "response code removed",
+				Old:         status})
+			continue
+		}
+		respOld := mOld[status]
+		if respOld == nil || respNew == nil {
+			continue
+		}
+		if respOld.Value == nil || respNew.Value == nil {
+			if respOld.Ref != respNew.Ref {
+				d.add(Change{
+					Type:        ChangeTypeChanged,
+					Category:    CategoryResponse,
+					Location:    statusPtr,
+					Breaking:    true,
+					Description: "response reference changed",
+					Old:         respOld.Ref,
+					New:         respNew.Ref})
+			}
+			continue
+		}
+		d.diffHeaders(statusPtr+"/headers", respOld.Value.Headers, respNew.Value.Headers)
+		d.diffContent(statusPtr+"/content", CategoryResponse, directionResponse, respOld.Value.Content, respNew.Value.Content)
+	}
+	for _, status := range maputil.Keys(mNew) {
+		if _, ok := mOld[status]; !ok {
+			d.add(Change{
+				Type:        ChangeTypeAdded,
+				Category:    CategoryResponse,
+				Location:    ptr + "/" + status,
+				Description: "response code added",
+				New:         status})
+		}
+	}
+}
+
+// diffHeaders compares response headers. A removed header is breaking since
+// clients may be reading it.
+func (d *differ) diffHeaders(ptr string, hOld, hNew oas3.Headers) {
+	for _, name := range maputil.Keys(hOld) {
+		hdrPtr := ptr + "/" + jsonpointer.PropertyNameEscape(name)
+		hdrNew, ok := hNew[name]
+		if !ok {
+			d.add(Change{
+				Type:        ChangeTypeRemoved,
+				Category:    CategoryHeader,
+				Location:    hdrPtr,
+				Breaking:    true,
+				Description: "response header removed",
+				Old:         name})
+			continue
+		}
+		hdrOld := hOld[name]
+		if hdrOld == nil || hdrNew == nil {
+			continue
+		}
+		if hdrOld.Value == nil || hdrNew.Value == nil {
+			if hdrOld.Ref != hdrNew.Ref {
+				d.add(Change{
+					Type:        ChangeTypeChanged,
+					Category:    CategoryHeader,
+					Location:    hdrPtr,
+					Breaking:    true,
+					Description: "response header reference changed",
+					Old:         hdrOld.Ref,
+					New:         hdrNew.Ref})
+			}
+			continue
+		}
+		d.diffSchema(hdrPtr+"/schema", directionResponse, hdrOld.Value.Schema, hdrNew.Value.Schema, 0)
+	}
+	for _, name := range maputil.Keys(hNew) {
+		if _, ok := hOld[name]; !ok {
+			d.add(Change{
+				Type:        ChangeTypeAdded,
+				Category:    CategoryHeader,
+				Location:    ptr + "/" + jsonpointer.PropertyNameEscape(name),
+				Description: "response header added",
+				New:         name})
+		}
+	}
+}
+
+// diffComponentSchemas compares component schemas using the direction they
+// are used in by either spec. Unused schemas are treated as used in both.
+func (d *differ) diffComponentSchemas(specOld, specNew *openapi3.Spec) {
+	dirsOld := schemaDirections(specOld)
+	dirsNew := schemaDirections(specNew)
+	var schOld, schNew oas3.Schemas
+	if specOld.Components != nil {
+		schOld = specOld.Components.Schemas
+	}
+	if specNew.Components != nil {
+		schNew = specNew.Components.Schemas
+	}
+	for _, name := range maputil.Keys(schOld) {
+		ptr := jsonpointer.PointerSubEscapeAll("#/components/schemas/%s", name)
+		refNew, ok := schNew[name]
+		if !ok {
+			d.add(Change{
+				Type:        ChangeTypeRemoved,
+				Category:    CategorySchema,
+				Location:    ptr,
+				Breaking:    true,
+				Description: "schema removed",
+				Old:         name})
+			continue
+		}
+		dir := dirsOld[name] | dirsNew[name]
+		if dir == 0 {
+			dir = directionBoth
+		}
+		d.diffSchema(ptr, dir, schOld[name], refNew, 0)
+	}
+	for _, name := range maputil.Keys(schNew) {
+		if _, ok := schOld[name]; !ok {
+			d.add(Change{
+				Type:        ChangeTypeAdded,
+				Category:    CategorySchema,
+				Location:    jsonpointer.PointerSubEscapeAll("#/components/schemas/%s", name),
+				Description: "schema added",
+				New:         name})
+		}
+	}
+}
+
+// maxSchemaDepth limits recursion into inline schemas.
+const maxSchemaDepth = 16
+
+// diffSchema compares two schemas. Referenced schemas are compared by their
+// `$ref` value only since component schemas are compared separately. `dir`
+// determines which changes are breaking: removing a property breaks clients
+// reading responses while requiring a property breaks clients sending
+// requests. `allOf`, `oneOf`, `anyOf` and `additionalProperties` are
+// compared with the same rule: narrowing breaks requests and widening
+// breaks responses.
+func (d *differ) diffSchema(ptr string, dir direction, sOld, sNew *oas3.SchemaRef, depth int) {
+	if sOld == nil || sNew == nil || depth > maxSchemaDepth {
+		return
+	}
+	if sOld.Ref != "" || sNew.Ref != "" {
+		if sOld.Ref != sNew.Ref {
+			d.add(Change{
+				Type:        ChangeTypeChanged,
+				Category:    CategorySchema,
+				Location:    ptr,
+				Breaking:    true,
+				Description: "schema reference changed",
+				Old:         sOld.Ref,
+				New:         sNew.Ref})
+		}
+		return
+	}
+	vOld := sOld.Value
+	vNew := sNew.Value
+	if vOld == nil || vNew == nil {
+		return
+	}
+	typeOld := openapi3.TypesRefString(vOld.Type)
+	typeNew := openapi3.TypesRefString(vNew.Type)
+	if typeOld != typeNew {
+		d.add(Change{
+			Type:        ChangeTypeChanged,
+			Category:    CategorySchema,
+			Location:    ptr + "/type",
+			Breaking:    true,
+			Description: "type changed",
+			Old:         typeOld,
+			New:         typeNew})
+	}
+	if vOld.Format != vNew.Format {
+		d.add(Change{
+			Type:        ChangeTypeChanged,
+			Category:    CategorySchema,
+			Location:    ptr + "/format",
+			Breaking:    true,
+			Description: "format changed",
+			Old:         vOld.Format,
+			New:         vNew.Format})
+	}
+	d.diffEnum(ptr+"/enum", dir, vOld.Enum, vNew.Enum)
+
+	for _, propName := range maputil.Keys(vOld.Properties) {
+		propPtr := ptr + "/properties/" + jsonpointer.PropertyNameEscape(propName)
+		propNew, ok := vNew.Properties[propName]
+		if !ok {
+			d.add(Change{
+				Type:        ChangeTypeRemoved,
+				Category:    CategoryProperty,
+				Location:    propPtr,
+				Breaking:    dir.response(),
+				Description: "property removed",
+				Old:         propName})
+			continue
+		}
+		if !slices.Contains(vOld.Required, propName) && slices.Contains(vNew.Required, propName) {
+			d.add(Change{
+				Type:        ChangeTypeChanged,
+				Category:    CategoryProperty,
+				Location:    propPtr,
+				Breaking:    dir.request(),
+				Description: "property became required",
+				Old:         propName})
+		}
+		d.diffSchema(propPtr, dir, vOld.Properties[propName], propNew, depth+1)
+	}
+	for _, propName := range maputil.Keys(vNew.Properties) {
+		if _, ok := vOld.Properties[propName]; ok {
+			continue
+		}
+		required := slices.Contains(vNew.Required, propName)
+		desc := "optional property added"
+		if required {
+			desc = "required property added"
+		}
+		d.add(Change{
+			Type:        ChangeTypeAdded,
+			Category:    CategoryProperty,
+			Location:    ptr + "/properties/" + jsonpointer.PropertyNameEscape(propName),
+			Breaking:    required && dir.request(),
+			Description: desc,
+			New:         propName})
+	}
+	// Names required without a property at this level, such as in an
+	// `allOf` member adding `required` to a referenced schema.
+	for _, propName := range vNew.Required {
+		if _, ok := vNew.Properties[propName]; ok || slices.Contains(vOld.Required, propName) {
+			continue
+		}
+		d.add(Change{
+			Type:        ChangeTypeChanged,
+			Category:    CategoryProperty,
+			Location:    ptr + "/required",
+			Breaking:    dir.request(),
+			Description: "property became required",
+			New:         propName})
+	}
+	d.diffSchema(ptr+"/items", dir, vOld.Items, vNew.Items, depth+1)
+	d.diffSchemaRefs(ptr+"/allOf", dir, "allOf", vOld.AllOf, vNew.AllOf, depth+1)
+	d.diffSchemaRefs(ptr+"/oneOf", dir, "oneOf", vOld.OneOf, vNew.OneOf, depth+1)
+	d.diffSchemaRefs(ptr+"/anyOf", dir, "anyOf", vOld.AnyOf, vNew.AnyOf, depth+1)
+	d.diffAdditionalProperties(ptr+"/additionalProperties", dir, vOld.AdditionalProperties, vNew.AdditionalProperties, depth+1)
+}
+
+// diffSchemaRefs compares the schemas of a composition keyword. Referenced
+// schemas are matched by `$ref` and inline schemas by their order among
+// inline schemas. Adding an `allOf` schema narrows the schema while adding a
+// `oneOf` or `anyOf` schema widens it.
+func (d *differ) diffSchemaRefs(ptr string, dir direction, keyword string, sOld, sNew oas3.SchemaRefs, depth int) {
+	matched := map[int]int{}
+	usedNew := map[int]bool{}
+	for i, schOld := range sOld {
+		inlineIdx := schemaRefsInlineIndex(sOld, i)
+		for j, schNew := range sNew {
+			if usedNew[j] || schOld == nil || schNew == nil {
+				continue
+			}
+			if (schOld.Ref != "" && schOld.Ref == schNew.Ref) ||
+				(schOld.Ref == "" && schNew.Ref == "" && schemaRefsInlineIndex(sNew, j) == inlineIdx) {
+				matched[i] = j
+				usedNew[j] = true
+				break
+			}
+		}
+	}
+	narrowing := keyword == "allOf"
+	for i, schOld := range sOld {
+		if j, ok := matched[i]; ok {
+			d.diffSchema(ptr+"/"+strconv.Itoa(j), dir, schOld, sNew[j], depth)
+			continue
+		}
+		d.add(Change{
+			Type:        ChangeTypeRemoved,
+			Category:    CategorySchema,
+			Location:    ptr + "/" + strconv.Itoa(i),
+			Breaking:    (narrowing && dir.response()) || (!narrowing && dir.request()),
+			Description: keyword + " schema removed",
+			Old:         schemaRefString(schOld)})
+	}
+	for j, schNew := range sNew {
+		if usedNew[j] {
+			continue
+		}
+		d.add(Change{
+			Type:        ChangeTypeAdded,
+			Category:    CategorySchema,
+			Location:    ptr + "/" + strconv.Itoa(j),
+			Breaking:    (narrowing && dir.request()) || (!narrowing && dir.response()),
+			Description: keyword + " schema added",
+			New:         schemaRefString(schNew)})
+	}
+}
+
+// schemaRefsInlineIndex returns the index of `schRefs[i]` among the inline
+// schemas in `schRefs`, or -1 if it is a reference.
+func schemaRefsInlineIndex(schRefs oas3.SchemaRefs, i int) int {
+	if schRefs[i] == nil || schRefs[i].Ref != "" {
+		return -1
+	}
+	idx := 0
+	for _, schRef := range schRefs[:i] {
+		if schRef != nil && schRef.Ref == "" {
+			idx++
+		}
+	}
+	return idx
+}
+
+// schemaRefString returns the `$ref` of a schema or `inline schema`.
+func schemaRefString(schRef *oas3.SchemaRef) string {
+	if schRef == nil {
+		return ""
+	} else if schRef.Ref != "" {
+		return schRef.Ref
+	}
+	return "inline schema"
+}
+
+// diffAdditionalProperties compares `additionalProperties`. Disallowing or
+// constraining additional properties breaks clients sending requests while
+// allowing any additional properties breaks clients reading responses.
+func (d *differ) diffAdditionalProperties(ptr string, dir direction, apOld, apNew oas3.AdditionalProperties, depth int) {
+	stateOld := additionalPropertiesState(apOld)
+	stateNew := additionalPropertiesState(apNew)
+	if stateOld == "schema" && stateNew == "schema" {
+		d.diffSchema(ptr, dir, apOld.Schema, apNew.Schema, depth)
+		return
+	} else if stateOld == stateNew {
+		return
+	}
+	// `true` allows the most, then a schema, then `false`.
+	narrowing := stateOld == "true" || stateNew == "false"
+	d.add(Change{
+		Type:        ChangeTypeChanged,
+		Category:    CategorySchema,
+		Location:    ptr,
+		Breaking:    (narrowing && dir.request()) || (!narrowing && dir.response()),
+		Description: "additionalProperties changed",
+		Old:         stateOld,
+		New:         stateNew})
+}
+
+// additionalPropertiesState returns `true`, `false` or `schema`. An unset
+// value allows additional properties.
+func additionalPropertiesState(ap oas3.AdditionalProperties) string {
+	if ap.Schema != nil {
+		return "schema"
+	} else if ap.Has != nil && !*ap.Has {
+		return "false"
+	}
+	return "true"
+}
+
+// diffEnum compares enum values. Narrowing an enum breaks clients sending
+// requests and widening an enum breaks clients reading responses.
+func (d *differ) diffEnum(ptr string, dir direction, enumOld, enumNew []any) {
+	if len(enumNew) == 0 {
+		if len(enumOld) > 0 {
+			d.add(Change{
+				Type:        ChangeTypeRemoved,
+				Category:    CategorySchema,
+				Location:    ptr,
+				Breaking:    dir.response(),
+				Description: "enum constraint removed"})
+		}
+		return
+	} else if len(enumOld) == 0 {
+		d.add(Change{
+			Type:        ChangeTypeAdded,
+			Category:    CategorySchema,
+			Location:    ptr,
+			Breaking:    dir.request(),
+			Description: "enum constraint added"})
+		return
+	}
+	sOld := enumStrings(enumOld)
+	sNew := enumStrings(enumNew)
+	for _, v := range sOld {
+		if !slices.Contains(sNew, v) {
+			d.add(Change{
+				Type:        ChangeTypeRemoved,
+				Category:    CategorySchema,
+				Location:    ptr,
+				Breaking:    dir.request(),
+				Description: "enum value removed",
+				Old:         v})
+		}
+	}
+	for _, v := range sNew {
+		if !slices.Contains(sOld, v) {
+			d.add(Change{
+				Type:        ChangeTypeAdded,
+				Category:    CategorySchema,
+				Location:    ptr,
+				Breaking:    dir.response(),
+				Description: "enum value added",
+				New:         v})
+		}
+	}
+}
+
+func enumStrings(enum []any) []string {
+	var s []string
+	for _, v := range enum {
+		s = append(s, fmt.Sprintf("%v", v))
+	}
+	return s
+}
@@ -0,0 +1,274 @@
+package openapi3diff
+
+import (
+	"testing"
+
+	"github.com/grokify/spectrum/openapi3"
+)
+
+const specOldJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},
+"paths":{
+  "/users/{userId}":{"get":{"operationId":"getUser",
+    "parameters":[{"name":"userId","in":"path","required":true,"schema":{"type":"string"}},
+      {"name":"fields","in":"query","schema":{"type":"string"}}],
+    "responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/User"}}}},
+      "404":{"description":"Not Found"}}}},
+  "/users":{"get":{"operationId":"listUsers","responses":{"200":{"description":"OK"}}}}},
+"components":{"schemas":{"User":{"type":"object","properties":{"id":{"type":"string"},"name":{"type":"string"}}}}}}`
+
+const specNewJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"2.0.0"},
+"paths":{
+  "/users/{userId}":{"get":{"operationId":"getUser",
+    "parameters":[{"name":"userId","in":"path","required":true,"schema":{"type":"string"}},
+      {"name":"fields","in":"query","required":true,"schema":{"type":"string"}}],
+    "responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/User"}}}}}}},
+  "/accounts":{"get":{"operationId":"listAccounts","responses":{"200":{"description":"OK"}}}}},
+"components":{"schemas":{"User":{"type":"object","properties":{"id":{"type":"integer"},"email":{"type":"string"}}}}}}`
+
+var diffTests = []struct {
+	location    string
+	changeType  string
+	breaking    bool
+	description string
+}{
+	{"#/paths/~1users/get", ChangeTypeRemoved, true, "operation removed"},
+	{"#/paths/~1accounts/get", ChangeTypeAdded, false, "operation added"},
+	{"#/paths/~1users~1{userId}/get/parameters/1/required", ChangeTypeChanged, true, "parameter became required"},
+	{"#/paths/~1users~1{userId}/get/responses/404", ChangeTypeRemoved, true, "response code removed"},
+	{"#/components/schemas/User/properties/id/type", ChangeTypeChanged, true, "type changed"},
+	{"#/components/schemas/User/properties/name", ChangeTypeRemoved, true, "property removed"},
+	{"#/components/schemas/User/properties/email", ChangeTypeAdded, false, "optional property added"},
+}
+
+// TestDiff ensures `Diff()` finds and classifies changes.
+func TestDiff(t *testing.T) {
+	specOld, err := openapi3.Parse([]byte(specOldJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	specNew, err := openapi3.Parse([]byte(specNewJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	report := Diff(specOld, specNew)
+	if len(report.Changes) != len(diffTests) {
+		t.Errorf("openapi3diff.Diff() Count Mismatch: want [%d], got [%d]",
+			len(diffTests), len(report.Changes))
+	}
+	for _, tt := range diffTests {
+		found := false
+		for _, chg := range report.Changes {
+			if chg.Location == tt.location && chg.Description == tt.description {
+				found = true
+				if chg.Type != tt.changeType || chg.Breaking != tt.breaking {
+					t.Errorf("openapi3diff.Diff() Mismatch at [%s]: want [%s, %v], got [%s, %v]",
+						tt.location, tt.changeType, tt.breaking, chg.Type, chg.Breaking)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("openapi3diff.Diff() Missing change: want [%s] at [%s]",
+				tt.description, tt.location)
+		}
+	}
+	if !report.HasBreaking() {
+		t.Errorf("Report.HasBreaking() Mismatch: want [true], got [false]")
+	}
+}
+
+const specDirOldJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},
+"paths":{
+  "/users/{userId}":{
+    "parameters":[{"name":"userId","in":"path","required":true,"schema":{"type":"string"}},
+      {"name":"verbose","in":"query","schema":{"type":"boolean"}}],
+    "get":{"operationId":"getUser",
+      "responses":{"200":{"description":"OK",
+        "headers":{"X-Rate-Limit":{"schema":{"type":"integer"}},"X-Request-Id":{"schema":{"type":"string"}}},
+        "content":{"application/json":{"schema":{"$ref":"#/components/schemas/User"}}}}}}},
+  "/users":{"post":{"operationId":"createUser",
+    "requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/NewUser"}}}},
+    "responses":{"201":{"description":"Created"}}}}},
+"components":{"schemas":{
+  "User":{"type":"object","properties":{"id":{"type":"string"},"nickname":{"type":"string"},
+    "status":{"type":"string","enum":["active","disabled"]}}},
+  "NewUser":{"type":"object","properties":{"name":{"type":"string"},"legacy":{"type":"string"},
+    "role":{"type":"string","enum":["admin","member"]}}}}}}`
+
+const specDirNewJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"2.0.0"},
+"paths":{
+  "/users/{userId}":{
+    "parameters":[{"name":"userId","in":"path","required":true,"schema":{"type":"string"}},
+      {"name":"verbose","in":"query","required":true,"schema":{"type":"boolean"}}],
+    "get":{"operationId":"getUser",
+      "responses":{"200":{"description":"OK",
+        "headers":{"X-Rate-Limit":{"schema":{"type":"integer"}},"X-Trace-Id":{"schema":{"type":"string"}}},
+        "content":{"application/json":{"schema":{"$ref":"#/components/schemas/User"}}}}}}},
+  "/users":{"post":{"operationId":"createUser",
+    "requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/NewUser"}}}},
+    "responses":{"201":{"description":"Created"}}}}},
+"components":{"schemas":{
+  "User":{"type":"object","required":["email"],"properties":{"id":{"type":"string"},"email":{"type":"string"},
+    "status":{"type":"string","enum":["active","pending"]}}},
+  "NewUser":{"type":"object","required":["name","tenant"],"properties":{"name":{"type":"string"},"tenant":{"type":"string"},
+    "role":{"type":"string","enum":["admin","guest"]}}}}}}`
+
+var diffDirectionTests = []struct {
+	location    string
+	breaking    bool
+	description string
+}{
+	{"#/paths/~1users~1{userId}/parameters/1/required", true, "parameter became required"},
+	{"#/paths/~1users~1{userId}/get/responses/200/headers/X-Request-Id", true, "response header removed"},
+	{"#/paths/~1users~1{userId}/get/responses/200/headers/X-Trace-Id", false, "response header added"},
+	{"#/components/schemas/User/properties/nickname", true, "property removed"},
+	{"#/components/schemas/User/properties/email", false, "required property added"},
+	{"#/components/schemas/User/properties/status/enum", false, "enum value removed"},
+	{"#/components/schemas/User/properties/status/enum", true, "enum value added"},
+	{"#/components/schemas/NewUser/properties/legacy", false, "property removed"},
+	{"#/components/schemas/NewUser/properties/tenant", true, "required property added"},
+	{"#/components/schemas/NewUser/properties/name", true, "property became required"},
+	{"#/components/schemas/NewUser/properties/role/enum", true, "enum value removed"},
+	{"#/components/schemas/NewUser/properties/role/enum", false, "enum value added"},
+}
+
+// TestDiffDirection ensures schema changes are classified by whether the
+// schema is used in requests or responses, and that path item parameters and
+// response headers are compared.
+func TestDiffDirection(t *testing.T) {
+	specOld, err := openapi3.Parse([]byte(specDirOldJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	specNew, err := openapi3.Parse([]byte(specDirNewJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	report := Diff(specOld, specNew)
+	if len(report.Changes) != len(diffDirectionTests) {
+		t.Errorf("openapi3diff.Diff() Count Mismatch: want [%d], got [%d]",
+			len(diffDirectionTests), len(report.Changes))
+	}
+	for _, tt := range diffDirectionTests {
+		found := false
+		for _, chg := range report.Changes {
+			if chg.Location == tt.location && chg.Description == tt.description {
+				found = true
+				if chg.Breaking != tt.breaking {
+					t.Errorf("openapi3diff.Diff() Mismatch at [%s] [%s]: want breaking [%v], got [%v]",
+						tt.location, tt.description, tt.breaking, chg.Breaking)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("openapi3diff.Diff() Missing change: want [%s] at [%s]",
+				tt.description, tt.location)
+		}
+	}
+}
+
+const specCompOldJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},
+"paths":{
+  "/pets/{petId}":{"get":{"operationId":"getPet",
+    "parameters":[{"name":"petId","in":"path","required":true,"schema":{"type":"string"}}],
+    "responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}}}}},
+  "/pets":{"post":{"operationId":"createPet",
+    "requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/NewPet"}}}},
+    "responses":{"201":{"description":"Created"}}}}},
+"components":{"schemas":{
+  "Audit":{"type":"object","properties":{"created":{"type":"string"}}},
+  "Base":{"type":"object","properties":{"id":{"type":"string"}}},
+  "Org":{"type":"object"},
+  "Person":{"type":"object"},
+  "Pet":{"additionalProperties":false,"allOf":[{"$ref":"#/components/schemas/Base"},
+    {"type":"object","properties":{
+      "owner":{"oneOf":[{"$ref":"#/components/schemas/Person"}]},
+      "contact":{"anyOf":[{"$ref":"#/components/schemas/Person"},{"$ref":"#/components/schemas/Org"}]}}}]},
+  "NewPet":{"allOf":[{"$ref":"#/components/schemas/Audit"},{"$ref":"#/components/schemas/Base"},
+    {"type":"object","properties":{
+      "owner":{"oneOf":[{"$ref":"#/components/schemas/Person"},{"$ref":"#/components/schemas/Org"}]},
+      "contact":{"anyOf":[{"$ref":"#/components/schemas/Person"}]},
+      "labels":{"type":"object","additionalProperties":{"type":"string"}}}}]}}}}`
+
+const specCompNewJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"2.0.0"},
+"paths":{
+  "/pets/{petId}":{"get":{"operationId":"getPet",
+    "parameters":[{"name":"petId","in":"path","required":true,"schema":{"type":"string"}}],
+    "responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}}}}},
+  "/pets":{"post":{"operationId":"createPet",
+    "requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/NewPet"}}}},
+    "responses":{"201":{"description":"Created"}}}}},
+"components":{"schemas":{
+  "Audit":{"type":"object","properties":{"created":{"type":"string"}}},
+  "Base":{"type":"object","properties":{"id":{"type":"string"}}},
+  "Org":{"type":"object"},
+  "Person":{"type":"object"},
+  "Pet":{"allOf":[{"$ref":"#/components/schemas/Audit"},{"$ref":"#/components/schemas/Base"},
+    {"type":"object","required":["id"],"properties":{
+      "owner":{"oneOf":[{"$ref":"#/components/schemas/Person"},{"$ref":"#/components/schemas/Org"}]},
+      "contact":{"anyOf":[{"$ref":"#/components/schemas/Person"}]}}}]},
+  "NewPet":{"additionalProperties":false,"allOf":[{"$ref":"#/components/schemas/Base"},
+    {"type":"object","required":["id"],"properties":{
+      "owner":{"oneOf":[{"$ref":"#/components/schemas/Person"}]},
+      "contact":{"anyOf":[{"$ref":"#/components/schemas/Person"},{"$ref":"#/components/schemas/Org"}]},
+      "labels":{"type":"object","additionalProperties":{"type":"integer"}}}}]}}}}`
+
+var diffCompositionTests = []struct {
+	location    string
+	breaking    bool
+	description string
+}{
+	// `Pet` is returned in responses, so widening is breaking.
+	{"#/components/schemas/Pet/allOf/0", false, "allOf schema added"},
+	{"#/components/schemas/Pet/allOf/2/required", false, "property became required"},
+	{"#/components/schemas/Pet/allOf/2/properties/owner/oneOf/1", true, "oneOf schema added"},
+	{"#/components/schemas/Pet/allOf/2/properties/contact/anyOf/1", false, "anyOf schema removed"},
+	{"#/components/schemas/Pet/additionalProperties", true, "additionalProperties changed"},
+	// `NewPet` is sent in requests, so narrowing is breaking.
+	{"#/components/schemas/NewPet/allOf/0", false, "allOf schema removed"},
+	{"#/components/schemas/NewPet/allOf/1/required", true, "property became required"},
+	{"#/components/schemas/NewPet/allOf/1/properties/owner/oneOf/1", true, "oneOf schema removed"},
+	{"#/components/schemas/NewPet/allOf/1/properties/contact/anyOf/1", false, "anyOf schema added"},
+	{"#/components/schemas/NewPet/allOf/1/properties/labels/additionalProperties/type", true, "type changed"},
+	{"#/components/schemas/NewPet/additionalProperties", true, "additionalProperties changed"},
+}
+
+// TestDiffComposition ensures `allOf`, `oneOf`, `anyOf`, `additionalProperties`
+// and `required` in composed schemas are compared by direction, and that
+// composed schemas are matched by `$ref` rather than position.
+func TestDiffComposition(t *testing.T) {
+	specOld, err := openapi3.Parse([]byte(specCompOldJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	specNew, err := openapi3.Parse([]byte(specCompNewJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	report := Diff(specOld, specNew)
+	if len(report.Changes) != len(diffCompositionTests) {
+		t.Errorf("openapi3diff.Diff() Count Mismatch: want [%d], got [%d]",
+			len(diffCompositionTests), len(report.Changes))
+	}
+	for _, tt := range diffCompositionTests {
+		found := false
+		for _, chg := range report.Changes {
+			if chg.Location == tt.location && chg.Description == tt.description {
+				found = true
+				if chg.Breaking != tt.breaking {
+					t.Errorf("openapi3diff.Diff() Mismatch at [%s] [%s]: want breaking [%v], got [%v]",
+						tt.location, tt.description, tt.breaking, chg.Breaking)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("openapi3diff.Diff() Missing change: want [%s] at [%s]",
+				tt.description, tt.location)
+		}
+	}
+}
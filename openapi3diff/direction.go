@@ -0,0 +1,175 @@
+package openapi3diff
+
+import (
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/spectrum/openapi3"
+)
+
+// direction indicates whether a schema is sent in requests, returned in
+// responses or both, which determines whether a change is breaking.
+type direction int
+
+const (
+	directionRequest direction = 1 << iota
+	directionResponse
+	directionBoth = directionRequest | directionResponse
+)
+
+func (dir direction) request() bool  { return dir&directionRequest != 0 }
+func (dir direction) response() bool { return dir&directionResponse != 0 }
+
+const (
+	ptrComponentSchemas       = "#/components/schemas/"
+	ptrComponentParameters    = "#/components/parameters/"
+	ptrComponentRequestBodies = "#/components/requestBodies/"
+	ptrComponentResponses     = "#/components/responses/"
+	ptrComponentHeaders       = "#/components/headers/"
+)
+
+// schemaDirections returns the directions each component schema is used in,
+// following `$ref`s from operation parameters, request bodies, responses and
+// response headers.
+func schemaDirections(spec *openapi3.Spec) map[string]direction {
+	su := schemaUsage{spec: spec, dirs: map[string]direction{}}
+	if spec.Paths == nil {
+		return su.dirs
+	}
+	for _, pathItem := range spec.Paths.Map() {
+		if pathItem == nil {
+			continue
+		}
+		su.parameters(pathItem.Parameters)
+	}
+	openapi3.VisitOperations(spec, func(path, method string, op *oas3.Operation) {
+		if op == nil {
+			return
+		}
+		su.parameters(op.Parameters)
+		if rb := su.requestBody(op.RequestBody); rb != nil {
+			su.content(rb.Content, directionRequest)
+		}
+		if op.Responses == nil {
+			return
+		}
+		for _, respRef := range op.Responses.Map() {
+			if resp := su.response(respRef); resp != nil {
+				su.content(resp.Content, directionResponse)
+				for _, hdrRef := range resp.Headers {
+					if hdr := su.header(hdrRef); hdr != nil {
+						su.schema(hdr.Schema, directionResponse, 0)
+					}
+				}
+			}
+		}
+	})
+	return su.dirs
+}
+
+type schemaUsage struct {
+	spec *openapi3.Spec
+	dirs map[string]direction
+}
+
+func (su *schemaUsage) components() *oas3.Components {
+	if su.spec.Components == nil {
+		return &oas3.Components{}
+	}
+	return su.spec.Components
+}
+
+func (su *schemaUsage) parameters(params oas3.Parameters) {
+	for _, paramRef := range params {
+		if paramRef == nil {
+			continue
+		}
+		param := paramRef.Value
+		if param == nil && strings.HasPrefix(paramRef.Ref, ptrComponentParameters) {
+			if compRef, ok := su.components().Parameters[strings.TrimPrefix(paramRef.Ref, ptrComponentParameters)]; ok && compRef != nil {
+				param = compRef.Value
+			}
+		}
+		if param == nil {
+			continue
+		}
+		su.schema(param.Schema, directionRequest, 0)
+		su.content(param.Content, directionRequest)
+	}
+}
+
+func (su *schemaUsage) requestBody(rbRef *oas3.RequestBodyRef) *oas3.RequestBody {
+	if rbRef == nil {
+		return nil
+	} else if rbRef.Value != nil || !strings.HasPrefix(rbRef.Ref, ptrComponentRequestBodies) {
+		return rbRef.Value
+	}
+	if compRef, ok := su.components().RequestBodies[strings.TrimPrefix(rbRef.Ref, ptrComponentRequestBodies)]; ok && compRef != nil {
+		return compRef.Value
+	}
+	return nil
+}
+
+func (su *schemaUsage) response(respRef *oas3.ResponseRef) *oas3.Response {
+	if respRef == nil {
+		return nil
+	} else if respRef.Value != nil || !strings.HasPrefix(respRef.Ref, ptrComponentResponses) {
+		return respRef.Value
+	}
+	if compRef, ok := su.components().Responses[strings.TrimPrefix(respRef.Ref, ptrComponentResponses)]; ok && compRef != nil {
+		return compRef.Value
+	}
+	return nil
+}
+
+func (su *schemaUsage) header(hdrRef *oas3.HeaderRef) *oas3.Header {
+	if hdrRef == nil {
+		return nil
+	} else if hdrRef.Value != nil || !strings.HasPrefix(hdrRef.Ref, ptrComponentHeaders) {
+		return hdrRef.Value
+	}
+	if compRef, ok := su.components().Headers[strings.TrimPrefix(hdrRef.Ref, ptrComponentHeaders)]; ok && compRef != nil {
+		return compRef.Value
+	}
+	return nil
+}
+
+func (su *schemaUsage) content(content oas3.Content, dir direction) {
+	for _, mt := range content {
+		if mt != nil {
+			su.schema(mt.Schema, dir, 0)
+		}
+	}
+}
+
+// schema marks component schemas reachable from `schRef` as used in `dir`.
+func (su *schemaUsage) schema(schRef *oas3.SchemaRef, dir direction, depth int) {
+	if schRef == nil || depth > maxSchemaDepth {
+		return
+	}
+	sch := schRef.Value
+	if strings.HasPrefix(schRef.Ref, ptrComponentSchemas) {
+		name := strings.TrimPrefix(schRef.Ref, ptrComponentSchemas)
+		if su.dirs[name]&dir == dir {
+			return
+		}
+		su.dirs[name] |= dir
+		if compRef, ok := su.components().Schemas[name]; ok && compRef != nil {
+			sch = compRef.Value
+		}
+	}
+	if sch == nil {
+		return
+	}
+	for _, propRef := range sch.Properties {
+		su.schema(propRef, dir, depth+1)
+	}
+	for _, schRefs := range []oas3.SchemaRefs{sch.AllOf, sch.AnyOf, sch.OneOf} {
+		for _, subRef := range schRefs {
+			su.schema(subRef, dir, depth+1)
+		}
+	}
+	su.schema(sch.Items, dir, depth+1)
+	su.schema(sch.Not, dir, depth+1)
+	su.schema(sch.AdditionalProperties.Schema, dir, depth+1)
+}
@@ -0,0 +1,140 @@
+package openapi3diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// Report is the result of `Diff()`.
+type Report struct {
+	Changes []Change `json:"changes"`
+}
+
+func NewReport() *Report {
+	return &Report{Changes: []Change{}}
+}
+
+// Sort orders changes by breaking first and then by location.
+func (r *Report) Sort() {
+	sort.SliceStable(r.Changes, func(i, j int) bool {
+		if r.Changes[i].Breaking != r.Changes[j].Breaking {
+			return r.Changes[i].Breaking
+		}
+		if r.Changes[i].Location != r.Changes[j].Location {
+			return r.Changes[i].Location < r.Changes[j].Location
+		}
+		return r.Changes[i].Description < r.Changes[j].Description
+	})
+}
+
+func (r *Report) BreakingChanges() []Change {
+	var chgs []Change
+	for _, chg := range r.Changes {
+		if chg.Breaking {
+			chgs = append(chgs, chg)
+		}
+	}
+	return chgs
+}
+
+func (r *Report) HasBreaking() bool {
+	for _, chg := range r.Changes {
+		if chg.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// CountsByType returns change counts keyed by change type.
+func (r *Report) CountsByType() map[string]int {
+	counts := map[string]int{}
+	for _, chg := range r.Changes {
+		counts[chg.Type]++
+	}
+	return counts
+}
+
+// Write writes the report using `FormatText`, `FormatJSON` or `FormatMarkdown`.
+// An empty format defaults to `FormatText`.
+func (r *Report) Write(w io.Writer, format string) error {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatText, "":
+		return r.WriteText(w)
+	case FormatJSON:
+		return r.WriteJSON(w, "", "  ")
+	case FormatMarkdown, "md":
+		return r.WriteMarkdown(w)
+	default:
+		return fmt.Errorf("unknown format [%s]", format)
+	}
+}
+
+func (r *Report) WriteJSON(w io.Writer, prefix, indent string) error {
+	bytes, err := json.MarshalIndent(r, prefix, indent)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(bytes, '\n'))
+	return err
+}
+
+func (r *Report) WriteText(w io.Writer) error {
+	for _, chg := range r.Changes {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s%s\n",
+			breakingLabel(chg.Breaking), chg.Type, chg.Location, chg.Description, valuesText(chg)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "changes: %d, breaking: %d\n", len(r.Changes), len(r.BreakingChanges()))
+	return err
+}
+
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	lines := []string{
+		"# OpenAPI Diff",
+		"",
+		fmt.Sprintf("%d changes, %d breaking.", len(r.Changes), len(r.BreakingChanges())),
+		"",
+		"| Breaking | Type | Category | Location | Description |",
+		"|----------|------|----------|----------|-------------|"}
+	for _, chg := range r.Changes {
+		lines = append(lines, fmt.Sprintf("| %s | %s | %s | `%s` | %s%s |",
+			breakingLabel(chg.Breaking), chg.Type, chg.Category,
+			chg.Location, markdownEscape(chg.Description), markdownEscape(valuesText(chg))))
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func breakingLabel(breaking bool) string {
+	if breaking {
+		return "BREAKING"
+	}
+	return "non-breaking"
+}
+
+func valuesText(chg Change) string {
+	switch {
+	case chg.Old != "" && chg.New != "":
+		return fmt.Sprintf(" [%s => %s]", chg.Old, chg.New)
+	case chg.Old != "":
+		return fmt.Sprintf(" [%s]", chg.Old)
+	case chg.New != "":
+		return fmt.Sprintf(" [%s]", chg.New)
+	}
+	return ""
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,52 @@
+package openapi3edit
+
+import (
+	"strings"
+
+	"github.com/grokify/spectrum/openapi3"
+)
+
+// ComponentsPrune removes components that cannot be reached from paths,
+// webhooks or top-level security requirements, including components only
+// used by other unreachable components. It returns the JSON pointers of the
+// removed components, or with `dryRun`, of the components that would be
+// removed without changing the spec.
+func (se *SpecEdit) ComponentsPrune(dryRun bool) ([]string, error) {
+	graph, err := se.SpecMore.ComponentGraph()
+	if err != nil {
+		return nil, err
+	}
+	unreachable := graph.Unreachable()
+	if dryRun || se.SpecMore.Spec.Components == nil {
+		return unreachable, nil
+	}
+	comps := se.SpecMore.Spec.Components
+	for _, ptr := range unreachable {
+		parts := strings.Split(strings.TrimPrefix(ptr, "#/"+openapi3.PathComponents+"/"), "/")
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.ReplaceAll(strings.ReplaceAll(parts[1], "~1", "/"), "~0", "~")
+		switch parts[0] {
+		case "schemas":
+			delete(comps.Schemas, name)
+		case "parameters":
+			delete(comps.Parameters, name)
+		case "responses":
+			delete(comps.Responses, name)
+		case "requestBodies":
+			delete(comps.RequestBodies, name)
+		case "headers":
+			delete(comps.Headers, name)
+		case "examples":
+			delete(comps.Examples, name)
+		case "securitySchemes":
+			delete(comps.SecuritySchemes, name)
+		case "links":
+			delete(comps.Links, name)
+		case "callbacks":
+			delete(comps.Callbacks, name)
+		}
+	}
+	return unreachable, nil
+}
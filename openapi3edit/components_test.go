@@ -0,0 +1,68 @@
+package openapi3edit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/spectrum/openapi3"
+)
+
+const componentsPruneSpec = `{
+"openapi":"3.0.3","info":{"title":"Pets","version":"1.0.0"},
+"security":[{"bearer":[]}],
+"paths":{
+  "/pets":{"get":{
+    "parameters":[{"$ref":"#/components/parameters/Limit"}],
+    "responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}}}}}},
+"components":{
+  "securitySchemes":{
+    "bearer":{"type":"http","scheme":"bearer"},
+    "apiKey":{"type":"apiKey","in":"header","name":"X-API-Key"}},
+  "parameters":{
+    "Limit":{"name":"limit","in":"query","schema":{"$ref":"#/components/schemas/Count"}},
+    "Offset":{"name":"offset","in":"query","schema":{"type":"integer"}}},
+  "schemas":{
+    "Count":{"type":"integer"},
+    "Pet":{"oneOf":[{"$ref":"#/components/schemas/Cat"}],"discriminator":{"propertyName":"kind","mapping":{"dog":"Dog"}}},
+    "Cat":{"type":"object","properties":{"owner":{"$ref":"#/components/schemas/Owner/properties/name"}}},
+    "Dog":{"type":"object"},
+    "Owner":{"type":"object","properties":{"name":{"type":"string"}}},
+    "Legacy":{"type":"object","properties":{"old":{"$ref":"#/components/schemas/LegacyItem"}}},
+    "LegacyItem":{"type":"object"}}}}`
+
+// TestComponentsPrune ensures unreachable components are removed
+// transitively and reported in dry runs.
+func TestComponentsPrune(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(componentsPruneSpec))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	want := "#/components/parameters/Offset,#/components/schemas/Legacy,#/components/schemas/LegacyItem,#/components/securitySchemes/apiKey"
+
+	se := NewSpecEdit(spec)
+	removed, err := se.ComponentsPrune(true)
+	if err != nil {
+		t.Fatalf("openapi3edit.SpecEdit.ComponentsPrune() Error [%s]", err.Error())
+	}
+	if got := strings.Join(removed, ","); got != want {
+		t.Errorf("openapi3edit.SpecEdit.ComponentsPrune(true) Mismatch: want [%s], got [%s]", want, got)
+	}
+	if len(spec.Components.Schemas) != 7 {
+		t.Errorf("openapi3edit.SpecEdit.ComponentsPrune(true) Mismatch: want [7] schemas, got [%d]", len(spec.Components.Schemas))
+	}
+
+	removed, err = se.ComponentsPrune(false)
+	if err != nil {
+		t.Fatalf("openapi3edit.SpecEdit.ComponentsPrune() Error [%s]", err.Error())
+	}
+	if got := strings.Join(removed, ","); got != want {
+		t.Errorf("openapi3edit.SpecEdit.ComponentsPrune(false) Mismatch: want [%s], got [%s]", want, got)
+	}
+	if len(spec.Components.Schemas) != 5 || len(spec.Components.Parameters) != 1 || len(spec.Components.SecuritySchemes) != 1 {
+		t.Errorf("openapi3edit.SpecEdit.ComponentsPrune(false) Mismatch: want [5] schemas, [1] parameter, [1] security scheme, got [%d], [%d], [%d]",
+			len(spec.Components.Schemas), len(spec.Components.Parameters), len(spec.Components.SecuritySchemes))
+	}
+	if removed, _ := se.ComponentsPrune(true); len(removed) != 0 {
+		t.Errorf("openapi3edit.SpecEdit.ComponentsPrune(true) Mismatch: want none after prune, got [%s]", strings.Join(removed, ","))
+	}
+}
@@ -25,6 +25,8 @@ func (se *SpecEdit) paramPathNamesModifyComponents(xf func(s string) string) err
 	_, _, err := transform.TransformMap(xf, namesStartSlice)
 	if err != nil {
 		return err
+	} else if spec.Components == nil {
+		return nil
 	}
 	for _, paramRef := range spec.Components.Parameters {
 		if paramRef == nil ||
@@ -111,7 +113,7 @@ func (se *SpecEdit) paramPathNamesModifyPaths(xf func(s string) string) error {
 			spec.Paths.Set(pathAfter, pathItem)
 			pathsMap[pathBefore] = pathAfter
 			// delete(spec.Paths, pathBefore) // getkin v0.121.0 to v0.122.0
-			spec.Paths.Set(pathBefore, nil)
+			spec.Paths.Delete(pathBefore)
 		}
 	}
 
@@ -0,0 +1,55 @@
+package openapi3edit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/mogo/text/stringcase"
+	"github.com/grokify/spectrum/openapi3"
+)
+
+var paramPathNamesModifyTests = []struct {
+	spec      string
+	wantPaths string
+}{
+	{`{"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},"paths":{
+  "/users/{user_id}":{"get":{
+    "parameters":[{"name":"user_id","in":"path","required":true,"schema":{"type":"string"}}],
+    "responses":{"200":{"description":"OK"}}}},
+  "/status":{"get":{"responses":{"200":{"description":"OK"}}}}}}`,
+		"/status,/users/{userId}"},
+	{`{"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},"paths":{
+  "/users/{user_id}":{"get":{
+    "parameters":[{"$ref":"#/components/parameters/UserId"}],
+    "responses":{"200":{"description":"OK"}}}}},
+"components":{"parameters":{"UserId":{"name":"user_id","in":"path","required":true,"schema":{"type":"string"}}}}}`,
+		"/users/{userId}"},
+}
+
+// TestParamPathNamesModify ensures renamed paths replace the original paths
+// and specs without components are supported.
+func TestParamPathNamesModify(t *testing.T) {
+	xf, err := stringcase.FuncToCase(stringcase.CamelCase)
+	if err != nil {
+		t.Fatalf("stringcase.FuncToCase() Error [%s]", err.Error())
+	}
+	for _, tt := range paramPathNamesModifyTests {
+		spec, err := openapi3.Parse([]byte(tt.spec))
+		if err != nil {
+			t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+		}
+		se := NewSpecEdit(spec)
+		if _, err := se.ParamPathNamesModify(xf); err != nil {
+			t.Fatalf("openapi3edit.SpecEdit.ParamPathNamesModify() Error [%s]", err.Error())
+		}
+		paths := Paths{Paths: spec.Paths}
+		if got := strings.Join(paths.PathKeys(), ","); got != tt.wantPaths {
+			t.Errorf("openapi3edit.SpecEdit.ParamPathNamesModify() Mismatch: want [%s], got [%s]", tt.wantPaths, got)
+		}
+		for _, pathItem := range spec.Paths.Map() {
+			if pathItem == nil {
+				t.Errorf("openapi3edit.SpecEdit.ParamPathNamesModify() Mismatch: want no nil path items")
+			}
+		}
+	}
+}
@@ -60,7 +60,8 @@ func (se *SpecEdit) SchemaRefsModifyRx(rx *regexp.Regexp, repl string) {
 	})
 }
 
-// SchemaRefsModify modifys schema reference JSON pointers. The xf function
+// SchemaRefsModify modifys schema reference JSON pointers, including refs in
+// path item parameters, callbacks and discriminator mappings. The xf function
 // must return the entire JSON pointer.
 func (se *SpecEdit) SchemaRefsModify(xf func(string) string) {
 	if se.SpecMore.Spec == nil || xf == nil {
@@ -80,39 +81,33 @@ func (se *SpecEdit) SchemaRefsModify(xf func(string) string) {
 		}
 	}
 
-	openapi3.VisitOperations(spec, func(opPath, opMethod string, op *oas3.Operation) {
-		if op == nil {
-			return
+	if spec.Paths != nil {
+		for _, pathItem := range spec.Paths.Map() {
+			pathItemModifyRefs(pathItem, xf)
 		}
-		// Operation Parameters
-		for _, paramRef := range op.Parameters {
-			paramRef.Ref = xf(paramRef.Ref)
-			if paramRef.Value != nil && paramRef.Value.Schema != nil {
-				SchemaRefModifyRefs(paramRef.Value.Schema, xf)
-			}
+	}
+	for _, callbackRef := range spec.Components.Callbacks {
+		callbackModifyRefs(callbackRef, xf)
+	}
+
+	for _, reqBodyRef := range spec.Components.RequestBodies {
+		if reqBodyRef == nil || reqBodyRef.Value == nil {
+			continue
 		}
-		// Operation Requests
-		if op.RequestBody != nil {
-			op.RequestBody.Ref = xf(op.RequestBody.Ref)
-			if op.RequestBody.Value != nil {
-				for _, mediaType := range op.RequestBody.Value.Content {
-					SchemaRefModifyRefs(mediaType.Schema, xf)
-				}
-			}
+		for _, mediaType := range reqBodyRef.Value.Content {
+			SchemaRefModifyRefs(mediaType.Schema, xf)
 		}
-		// Operation Responses
-		respsMap := op.Responses.Map()
-		for _, respRef := range respsMap {
-			// for _, respRef := range op.Responses { // getkin v0.121.0 to v0.122.0
-			respRef.Ref = xf(respRef.Ref)
-			if respRef.Value == nil {
-				continue
-			}
-			for _, mediaType := range respRef.Value.Content {
-				SchemaRefModifyRefs(mediaType.Schema, xf)
-			}
+	}
+	for _, respRef := range spec.Components.Responses {
+		if respRef != nil {
+			responseModifyRefs(respRef.Value, xf)
 		}
-	})
+	}
+	for _, headerRef := range spec.Components.Headers {
+		if headerRef != nil && headerRef.Value != nil {
+			SchemaRefModifyRefs(headerRef.Value.Schema, xf)
+		}
+	}
 
 	for _, schRef := range spec.Components.Schemas {
 		if schRef == nil {
@@ -151,6 +146,88 @@ func (se *SpecEdit) SchemaRefsModify(xf func(string) string) {
 	}
 }
 
+// pathItemModifyRefs modifies refs in path item parameters and operations.
+func pathItemModifyRefs(pathItem *oas3.PathItem, xf func(string) string) {
+	if pathItem == nil {
+		return
+	}
+	parametersModifyRefs(pathItem.Parameters, xf)
+	for _, op := range pathItem.Operations() {
+		operationModifyRefs(op, xf)
+	}
+}
+
+func operationModifyRefs(op *oas3.Operation, xf func(string) string) {
+	if op == nil {
+		return
+	}
+	// Operation Parameters
+	parametersModifyRefs(op.Parameters, xf)
+	// Operation Requests
+	if op.RequestBody != nil {
+		op.RequestBody.Ref = xf(op.RequestBody.Ref)
+		if op.RequestBody.Value != nil {
+			for _, mediaType := range op.RequestBody.Value.Content {
+				SchemaRefModifyRefs(mediaType.Schema, xf)
+			}
+		}
+	}
+	// Operation Responses
+	if op.Responses != nil {
+		for _, respRef := range op.Responses.Map() {
+			// for _, respRef := range op.Responses { // getkin v0.121.0 to v0.122.0
+			if respRef == nil {
+				continue
+			}
+			respRef.Ref = xf(respRef.Ref)
+			responseModifyRefs(respRef.Value, xf)
+		}
+	}
+	// Operation Callbacks
+	for _, callbackRef := range op.Callbacks {
+		callbackModifyRefs(callbackRef, xf)
+	}
+}
+
+func parametersModifyRefs(params oas3.Parameters, xf func(string) string) {
+	for _, paramRef := range params {
+		if paramRef == nil {
+			continue
+		}
+		paramRef.Ref = xf(paramRef.Ref)
+		if paramRef.Value != nil && paramRef.Value.Schema != nil {
+			SchemaRefModifyRefs(paramRef.Value.Schema, xf)
+		}
+	}
+}
+
+func callbackModifyRefs(callbackRef *oas3.CallbackRef, xf func(string) string) {
+	if callbackRef == nil {
+		return
+	}
+	callbackRef.Ref = xf(callbackRef.Ref)
+	if callbackRef.Value == nil {
+		return
+	}
+	for _, pathItem := range callbackRef.Value.Map() {
+		pathItemModifyRefs(pathItem, xf)
+	}
+}
+
+func responseModifyRefs(resp *oas3.Response, xf func(string) string) {
+	if resp == nil {
+		return
+	}
+	for _, mediaType := range resp.Content {
+		SchemaRefModifyRefs(mediaType.Schema, xf)
+	}
+	for _, headerRef := range resp.Headers {
+		if headerRef != nil && headerRef.Value != nil {
+			SchemaRefModifyRefs(headerRef.Value.Schema, xf)
+		}
+	}
+}
+
 // SchemaRefModifyRefsRx modifies Schema reference schema pointers that match
 // the supplied `*regexp.Regexp` with the replacement string. It was originally
 // designed to convert `#schemas/` to `#components/schemas/`.
@@ -160,12 +237,15 @@ func SchemaRefModifyRefsRx(schRef *oas3.SchemaRef, rx *regexp.Regexp, repl strin
 	})
 }
 
+// SchemaRefModifyRefs modifies `$ref` pointers in a schema and its
+// subschemas. Schemas behind a `$ref` are modified where they are defined,
+// so recursive schemas loaded with resolved refs are not walked endlessly.
 func SchemaRefModifyRefs(schRef *oas3.SchemaRef, xf func(string) string) {
 	if schRef == nil || xf == nil {
 		return
 	}
 	schRef.Ref = xf(schRef.Ref)
-	if schRef.Value == nil {
+	if schRef.Value == nil || len(schRef.Ref) > 0 {
 		return
 	}
 	for _, propSchemaRef := range schRef.Value.Properties {
@@ -180,15 +260,32 @@ func SchemaRefModifyRefs(schRef *oas3.SchemaRef, xf func(string) string) {
 	if schRef.Value.Not != nil {
 		SchemaRefModifyRefs(schRef.Value.Not, xf)
 	}
-	for _, allOf := range schRef.Value.OneOf {
+	for _, allOf := range schRef.Value.AllOf {
 		SchemaRefModifyRefs(allOf, xf)
 	}
-	for _, anyOf := range schRef.Value.OneOf {
+	for _, anyOf := range schRef.Value.AnyOf {
 		SchemaRefModifyRefs(anyOf, xf)
 	}
 	for _, oneOf := range schRef.Value.OneOf {
 		SchemaRefModifyRefs(oneOf, xf)
 	}
+	if schRef.Value.Discriminator != nil {
+		discriminatorModifyRefs(schRef.Value.Discriminator, xf)
+	}
+}
+
+// discriminatorModifyRefs modifies discriminator mapping values. Values
+// that are schema names rather than JSON pointers are modified as
+// `#/components/schemas/<name>` pointers and kept as names.
+func discriminatorModifyRefs(disc *oas3.Discriminator, xf func(string) string) {
+	for key, mappingRef := range disc.Mapping {
+		if strings.Contains(mappingRef.Ref, "/") {
+			mappingRef.Ref = xf(mappingRef.Ref)
+		} else if name, ok := strings.CutPrefix(xf(openapi3.PointerComponentsSchemas+"/"+mappingRef.Ref), openapi3.PointerComponentsSchemas+"/"); ok {
+			mappingRef.Ref = name
+		}
+		disc.Mapping[key] = mappingRef
+	}
 }
 
 func (se *SpecEdit) SchemaSetAdditionalPropertiesTrue(pointerBase string) []string {
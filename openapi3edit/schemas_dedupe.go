@@ -0,0 +1,192 @@
+package openapi3edit
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/grokify/spectrum/openapi3"
+)
+
+// SchemasDedupeOpts configures `SpecEdit.SchemasDedupe()`.
+type SchemasDedupeOpts struct {
+	// CanonicalNameFunc returns the name to keep for sorted names of
+	// equivalent schemas. It defaults to `SchemaCanonicalNameShortest`.
+	CanonicalNameFunc func(names []string) string
+}
+
+// SchemaDuplicates is a set of equivalent schema names and the name kept.
+type SchemaDuplicates struct {
+	Canonical string
+	Names     []string
+}
+
+// SchemasDedupeResult lists merged duplicates and near-duplicates that
+// differ only in descriptions or examples, which are not merged.
+type SchemasDedupeResult struct {
+	Duplicates     []SchemaDuplicates
+	NearDuplicates [][]string
+}
+
+// SchemaCanonicalNameShortest returns the shortest name, then the first
+// in sort order, e.g. `Error` for `ApiError`, `Error` and `ErrorResponse`.
+func SchemaCanonicalNameShortest(names []string) string {
+	canonical := ""
+	for _, name := range names {
+		if len(canonical) == 0 || len(name) < len(canonical) ||
+			(len(name) == len(canonical) && name < canonical) {
+			canonical = name
+		}
+	}
+	return canonical
+}
+
+// SchemaCanonicalNamePreferred returns a `CanonicalNameFunc` that uses the
+// first of `preferred` in the names, and otherwise the shortest name.
+func SchemaCanonicalNamePreferred(preferred ...string) func(names []string) string {
+	return func(names []string) string {
+		for _, pref := range preferred {
+			for _, name := range names {
+				if name == pref {
+					return name
+				}
+			}
+		}
+		return SchemaCanonicalNameShortest(names)
+	}
+}
+
+// SchemasDedupe merges component schemas that are structurally equivalent,
+// such as identical models merged from different specs under different
+// names. Refs to the removed names are rewritten with `SchemaRefsModify()`.
+// Merging repeats until no duplicates remain, so schemas that differ only
+// in refs to merged duplicates are merged too. Schemas that are only refs
+// are not merged.
+func (se *SpecEdit) SchemasDedupe(opts *SchemasDedupeOpts) (SchemasDedupeResult, error) {
+	res := SchemasDedupeResult{Duplicates: []SchemaDuplicates{}, NearDuplicates: [][]string{}}
+	spec := se.SpecMore.Spec
+	if spec == nil {
+		return res, openapi3.ErrSpecNotSet
+	}
+	if spec.Components == nil || len(spec.Components.Schemas) == 0 {
+		return res, nil
+	}
+	canonicalNameFunc := SchemaCanonicalNameShortest
+	if opts != nil && opts.CanonicalNameFunc != nil {
+		canonicalNameFunc = opts.CanonicalNameFunc
+	}
+
+	merged := map[string][]string{}
+	for {
+		groups, err := se.schemaGroups(false)
+		if err != nil {
+			return res, err
+		}
+		renames := map[string]string{}
+		for _, names := range groups {
+			canonical := canonicalNameFunc(names)
+			for _, name := range names {
+				if name == canonical {
+					continue
+				}
+				renames[name] = canonical
+				merged[canonical] = append(merged[canonical], name)
+				if prev, ok := merged[name]; ok {
+					merged[canonical] = append(merged[canonical], prev...)
+					delete(merged, name)
+				}
+			}
+		}
+		if len(renames) == 0 {
+			break
+		}
+		se.SchemaRefsModify(func(ref string) string {
+			if name, ok := strings.CutPrefix(ref, openapi3.PointerComponentsSchemas+"/"); ok {
+				if canonical, ok := renames[name]; ok {
+					return openapi3.PointerComponentsSchemas + "/" + canonical
+				}
+			}
+			return ref
+		})
+		for name := range renames {
+			delete(spec.Components.Schemas, name)
+		}
+	}
+	for canonical, names := range merged {
+		names = append(names, canonical)
+		sort.Strings(names)
+		res.Duplicates = append(res.Duplicates, SchemaDuplicates{Canonical: canonical, Names: names})
+	}
+	sort.Slice(res.Duplicates, func(i, j int) bool {
+		return res.Duplicates[i].Canonical < res.Duplicates[j].Canonical
+	})
+
+	near, err := se.schemaGroups(true)
+	if err != nil {
+		return res, err
+	}
+	res.NearDuplicates = near
+	return res, nil
+}
+
+// schemaGroups returns sorted groups of two or more component schema names
+// with equal JSON, optionally ignoring descriptions and examples.
+func (se *SpecEdit) schemaGroups(ignoreDocs bool) ([][]string, error) {
+	byJSON := map[string][]string{}
+	for name, schRef := range se.SpecMore.Spec.Components.Schemas {
+		if schRef == nil || schRef.Value == nil || len(schRef.Ref) > 0 {
+			continue
+		}
+		data, err := json.Marshal(schRef.Value)
+		if err != nil {
+			return nil, err
+		}
+		if ignoreDocs {
+			var v any
+			if err := json.Unmarshal(data, &v); err != nil {
+				return nil, err
+			}
+			if data, err = json.Marshal(schemaRemoveDocs(v)); err != nil {
+				return nil, err
+			}
+		}
+		byJSON[string(data)] = append(byJSON[string(data)], name)
+	}
+	groups := [][]string{}
+	for _, names := range byJSON {
+		if len(names) > 1 {
+			sort.Strings(names)
+			groups = append(groups, names)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+	return groups, nil
+}
+
+// schemaRemoveDocs removes `description`, `example` and `examples` from a
+// decoded schema and its subschemas. Property names are kept.
+func schemaRemoveDocs(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for _, k := range []string{"description", "example", "examples"} {
+			delete(val, k)
+		}
+		for k, item := range val {
+			switch k {
+			case "properties", "patternProperties":
+				if props, ok := item.(map[string]any); ok {
+					for name, prop := range props {
+						props[name] = schemaRemoveDocs(prop)
+					}
+				}
+			case "items", "additionalProperties", "not", "allOf", "anyOf", "oneOf":
+				val[k] = schemaRemoveDocs(item)
+			}
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = schemaRemoveDocs(item)
+		}
+	}
+	return v
+}
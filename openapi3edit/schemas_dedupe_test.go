@@ -0,0 +1,180 @@
+package openapi3edit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/grokify/spectrum/openapi3"
+)
+
+const schemasDedupeSpec = `{
+"openapi":"3.0.3","info":{"title":"Merged","version":"1.0.0"},
+"paths":{
+  "/a":{"get":{"responses":{
+    "200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/AWrapper"}}}},
+    "default":{"description":"Error","content":{"application/json":{"schema":{"$ref":"#/components/schemas/ApiError"}}}}}}},
+  "/b":{"get":{"responses":{
+    "200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/BWrapper"}}}},
+    "default":{"$ref":"#/components/responses/Error"}}}}},
+"components":{
+  "responses":{"Error":{"description":"Error","content":{"application/json":{"schema":{"$ref":"#/components/schemas/ErrorResponse"}}}}},
+  "schemas":{
+    "Error":{"type":"object","properties":{"code":{"type":"integer"},"message":{"type":"string"}}},
+    "ErrorResponse":{"type":"object","properties":{"code":{"type":"integer"},"message":{"type":"string"}}},
+    "ApiError":{"type":"object","properties":{"code":{"type":"integer"},"message":{"type":"string"}}},
+    "AWrapper":{"allOf":[{"$ref":"#/components/schemas/ApiError"}]},
+    "BWrapper":{"allOf":[{"$ref":"#/components/schemas/ErrorResponse"}]},
+    "Pet":{"type":"object","description":"A pet.","properties":{"description":{"type":"string","example":"Friendly"}}},
+    "Animal":{"type":"object","description":"An animal.","properties":{"description":{"type":"string"}}},
+    "Plant":{"type":"object","properties":{"name":{"type":"string"}}}}}}`
+
+// TestSchemasDedupe ensures equivalent schemas are merged, refs are
+// rewritten and near-duplicates are reported.
+func TestSchemasDedupe(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(schemasDedupeSpec))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	se := NewSpecEdit(spec)
+	res, err := se.SchemasDedupe(nil)
+	if err != nil {
+		t.Fatalf("openapi3edit.SpecEdit.SchemasDedupe() Error [%s]", err.Error())
+	}
+	wantDuplicates := []SchemaDuplicates{
+		{Canonical: "AWrapper", Names: []string{"AWrapper", "BWrapper"}},
+		{Canonical: "Error", Names: []string{"ApiError", "Error", "ErrorResponse"}}}
+	if !reflect.DeepEqual(res.Duplicates, wantDuplicates) {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: duplicates want [%v], got [%v]", wantDuplicates, res.Duplicates)
+	}
+	wantNear := [][]string{{"Animal", "Pet"}}
+	if !reflect.DeepEqual(res.NearDuplicates, wantNear) {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: near duplicates want [%v], got [%v]", wantNear, res.NearDuplicates)
+	}
+	sm := se.SpecMore
+	if got := sm.SchemaNames(); !reflect.DeepEqual(got, []string{"AWrapper", "Animal", "Error", "Pet", "Plant"}) {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: schema names got [%v]", got)
+	}
+	_, _, referenceNoSchema, err := sm.SchemaNamesStatus()
+	if err != nil {
+		t.Fatalf("openapi3.SpecMore.SchemaNamesStatus() Error [%s]", err.Error())
+	}
+	if len(referenceNoSchema) > 0 {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: refs to removed schemas [%v]", referenceNoSchema)
+	}
+
+	clone, err := sm.Clone()
+	if err != nil {
+		t.Fatalf("openapi3.SpecMore.Clone() Error [%s]", err.Error())
+	}
+	if err := clone.Validate(context.Background()); err != nil {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Validate Error [%s]", err.Error())
+	}
+
+	prefer := &SchemasDedupeOpts{CanonicalNameFunc: SchemaCanonicalNamePreferred("ErrorResponse")}
+	spec2, err := openapi3.Parse([]byte(schemasDedupeSpec))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	se2 := NewSpecEdit(spec2)
+	if res, err := se2.SchemasDedupe(prefer); err != nil || len(res.Duplicates) != 2 || res.Duplicates[1].Canonical != "ErrorResponse" {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: want preferred canonical [ErrorResponse], got [%v] err [%v]", res.Duplicates, err)
+	}
+}
+
+const schemasDedupeRefsSpec = `{
+"openapi":"3.0.3","info":{"title":"Merged","version":"1.0.0"},
+"paths":{
+  "/pets/{id}":{
+    "parameters":[{"name":"id","in":"path","required":true,"schema":{"$ref":"#/components/schemas/IdB"}}],
+    "get":{"responses":{"200":{"description":"OK","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Pet"}}}}}},
+    "post":{
+      "callbacks":{"onAdopted":{"{$request.body#/callbackUrl}":{"post":{
+        "requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/EventB"}}}},
+        "responses":{"200":{"description":"OK"}}}}}},
+      "responses":{"200":{"description":"OK"}}}}},
+"components":{"schemas":{
+  "IdA":{"type":"string","pattern":"^[a-z0-9]+$"},
+  "IdB":{"type":"string","pattern":"^[a-z0-9]+$"},
+  "Event":{"type":"object","properties":{"petId":{"type":"string"},"at":{"type":"string","format":"date-time"}}},
+  "EventB":{"type":"object","properties":{"petId":{"type":"string"},"at":{"type":"string","format":"date-time"}}},
+  "Cat":{"type":"object","required":["kind"],"properties":{"kind":{"type":"string"},"meows":{"type":"boolean"}}},
+  "CatB":{"type":"object","required":["kind"],"properties":{"kind":{"type":"string"},"meows":{"type":"boolean"}}},
+  "Dog":{"type":"object","required":["kind"],"properties":{"kind":{"type":"string"},"barks":{"type":"boolean"}}},
+  "Pet":{"oneOf":[{"$ref":"#/components/schemas/CatB"},{"$ref":"#/components/schemas/Dog"}],
+    "discriminator":{"propertyName":"kind","mapping":{"cat":"#/components/schemas/CatB","dog":"Dog"}}}}}}`
+
+// TestSchemasDedupeRefs ensures refs in path item parameters, callbacks and
+// discriminator mappings are rewritten so the spec still resolves.
+func TestSchemasDedupeRefs(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(schemasDedupeRefsSpec))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	se := NewSpecEdit(spec)
+	opts := &SchemasDedupeOpts{CanonicalNameFunc: SchemaCanonicalNamePreferred("IdA")}
+	if _, err := se.SchemasDedupe(opts); err != nil {
+		t.Fatalf("openapi3edit.SpecEdit.SchemasDedupe() Error [%s]", err.Error())
+	}
+	if got := se.SpecMore.SchemaNames(); !reflect.DeepEqual(got, []string{"Cat", "Dog", "Event", "IdA", "Pet"}) {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: schema names got [%v]", got)
+	}
+	pathItem := spec.Paths.Find("/pets/{id}")
+	if ref := pathItem.Parameters[0].Value.Schema.Ref; ref != "#/components/schemas/IdA" {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: path item parameter ref want [#/components/schemas/IdA], got [%s]", ref)
+	}
+	cbRef := (*pathItem.Post.Callbacks["onAdopted"].Value.Value("{$request.body#/callbackUrl}")).Post.RequestBody.Value.Content["application/json"].Schema.Ref
+	if cbRef != "#/components/schemas/Event" {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: callback ref want [#/components/schemas/Event], got [%s]", cbRef)
+	}
+	mapping := spec.Components.Schemas["Pet"].Value.Discriminator.Mapping
+	if mapping["cat"].Ref != "#/components/schemas/Cat" || mapping["dog"].Ref != "Dog" {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: discriminator mapping got [%s] [%s]", mapping["cat"].Ref, mapping["dog"].Ref)
+	}
+
+	clone, err := se.SpecMore.Clone()
+	if err != nil {
+		t.Fatalf("openapi3.SpecMore.Clone() Error [%s]", err.Error())
+	}
+	if err := clone.Validate(context.Background()); err != nil {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Validate Error [%s]", err.Error())
+	}
+}
+
+const schemasDedupeRecursiveSpec = `{
+"openapi":"3.0.3","info":{"title":"Tree","version":"1.0.0"},
+"paths":{"/tree":{"get":{"responses":{"200":{"description":"OK",
+  "content":{"application/json":{"schema":{"$ref":"#/components/schemas/TreeNode"}}}}}}}},
+"components":{"schemas":{
+  "Node":{"type":"object","properties":{"name":{"type":"string"},
+    "children":{"type":"array","items":{"$ref":"#/components/schemas/Node"}}}},
+  "TreeNode":{"type":"object","properties":{"name":{"type":"string"},
+    "children":{"type":"array","items":{"$ref":"#/components/schemas/Node"}}}}}}}`
+
+// TestSchemasDedupeRecursive ensures recursive schemas loaded with resolved
+// refs are deduplicated without walking the recursion.
+func TestSchemasDedupeRecursive(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "tree.json")
+	if err := os.WriteFile(filename, []byte(schemasDedupeRecursiveSpec), 0600); err != nil {
+		t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+	}
+	spec, err := openapi3.ReadFile(filename, true)
+	if err != nil {
+		t.Fatalf("openapi3.ReadFile() Error [%s]", err.Error())
+	}
+	se := NewSpecEdit(spec)
+	res, err := se.SchemasDedupe(nil)
+	if err != nil {
+		t.Fatalf("openapi3edit.SpecEdit.SchemasDedupe() Error [%s]", err.Error())
+	}
+	wantDuplicates := []SchemaDuplicates{{Canonical: "Node", Names: []string{"Node", "TreeNode"}}}
+	if !reflect.DeepEqual(res.Duplicates, wantDuplicates) {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: duplicates want [%v], got [%v]", wantDuplicates, res.Duplicates)
+	}
+	schRef := spec.Paths.Find("/tree").Get.Responses.Status(200).Value.Content["application/json"].Schema
+	if schRef.Ref != "#/components/schemas/Node" {
+		t.Errorf("openapi3edit.SpecEdit.SchemasDedupe() Mismatch: response ref want [#/components/schemas/Node], got [%s]", schRef.Ref)
+	}
+}
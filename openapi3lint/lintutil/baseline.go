@@ -0,0 +1,145 @@
+package lintutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"time"
+)
+
+const BaselineVersion = "1"
+
+// Baseline is a snapshot of existing violations used to report only new
+// violations on later runs. Entries are matched by rule name, JSON pointer
+// location and a fingerprint of the violation value, so edits in unrelated
+// parts of a spec do not invalidate the baseline.
+type Baseline struct {
+	Version string          `json:"version"`
+	Created time.Time       `json:"created"`
+	Entries []BaselineEntry `json:"entries"`
+}
+
+type BaselineEntry struct {
+	RuleName    string `json:"rule"`
+	Location    string `json:"location"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// NewBaseline creates a `Baseline` from current violations.
+func NewBaseline(sets *PolicyViolationsSets) Baseline {
+	bl := Baseline{
+		Version: BaselineVersion,
+		Created: time.Now().UTC(),
+		Entries: []BaselineEntry{}}
+	if sets == nil {
+		return bl
+	}
+	for _, vio := range sets.Violations() {
+		bl.Entries = append(bl.Entries, NewBaselineEntry(vio))
+	}
+	sort.SliceStable(bl.Entries, func(i, j int) bool {
+		if bl.Entries[i].RuleName != bl.Entries[j].RuleName {
+			return bl.Entries[i].RuleName < bl.Entries[j].RuleName
+		}
+		return bl.Entries[i].Location < bl.Entries[j].Location
+	})
+	return bl
+}
+
+func NewBaselineEntry(vio PolicyViolation) BaselineEntry {
+	return BaselineEntry{
+		RuleName:    vio.RuleName,
+		Location:    vio.Location,
+		Fingerprint: ViolationFingerprint(vio)}
+}
+
+// ViolationFingerprint returns a hash of the rule name and value that does
+// not depend on the location.
+func ViolationFingerprint(vio PolicyViolation) string {
+	sum := sha256.Sum256([]byte(vio.RuleName + "\x00" + vio.Value))
+	return hex.EncodeToString(sum[:8])
+}
+
+func ReadBaselineFile(filename string) (Baseline, error) {
+	bl := Baseline{}
+	bytes, err := os.ReadFile(filename)
+	if err != nil {
+		return bl, err
+	}
+	return bl, json.Unmarshal(bytes, &bl)
+}
+
+// WriteFile writes the baseline. If `filename` has a baseline with the same
+// version and entries, its `Created` time is kept so an unchanged baseline
+// is written unchanged.
+func (bl Baseline) WriteFile(filename string, perm os.FileMode) error {
+	if prior, err := ReadBaselineFile(filename); err == nil &&
+		prior.Version == bl.Version && reflect.DeepEqual(prior.Entries, bl.Entries) {
+		bl.Created = prior.Created
+	}
+	bytes, err := json.MarshalIndent(bl, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, bytes, perm)
+}
+
+var rxPointerIndex = regexp.MustCompile(`/(allOf|anyOf|enum|oneOf|parameters|security|servers|tags)/\d+`)
+
+// locationNormalized replaces array indexes in JSON pointers so that
+// violations still match when items such as parameters are reordered.
+func locationNormalized(loc string) string {
+	return rxPointerIndex.ReplaceAllString(loc, "/$1/*")
+}
+
+// Filter returns a copy of `sets` with violations present in the baseline
+// removed, along with the number of violations removed. Violations are first
+// matched by exact location. Remaining violations with a non-empty value are
+// then matched by fingerprint and location with array indexes ignored. Each
+// baseline entry matches at most one violation.
+func (bl Baseline) Filter(sets *PolicyViolationsSets) (*PolicyViolationsSets, uint) {
+	out := NewPolicyViolationsSets()
+	if sets == nil {
+		return out, 0
+	}
+	exact := map[BaselineEntry]int{}
+	loose := map[BaselineEntry]int{}
+	for _, entry := range bl.Entries {
+		exact[entry]++
+		entry.Location = locationNormalized(entry.Location)
+		loose[entry]++
+	}
+	var unmatched []PolicyViolation
+	matched := uint(0)
+	for _, vio := range sets.Violations() {
+		entry := NewBaselineEntry(vio)
+		if exact[entry] > 0 {
+			exact[entry]--
+			entry.Location = locationNormalized(entry.Location)
+			loose[entry]--
+			matched++
+			continue
+		}
+		unmatched = append(unmatched, vio)
+	}
+	for _, vio := range unmatched {
+		entry := NewBaselineEntry(vio)
+		entry.Location = locationNormalized(entry.Location)
+		if len(vio.Value) > 0 && loose[entry] > 0 {
+			loose[entry]--
+			matched++
+			continue
+		}
+		out.AddViolation(vio)
+	}
+	for _, set := range sets.SuppressedByRule {
+		for _, vio := range set.Violations {
+			out.AddSuppressed(vio)
+		}
+	}
+	return out, matched
+}
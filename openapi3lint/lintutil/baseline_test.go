@@ -0,0 +1,91 @@
+package lintutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var baselineTests = []struct {
+	baseline []PolicyViolation
+	current  []PolicyViolation
+	newCount uint
+	matched  uint
+}{
+	{
+		[]PolicyViolation{{RuleName: "rule-a", Location: "spec.json#/paths/~1users/get/parameters/0", Value: "user_id"}},
+		[]PolicyViolation{{RuleName: "rule-a", Location: "spec.json#/paths/~1users/get/parameters/0", Value: "user_id"}},
+		0, 1,
+	},
+	{
+		[]PolicyViolation{{RuleName: "rule-a", Location: "spec.json#/paths/~1users/get/parameters/0", Value: "user_id"}},
+		[]PolicyViolation{{RuleName: "rule-a", Location: "spec.json#/paths/~1users/get/parameters/1", Value: "user_id"}},
+		0, 1,
+	},
+	{
+		[]PolicyViolation{{RuleName: "rule-a", Location: "spec.json#/paths/~1users/get/parameters/0", Value: "user_id"}},
+		[]PolicyViolation{
+			{RuleName: "rule-a", Location: "spec.json#/paths/~1users/get/parameters/0", Value: "user_id"},
+			{RuleName: "rule-a", Location: "spec.json#/paths/~1users/get/parameters/1", Value: "group_id"}},
+		1, 1,
+	},
+	{
+		[]PolicyViolation{{RuleName: "rule-b", Location: "spec.json#/paths/~1users/get/responses/200"}},
+		[]PolicyViolation{{RuleName: "rule-b", Location: "spec.json#/paths/~1users/get/responses/404"}},
+		1, 0,
+	},
+}
+
+// TestBaselineFilter ensures `Baseline.Filter()` removes known violations.
+func TestBaselineFilter(t *testing.T) {
+	for i, tt := range baselineTests {
+		setsBaseline := NewPolicyViolationsSets()
+		setsBaseline.AddViolations(tt.baseline)
+		setsCurrent := NewPolicyViolationsSets()
+		setsCurrent.AddViolations(tt.current)
+
+		bl := NewBaseline(setsBaseline)
+		setsNew, matched := bl.Filter(setsCurrent)
+		if setsNew.Count() != tt.newCount || matched != tt.matched {
+			t.Errorf("Baseline.Filter() test [%d] Mismatch: want [%d, %d], got [%d, %d]",
+				i, tt.newCount, tt.matched, setsNew.Count(), matched)
+		}
+	}
+}
+
+// TestBaselineWriteFile ensures rewriting an unchanged baseline keeps the
+// file unchanged and a changed baseline gets a new `Created` time.
+func TestBaselineWriteFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "baseline.json")
+	sets := NewPolicyViolationsSets()
+	sets.AddViolation(PolicyViolation{RuleName: "rule-a", Location: "spec.json#/paths", Value: "a"})
+	bl := NewBaseline(sets)
+	bl.Created = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := bl.WriteFile(filename, 0600); err != nil {
+		t.Fatalf("lintutil.Baseline.WriteFile() Error [%s]", err.Error())
+	}
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("os.ReadFile() Error [%s]", err.Error())
+	}
+	if err := NewBaseline(sets).WriteFile(filename, 0600); err != nil {
+		t.Fatalf("lintutil.Baseline.WriteFile() Error [%s]", err.Error())
+	}
+	if got, err := os.ReadFile(filename); err != nil || !bytes.Equal(got, want) {
+		t.Errorf("lintutil.Baseline.WriteFile() Mismatch: want unchanged file [%s], got [%s]", string(want), string(got))
+	}
+
+	sets.AddViolation(PolicyViolation{RuleName: "rule-b", Location: "spec.json#/paths", Value: "b"})
+	if err := NewBaseline(sets).WriteFile(filename, 0600); err != nil {
+		t.Fatalf("lintutil.Baseline.WriteFile() Error [%s]", err.Error())
+	}
+	bl2, err := ReadBaselineFile(filename)
+	if err != nil {
+		t.Fatalf("lintutil.ReadBaselineFile() Error [%s]", err.Error())
+	}
+	if len(bl2.Entries) != 2 || bl2.Created.Equal(bl.Created) {
+		t.Errorf("lintutil.Baseline.WriteFile() Mismatch: want [2] entries and new created time, got [%d] and [%s]", len(bl2.Entries), bl2.Created)
+	}
+}
@@ -0,0 +1,19 @@
+package lintutil
+
+// PolicyFix describes a single change made by a rule implementing
+// `openapi3lint.Fixer`.
+type PolicyFix struct {
+	RuleName string
+	Location string
+	Before   string
+	After    string
+}
+
+// FixesByRule groups fixes by rule name.
+func FixesByRule(fixes []PolicyFix) map[string][]PolicyFix {
+	m := map[string][]PolicyFix{}
+	for _, fix := range fixes {
+		m[fix.RuleName] = append(m[fix.RuleName], fix)
+	}
+	return m
+}
@@ -0,0 +1,80 @@
+package lintutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/grokify/mogo/log/severity"
+)
+
+const (
+	FormatJSON       = "json"
+	FormatSARIF      = "sarif"
+	FormatJUnit      = "junit"
+	FormatCheckstyle = "checkstyle"
+
+	ToolName           = "spectrum-openapi3lint"
+	ToolInformationURI = "https://github.com/grokify/spectrum"
+)
+
+// Formatter renders `PolicyViolationsSets` for consumption by other tools.
+type Formatter interface {
+	Format(w io.Writer, sets *PolicyViolationsSets) error
+}
+
+// NewFormatter returns a `Formatter` for one of `FormatJSON`, `FormatSARIF`,
+// `FormatJUnit` or `FormatCheckstyle`.
+func NewFormatter(format string) (Formatter, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatJSON, "":
+		return FormatterJSON{Prefix: "", Indent: "  "}, nil
+	case FormatSARIF:
+		return FormatterSARIF{}, nil
+	case FormatJUnit:
+		return FormatterJUnit{}, nil
+	case FormatCheckstyle:
+		return FormatterCheckstyle{}, nil
+	}
+	return nil, fmt.Errorf("unknown format [%s]", format)
+}
+
+// FormatterJSON writes the locations and counts by rule.
+type FormatterJSON struct {
+	Prefix string
+	Indent string
+}
+
+func (f FormatterJSON) Format(w io.Writer, sets *PolicyViolationsSets) error {
+	out := struct {
+		LocationsByRule        ViolationLocationsByRuleSet
+		CountsByRule           map[string]uint
+		SuppressedCountsByRule map[string]uint `json:",omitempty"`
+	}{
+		LocationsByRule:        sets.LocationsByRule(),
+		CountsByRule:           sets.CountsByRule(),
+		SuppressedCountsByRule: sets.SuppressedCountsByRule()}
+	bytes, err := json.MarshalIndent(out, f.Prefix, f.Indent)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(bytes, '\n'))
+	return err
+}
+
+// severityLevel groups syslog-type severities into the three levels
+// commonly used by reporting formats: `error`, `warning` and `info`.
+func severityLevel(sev string) string {
+	sevCanonical, err := severity.Parse(sev)
+	if err != nil {
+		return "error"
+	}
+	switch sevCanonical {
+	case severity.SeverityWarning:
+		return "warning"
+	case severity.SeverityNotice, severity.SeverityInformational, severity.SeverityDebug:
+		return "info"
+	}
+	return "error"
+}
@@ -0,0 +1,48 @@
+package lintutil
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// FormatterCheckstyle writes violations as Checkstyle XML grouped by file.
+// Since violations are identified by JSON pointer instead of line number,
+// `line` is always `0` and the pointer is included in the message.
+type FormatterCheckstyle struct{}
+
+type checkstyleResult struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func (f FormatterCheckstyle) Format(w io.Writer, sets *PolicyViolationsSets) error {
+	res := checkstyleResult{Version: "4.3"}
+	fileIndexes := map[string]int{}
+	for _, vio := range sets.Violations() {
+		file := vio.File()
+		idx, ok := fileIndexes[file]
+		if !ok {
+			idx = len(res.Files)
+			fileIndexes[file] = idx
+			res.Files = append(res.Files, checkstyleFile{Name: file})
+		}
+		res.Files[idx].Errors = append(res.Files[idx].Errors, checkstyleError{
+			Severity: severityLevel(vio.Severity),
+			Message:  vio.Message(),
+			Source:   vio.RuleName})
+	}
+	return writeXML(w, res)
+}
@@ -0,0 +1,79 @@
+package lintutil
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// FormatterJUnit writes violations as JUnit XML with one test suite per rule
+// and one failed test case per violation.
+type FormatterJUnit struct{}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	File      string       `xml:"file,attr,omitempty"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (f FormatterJUnit) Format(w io.Writer, sets *PolicyViolationsSets) error {
+	suites := junitTestSuites{Name: ToolName}
+	vios := sets.Violations()
+	suiteIndexes := map[string]int{}
+	for _, vio := range vios {
+		idx, ok := suiteIndexes[vio.RuleName]
+		if !ok {
+			idx = len(suites.Suites)
+			suiteIndexes[vio.RuleName] = idx
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: vio.RuleName})
+		}
+		suite := &suites.Suites[idx]
+		suite.Tests++
+		suite.Failures++
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      vio.Location,
+			ClassName: vio.RuleName,
+			File:      vio.File(),
+			Failure: junitFailure{
+				Message: vio.Message(),
+				Type:    severityLevel(vio.Severity),
+				Text:    vio.Pointer()}})
+	}
+	suites.Tests = len(vios)
+	suites.Failures = len(vios)
+	return writeXML(w, suites)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
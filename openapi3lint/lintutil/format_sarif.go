@@ -0,0 +1,140 @@
+package lintutil
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// FormatterSARIF writes violations as a SARIF 2.1 log.
+type FormatterSARIF struct{}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	RuleIndex    int                `json:"ruleIndex"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func (f FormatterSARIF) Format(w io.Writer, sets *PolicyViolationsSets) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           ToolName,
+			InformationURI: ToolInformationURI,
+			Rules:          []sarifRule{}}},
+		Results: []sarifResult{}}
+	ruleIndexes := map[string]int{}
+	addRule := func(ruleName string) {
+		if _, ok := ruleIndexes[ruleName]; !ok {
+			ruleIndexes[ruleName] = len(run.Tool.Driver.Rules)
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: ruleName})
+		}
+	}
+	for _, ruleName := range sets.RuleNames() {
+		addRule(ruleName)
+	}
+	for _, vio := range sets.Violations() {
+		run.Results = append(run.Results, sarifResultFromViolation(vio, ruleIndexes[vio.RuleName]))
+	}
+	// Suppressed violations are included with a SARIF `inSource` suppression.
+	for _, vio := range sets.SuppressedViolations() {
+		addRule(vio.RuleName)
+		res := sarifResultFromViolation(vio, ruleIndexes[vio.RuleName])
+		res.Suppressions = []sarifSuppression{{
+			Kind:          "inSource",
+			Justification: vio.Data[DataKeySuppressionReason]}}
+		run.Results = append(run.Results, res)
+	}
+	bytes, err := json.MarshalIndent(sarifLog{
+		Version: SARIFVersion,
+		Schema:  SARIFSchema,
+		Runs:    []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(bytes, '\n'))
+	return err
+}
+
+func sarifResultFromViolation(vio PolicyViolation, ruleIndex int) sarifResult {
+	loc := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{
+			FullyQualifiedName: vio.Pointer(),
+			Kind:               "element"}}}
+	if file := vio.File(); len(file) > 0 {
+		loc.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: file}}
+	}
+	return sarifResult{
+		RuleID:    vio.RuleName,
+		RuleIndex: ruleIndex,
+		Level:     sarifLevel(vio.Severity),
+		Message:   sarifMessage{Text: vio.Message()},
+		Locations: []sarifLocation{loc}}
+}
+
+func sarifLevel(sev string) string {
+	level := severityLevel(sev)
+	if level == "info" {
+		return "note"
+	}
+	return level
+}
@@ -0,0 +1,240 @@
+package lintutil
+
+import (
+	"bytes"
+	"testing"
+)
+
+const formatGoldenSARIF = `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "spectrum-openapi3lint",
+          "informationUri": "https://github.com/grokify/spectrum",
+          "rules": [
+            {
+              "id": "operation-operationid-style-camelcase"
+            },
+            {
+              "id": "operation-summary-exist"
+            },
+            {
+              "id": "tag-style-first-uppercase"
+            },
+            {
+              "id": "schema-has-reference"
+            }
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "operation-operationid-style-camelcase",
+          "ruleIndex": 0,
+          "level": "warning",
+          "message": {
+            "text": "rule [operation-operationid-style-camelcase] violated at #/paths/~1pets/post/operationId [create_pet]"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "pets.json"
+                }
+              },
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "#/paths/~1pets/post/operationId",
+                  "kind": "element"
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "ruleId": "operation-summary-exist",
+          "ruleIndex": 1,
+          "level": "error",
+          "message": {
+            "text": "rule [operation-summary-exist] violated at #/paths/~1pets/get"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "pets.json"
+                }
+              },
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "#/paths/~1pets/get",
+                  "kind": "element"
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "ruleId": "operation-summary-exist",
+          "ruleIndex": 1,
+          "level": "error",
+          "message": {
+            "text": "rule [operation-summary-exist] violated at #/paths/~1stores/get"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "stores.json"
+                }
+              },
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "#/paths/~1stores/get",
+                  "kind": "element"
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "ruleId": "tag-style-first-uppercase",
+          "ruleIndex": 2,
+          "level": "note",
+          "message": {
+            "text": "rule [tag-style-first-uppercase] violated at #/tags/0 [stores]"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "stores.json"
+                }
+              },
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "#/tags/0",
+                  "kind": "element"
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "ruleId": "schema-has-reference",
+          "ruleIndex": 3,
+          "level": "error",
+          "message": {
+            "text": "rule [schema-has-reference] violated at #/components/schemas/Pet"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "pets.json"
+                }
+              },
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "#/components/schemas/Pet",
+                  "kind": "element"
+                }
+              ]
+            }
+          ],
+          "suppressions": [
+            {
+              "kind": "inSource",
+              "justification": "legacy"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`
+
+const formatGoldenJUnit = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="spectrum-openapi3lint" tests="4" failures="4">
+  <testsuite name="operation-operationid-style-camelcase" tests="1" failures="1">
+    <testcase name="pets.json#/paths/~1pets/post/operationId" classname="operation-operationid-style-camelcase" file="pets.json">
+      <failure message="rule [operation-operationid-style-camelcase] violated at #/paths/~1pets/post/operationId [create_pet]" type="warning">#/paths/~1pets/post/operationId</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="operation-summary-exist" tests="2" failures="2">
+    <testcase name="pets.json#/paths/~1pets/get" classname="operation-summary-exist" file="pets.json">
+      <failure message="rule [operation-summary-exist] violated at #/paths/~1pets/get" type="error">#/paths/~1pets/get</failure>
+    </testcase>
+    <testcase name="stores.json#/paths/~1stores/get" classname="operation-summary-exist" file="stores.json">
+      <failure message="rule [operation-summary-exist] violated at #/paths/~1stores/get" type="error">#/paths/~1stores/get</failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="tag-style-first-uppercase" tests="1" failures="1">
+    <testcase name="stores.json#/tags/0" classname="tag-style-first-uppercase" file="stores.json">
+      <failure message="rule [tag-style-first-uppercase] violated at #/tags/0 [stores]" type="info">#/tags/0</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+const formatGoldenCheckstyle = `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="pets.json">
+    <error line="0" severity="warning" message="rule [operation-operationid-style-camelcase] violated at #/paths/~1pets/post/operationId [create_pet]" source="operation-operationid-style-camelcase"></error>
+    <error line="0" severity="error" message="rule [operation-summary-exist] violated at #/paths/~1pets/get" source="operation-summary-exist"></error>
+  </file>
+  <file name="stores.json">
+    <error line="0" severity="error" message="rule [operation-summary-exist] violated at #/paths/~1stores/get" source="operation-summary-exist"></error>
+    <error line="0" severity="info" message="rule [tag-style-first-uppercase] violated at #/tags/0 [stores]" source="tag-style-first-uppercase"></error>
+  </file>
+</checkstyle>
+`
+
+var formatTests = []struct {
+	format string
+	want   string
+}{
+	{FormatSARIF, formatGoldenSARIF},
+	{FormatJUnit, formatGoldenJUnit},
+	{FormatCheckstyle, formatGoldenCheckstyle},
+}
+
+// TestFormatters ensures the SARIF, JUnit and Checkstyle output structure,
+// file grouping, suppressions and severity mapping match golden output.
+func TestFormatters(t *testing.T) {
+	for _, tt := range formatTests {
+		fmtr, err := NewFormatter(tt.format)
+		if err != nil {
+			t.Fatalf("lintutil.NewFormatter(%s) Error [%s]", tt.format, err.Error())
+		}
+		var buf bytes.Buffer
+		if err := fmtr.Format(&buf, formatTestSets()); err != nil {
+			t.Fatalf("Formatter.Format(%s) Error [%s]", tt.format, err.Error())
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("Formatter.Format(%s) Mismatch: want [%s], got [%s]", tt.format, tt.want, got)
+		}
+	}
+}
+
+// formatTestSets returns violations across two files covering the error,
+// warning and info severity levels plus a suppressed violation.
+func formatTestSets() *PolicyViolationsSets {
+	sets := NewPolicyViolationsSets()
+	sets.AddViolation(PolicyViolation{RuleName: "operation-summary-exist", Severity: "error",
+		Location: "pets.json#/paths/~1pets/get"})
+	sets.AddViolation(PolicyViolation{RuleName: "operation-operationid-style-camelcase", Severity: "warning",
+		Location: "pets.json#/paths/~1pets/post/operationId", Value: "create_pet"})
+	sets.AddViolation(PolicyViolation{RuleName: "tag-style-first-uppercase", Severity: "informational",
+		Location: "stores.json#/tags/0", Value: "stores"})
+	sets.AddViolation(PolicyViolation{RuleName: "operation-summary-exist", Severity: "critical",
+		Location: "stores.json#/paths/~1stores/get"})
+	sets.AddSuppressed(PolicyViolation{RuleName: "schema-has-reference", Severity: "error",
+		Location: "pets.json#/components/schemas/Pet",
+		Data:     map[string]string{DataKeySuppressionReason: "legacy"}})
+	return sets
+}
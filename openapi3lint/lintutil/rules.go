@@ -17,6 +17,11 @@ const (
 	RulenameOpSummaryExist               = "operation-summary-exist"
 	RulenameOpSummaryStyleFirstUpperCase = "operation-summary-style-first-uppercase"
 
+	RulenameOpResponseSuccessExist           = "operation-response-success-exist"
+	RulenameOpResponseErrorSchemaShared      = "operation-response-error-schema-shared"
+	RulenameOpResponseDefaultOr500Exist      = "operation-response-default-or-500-exist"
+	RulenameOpResponse201LocationHeaderExist = "operation-response-201-location-header-exist"
+
 	RuleOpTagsCountOneOnly = "operation-tags-count-one"
 	RulePathParamNameExist = "path-param-name-exist"
 
@@ -3,6 +3,8 @@ package lintutil
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/grokify/mogo/type/stringsutil"
 )
@@ -10,13 +12,16 @@ import (
 // PolicyViolationsSets is a container for `openapi3lint` policy violations.
 // Common approaches to view violatiosn are to use `PolicyViolationsSets.LocationsByRule()`
 // and `PolicyViolationsSets.CountsByRule()`.
+// Violations suppressed via `x-lint-ignore` are stored separately in `SuppressedByRule`.
 type PolicyViolationsSets struct {
-	ByRule map[string]PolicyViolationsSet
+	ByRule           map[string]PolicyViolationsSet
+	SuppressedByRule map[string]PolicyViolationsSet `json:",omitempty"`
 }
 
 func NewPolicyViolationsSets() *PolicyViolationsSets {
 	return &PolicyViolationsSets{
-		ByRule: map[string]PolicyViolationsSet{}}
+		ByRule:           map[string]PolicyViolationsSet{},
+		SuppressedByRule: map[string]PolicyViolationsSet{}}
 }
 
 func (sets *PolicyViolationsSets) AddViolations(violations []PolicyViolation) {
@@ -34,6 +39,19 @@ func (sets *PolicyViolationsSets) AddViolation(violation PolicyViolation) {
 	sets.ByRule[violation.RuleName] = set
 }
 
+// AddSuppressed adds a violation that has been suppressed.
+func (sets *PolicyViolationsSets) AddSuppressed(violation PolicyViolation) {
+	if sets.SuppressedByRule == nil {
+		sets.SuppressedByRule = map[string]PolicyViolationsSet{}
+	}
+	set, ok := sets.SuppressedByRule[violation.RuleName]
+	if !ok {
+		set = NewPolicyViolationsSet(violation.RuleName)
+	}
+	set.Violations = append(set.Violations, violation)
+	sets.SuppressedByRule[violation.RuleName] = set
+}
+
 func (sets *PolicyViolationsSets) AddSimple(ruleName, location, value string) {
 	set, ok := sets.ByRule[ruleName]
 	if !ok {
@@ -49,21 +67,13 @@ func (sets *PolicyViolationsSets) AddSimple(ruleName, location, value string) {
 
 func (sets *PolicyViolationsSets) UpsertSet(upsertSet PolicyViolationsSet) error {
 	for _, vio := range upsertSet.Violations {
-		ruleName := vio.RuleName
-		if len(ruleName) == 0 {
-			ruleName = upsertSet.RuleName
+		if len(vio.RuleName) == 0 {
+			vio.RuleName = upsertSet.RuleName
 		}
-		if len(ruleName) == 0 {
+		if len(vio.RuleName) == 0 {
 			return errors.New("violation & violationSet have no RuleName")
 		}
-		existingSet, ok := sets.ByRule[upsertSet.RuleName]
-		if !ok {
-			sets.ByRule[upsertSet.RuleName] = upsertSet
-		} else {
-			existingSet.Violations = append(
-				existingSet.Violations, vio)
-			sets.ByRule[ruleName] = existingSet
-		}
+		sets.AddViolation(vio)
 	}
 	return nil
 }
@@ -81,6 +91,14 @@ func (sets *PolicyViolationsSets) UpsertSets(upsertSets *PolicyViolationsSets) e
 			return err
 		}
 	}
+	for _, suppressedSet := range upsertSets.SuppressedByRule {
+		for _, vio := range suppressedSet.Violations {
+			if len(vio.RuleName) == 0 {
+				vio.RuleName = suppressedSet.RuleName
+			}
+			sets.AddSuppressed(vio)
+		}
+	}
 	return nil
 }
 
@@ -106,6 +124,56 @@ func (sets *PolicyViolationsSets) LocationsByRule() ViolationLocationsByRuleSet
 	return vlrs
 }
 
+// Violations returns all violations sorted by rule name and location.
+func (sets *PolicyViolationsSets) Violations() []PolicyViolation {
+	vios := []PolicyViolation{}
+	for _, set := range sets.ByRule {
+		vios = append(vios, set.Violations...)
+	}
+	sortViolations(vios)
+	return vios
+}
+
+// SuppressedViolations returns all suppressed violations sorted by rule name and location.
+func (sets *PolicyViolationsSets) SuppressedViolations() []PolicyViolation {
+	vios := []PolicyViolation{}
+	for _, set := range sets.SuppressedByRule {
+		vios = append(vios, set.Violations...)
+	}
+	sortViolations(vios)
+	return vios
+}
+
+// Sort orders the violations in each set by location so output does not
+// depend on map iteration order.
+func (sets *PolicyViolationsSets) Sort() {
+	for _, set := range sets.ByRule {
+		sortViolations(set.Violations)
+	}
+	for _, set := range sets.SuppressedByRule {
+		sortViolations(set.Violations)
+	}
+}
+
+func sortViolations(vios []PolicyViolation) {
+	sort.SliceStable(vios, func(i, j int) bool {
+		if vios[i].RuleName != vios[j].RuleName {
+			return vios[i].RuleName < vios[j].RuleName
+		}
+		return vios[i].Location < vios[j].Location
+	})
+}
+
+// RuleNames returns the sorted list of rule names with violations.
+func (sets *PolicyViolationsSets) RuleNames() []string {
+	names := []string{}
+	for ruleName := range sets.ByRule {
+		names = append(names, ruleName)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (sets *PolicyViolationsSets) Count() uint {
 	count := uint(0)
 	for _, set := range sets.ByRule {
@@ -122,6 +190,24 @@ func (sets *PolicyViolationsSets) CountsByRule() map[string]uint {
 	return counts
 }
 
+// SuppressedCount returns the number of suppressed violations.
+func (sets *PolicyViolationsSets) SuppressedCount() uint {
+	count := uint(0)
+	for _, set := range sets.SuppressedByRule {
+		count += set.Count()
+	}
+	return count
+}
+
+// SuppressedCountsByRule returns the number of suppressed violations by rule.
+func (sets *PolicyViolationsSets) SuppressedCountsByRule() map[string]uint {
+	counts := map[string]uint{}
+	for _, set := range sets.SuppressedByRule {
+		counts[set.RuleName] = set.Count()
+	}
+	return counts
+}
+
 type PolicyRule struct {
 	Name         string
 	StringFormat string
@@ -162,15 +248,56 @@ func (vl *PolicyViolationLocations) Finalize() {
 	vl.Locations = stringsutil.SliceCondenseSpace(vl.Locations, true, true)
 }
 
+// DataKeySuppressionReason is the `PolicyViolation.Data` key for the reason
+// provided with an `x-lint-ignore` suppression.
+const DataKeySuppressionReason = "suppressionReason"
+
 type PolicyViolation struct {
 	RuleName  string
 	RuleType  string
+	Severity  string
 	Violation string
 	Value     string
 	Location  string
 	Data      map[string]string
 }
 
+// File returns the file portion of the location, which is the pointer base
+// injected by `Policy.ValidateSpecFiles()`.
+func (vio *PolicyViolation) File() string {
+	file, _ := SplitLocation(vio.Location)
+	return file
+}
+
+// Pointer returns the JSON pointer portion of the location.
+func (vio *PolicyViolation) Pointer() string {
+	_, ptr := SplitLocation(vio.Location)
+	return ptr
+}
+
+// Message returns a human readable description of the violation.
+func (vio *PolicyViolation) Message() string {
+	msg := vio.Violation
+	if len(msg) == 0 {
+		msg = fmt.Sprintf("rule [%s] violated", vio.RuleName)
+	}
+	msg += " at " + vio.Pointer()
+	if len(vio.Value) > 0 {
+		msg += " [" + vio.Value + "]"
+	}
+	return msg
+}
+
+// SplitLocation splits a location such as `myspec.yaml#/paths/~1users/get`
+// into a file and a JSON pointer.
+func SplitLocation(location string) (file, pointer string) {
+	idx := strings.Index(location, "#")
+	if idx < 0 {
+		return "", location
+	}
+	return location[:idx], location[idx:]
+}
+
 type ViolationLocationsByRuleSet struct {
 	ViolationLocationsByRule map[string][]string
 }
@@ -18,7 +18,8 @@ import (
 
 type Policy struct {
 	//rules       map[string]Rule
-	policyRules map[string]PolicyRule
+	policyRules  map[string]PolicyRule
+	ruleSettings RuleSettings
 }
 
 func NewPolicy() Policy {
@@ -98,6 +99,12 @@ func (pol *Policy) AddRule(rule Rule, errorOnCollision bool) error {
 }
 */
 
+// RuleSettings returns the effective rule settings and their sources when
+// the policy was created from a `PolicyConfig`.
+func (pol *Policy) RuleSettings() []RuleSetting {
+	return pol.ruleSettings.Slice()
+}
+
 func (pol *Policy) RuleNames() []string {
 	ruleNames := []string{}
 	for rn := range pol.policyRules {
@@ -140,7 +147,7 @@ func (pol *Policy) ValidateSpec(spec *openapi3.Spec, pointerBase, filterSeverity
 		return vsets, err
 	}
 
-	return vsets, nil
+	return vsets, applySuppressions(spec, vsets)
 }
 
 func (pol *Policy) processRulesSpecification(spec *openapi3.Spec, pointerBase, filterSeverity string) (*lintutil.PolicyViolationsSets, error) {
@@ -160,7 +167,8 @@ func (pol *Policy) processRulesSpecification(spec *openapi3.Spec, pointerBase, f
 		// fmt.Printf("FILTER_SEV [%v] ITEM_SEV [%v] INCL [%v]\n", filterSeverity, rule.Severity(), inclRule)
 		if inclRule {
 			//fmt.Printf("PROC RULE name[%s] scope[%s] sev[%s]\n", rule.Name(), rule.Scope(), rule.Severity())
-			vsets.AddViolations(policyRule.Rule.ProcessSpec(spec, pointerBase))
+			vsets.AddViolations(violationsWithSeverity(
+				policyRule.Rule.ProcessSpec(spec, pointerBase), policyRule.Severity))
 		}
 	}
 	return vsets, nil
@@ -190,7 +198,8 @@ func (pol *Policy) processRulesOperation(spec *openapi3.Spec, pointerBase, filte
 					severityErrorRules = append(severityErrorRules, policyRule.Rule.Name())
 					unknownSeverities = append(unknownSeverities, policyRule.Severity)
 				} else if inclRule {
-					vsets.AddViolations(policyRule.Rule.ProcessOperation(spec, op, opPointer, path, method))
+					vsets.AddViolations(violationsWithSeverity(
+						policyRule.Rule.ProcessOperation(spec, op, opPointer, path, method), policyRule.Severity))
 				}
 			}
 		},
@@ -209,6 +218,17 @@ func (pol *Policy) processRulesOperation(spec *openapi3.Spec, pointerBase, filte
 	return vsets, nil
 }
 
+// violationsWithSeverity sets the policy severity on violations that
+// do not have one set by the rule.
+func violationsWithSeverity(vios []lintutil.PolicyViolation, sev string) []lintutil.PolicyViolation {
+	for i, vio := range vios {
+		if len(vio.Severity) == 0 {
+			vios[i].Severity = sev
+		}
+	}
+	return vios
+}
+
 var ErrNoSpecFiles = errors.New("no spec files supplied")
 
 // ValidateSpecFiles executes the policy against a set of one or more spec files.
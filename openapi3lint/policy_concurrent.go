@@ -0,0 +1,125 @@
+package openapi3lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/grokify/mogo/log/severity"
+	"github.com/grokify/mogo/path/filepathutil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+)
+
+// FileResult is the result of validating one spec file. `Err` is set when
+// the file could not be read or validated.
+type FileResult struct {
+	Filename   string
+	Violations *lintutil.PolicyViolationsSets
+	Err        error
+}
+
+type FileResults []FileResult
+
+// Errors returns the joined per-file errors or nil if all files were validated.
+func (results FileResults) Errors() error {
+	var errs []error
+	for _, res := range results {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", res.Filename, res.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateSpecFilesConcurrent executes the policy against spec files using
+// a pool of `workers` goroutines. A `workers` value less than 1 uses
+// `runtime.NumCPU()`. Like `ValidateSpecFiles()`, each location is prefixed
+// with the file's leaf name so output and baselines match sequential runs.
+// Files with the same leaf name in different directories therefore produce
+// the same locations; use the per-file `FileResults` to tell them apart.
+// Errors for individual files are captured in the returned `FileResults`
+// and do not stop other files from being validated.
+// Violations from successful files are merged in the order of `specfiles`
+// with each file's violations sorted by location, so output is deterministic.
+// The returned error is only set for invalid arguments or when `ctx` is done.
+func (pol *Policy) ValidateSpecFilesConcurrent(ctx context.Context, filterSeverity string, specfiles []string, workers int) (*lintutil.PolicyViolationsSets, FileResults, error) {
+	if len(specfiles) == 0 {
+		return nil, nil, ErrNoSpecFiles
+	}
+	severityLevel, err := severity.Parse(filterSeverity)
+	if err != nil {
+		return nil, nil, err
+	}
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(specfiles) {
+		workers = len(specfiles)
+	}
+
+	results := make(FileResults, len(specfiles))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = pol.validateSpecFile(ctx, specfiles[i], severityLevel)
+			}
+		}()
+	}
+feed:
+	for i := range specfiles {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		for i, res := range results {
+			if len(res.Filename) == 0 {
+				results[i] = FileResult{Filename: specfiles[i], Err: err}
+			}
+		}
+		return nil, results, err
+	}
+
+	vsets := lintutil.NewPolicyViolationsSets()
+	for _, res := range results {
+		if res.Err != nil {
+			continue
+		}
+		if err := vsets.UpsertSets(res.Violations); err != nil {
+			return nil, results, err
+		}
+	}
+	return vsets, results, nil
+}
+
+func (pol *Policy) validateSpecFile(ctx context.Context, filename, severityLevel string) FileResult {
+	res := FileResult{Filename: filename}
+	if res.Err = ctx.Err(); res.Err != nil {
+		return res
+	}
+	spec, err := openapi3.ReadFile(filename, false)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	vsets, err := pol.ValidateSpec(spec, filepathutil.FilepathLeaf(filename), severityLevel)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	vsets.Sort()
+	res.Violations = vsets
+	return res
+}
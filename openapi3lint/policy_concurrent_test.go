@@ -0,0 +1,83 @@
+package openapi3lint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+)
+
+const concurrentSpecJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},
+"paths":{
+  "/users":{"get":{"operationId":"get_users","responses":{"200":{"description":"OK"}}}},
+  "/pets":{"get":{"operationId":"get_pets","responses":{"200":{"description":"OK"}}}}}}`
+
+// TestValidateSpecFilesConcurrent ensures results are ordered by input file
+// and per-file errors are captured.
+func TestValidateSpecFilesConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{}
+	for i := 0; i < 8; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("spec%d.json", i))
+		if err := os.WriteFile(file, []byte(concurrentSpecJSON), 0600); err != nil {
+			t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+		}
+		files = append(files, file)
+	}
+	files = append(files, filepath.Join(dir, "missing.json"))
+
+	pol := NewPolicy()
+	rule, err := NewRuleCollectionStandard().RuleWithOptions(lintutil.RulenameOpIDStyleCamelCase, nil)
+	if err != nil {
+		t.Fatalf("RuleCollectionStandard.RuleWithOptions() Error [%s]", err.Error())
+	}
+	if err := pol.AddRule(rule, "error", true); err != nil {
+		t.Fatalf("Policy.AddRule() Error [%s]", err.Error())
+	}
+
+	vsets, results, err := pol.ValidateSpecFilesConcurrent(context.Background(), "info", files, 3)
+	if err != nil {
+		t.Fatalf("Policy.ValidateSpecFilesConcurrent() Error [%s]", err.Error())
+	}
+	if len(results) != len(files) || results[len(files)-1].Err == nil || results.Errors() == nil {
+		t.Errorf("Policy.ValidateSpecFilesConcurrent() Mismatch: want error for [%s]", files[len(files)-1])
+	}
+	got := vsets.ByRule[lintutil.RulenameOpIDStyleCamelCase].Violations
+	if len(got) != 16 {
+		t.Fatalf("Policy.ValidateSpecFilesConcurrent() Count Mismatch: want [16], got [%d]", len(got))
+	}
+	for i, vio := range got {
+		want := filepath.Base(files[i/2]) + "#/paths/~1pets/get/operationId"
+		if i%2 == 1 {
+			want = filepath.Base(files[i/2]) + "#/paths/~1users/get/operationId"
+		}
+		if vio.Location != want {
+			t.Errorf("Policy.ValidateSpecFilesConcurrent() Order Mismatch: want [%s], got [%s]", want, vio.Location)
+		}
+	}
+
+	vsetsSeq, err := pol.ValidateSpecFiles("info", files[:len(files)-1])
+	if err != nil {
+		t.Fatalf("Policy.ValidateSpecFiles() Error [%s]", err.Error())
+	}
+	vsetsSeq.Sort()
+	gotSeq := vsetsSeq.ByRule[lintutil.RulenameOpIDStyleCamelCase].Violations
+	if len(gotSeq) != len(got) {
+		t.Fatalf("Policy.ValidateSpecFiles() Count Mismatch: want [%d], got [%d]", len(got), len(gotSeq))
+	}
+	for i, vio := range gotSeq {
+		if vio.Location != got[i].Location {
+			t.Errorf("Policy.ValidateSpecFiles() Location Mismatch with concurrent: want [%s], got [%s]", got[i].Location, vio.Location)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := pol.ValidateSpecFilesConcurrent(ctx, "info", files, 3); err == nil {
+		t.Errorf("Policy.ValidateSpecFilesConcurrent() Mismatch: want context error, got [nil]")
+	}
+}
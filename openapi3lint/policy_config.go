@@ -4,44 +4,88 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/grokify/mogo/errors/errorsutil"
 	"github.com/grokify/mogo/type/stringsutil"
+	"github.com/grokify/spectrum/openapi3lint/ruledeclarative"
+	"sigs.k8s.io/yaml"
 )
 
+// PolicyConfig is a policy definition read from a JSON or YAML file.
+// `Extends` lists base policy files or built-in profiles to inherit from.
+// `IncludeStandardRules` is inherited unless set explicitly.
 type PolicyConfig struct {
-	Name                 string                `json:"name"`
-	Version              string                `json:"version"`
-	LastUpdated          time.Time             `json:"lastUpdated,omitempty"`
-	IncludeStandardRules bool                  `json:"includeStandardRules"`
-	Rules                map[string]RuleConfig `json:"rules,omitempty"`
-	NonStandardRules     []string              `json:"nonStandardRules,omitempty"`
-	xRuleCollections     RuleCollections       `json:"-"`
+	Extends              []string                     `json:"extends,omitempty"`
+	Name                 string                       `json:"name"`
+	Version              string                       `json:"version"`
+	LastUpdated          time.Time                    `json:"lastUpdated,omitempty"`
+	IncludeStandardRules bool                         `json:"includeStandardRules"`
+	Rules                map[string]RuleConfig        `json:"rules,omitempty"`
+	NonStandardRules     []string                     `json:"nonStandardRules,omitempty"`
+	DeclarativeRules     []ruledeclarative.RuleConfig `json:"declarativeRules,omitempty"`
+	xRuleCollections     RuleCollections              `json:"-"`
+	filename             string
+	// includeStandardRulesSet is true when `includeStandardRules` was read
+	// from the policy file, so an explicit `false` overrides a base policy.
+	includeStandardRulesSet bool
 }
 
+func (polCfg *PolicyConfig) UnmarshalJSON(data []byte) error {
+	type policyConfig PolicyConfig
+	v := policyConfig(*polCfg)
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	members := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	*polCfg = PolicyConfig(v)
+	_, polCfg.includeStandardRulesSet = members["includeStandardRules"]
+	return nil
+}
+
+// NewPolicyConfigFile reads a policy file. Files with a `.yaml` or `.yml`
+// extension are read as YAML, otherwise as JSON.
 func NewPolicyConfigFile(filename string) (PolicyConfig, error) {
-	pol := PolicyConfig{}
+	pol := PolicyConfig{filename: filename}
 	bytes, err := os.ReadFile(filename)
 	if err != nil {
 		return pol, err
 	}
-	return pol, json.Unmarshal(bytes, &pol)
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(bytes, &pol)
+	default:
+		err = json.Unmarshal(bytes, &pol)
+	}
+	return pol, err
+}
+
+// standardRulesSet returns true if `IncludeStandardRules` overrides the
+// value inherited from base policies.
+func (polCfg *PolicyConfig) standardRulesSet() bool {
+	return polCfg.includeStandardRulesSet || polCfg.IncludeStandardRules
 }
 
 const (
-	RuleTypeAll        = "all"
-	RuleTypeStandard   = "standard"
-	RuleTypeXDefined   = "xdefined"
-	RuleTypeXUndefined = "xundefined"
+	RuleTypeAll         = "all"
+	RuleTypeDeclarative = "declarative"
+	RuleTypeStandard    = "standard"
+	RuleTypeXDefined    = "xdefined"
+	RuleTypeXUndefined  = "xundefined"
 )
 
 func (polCfg *PolicyConfig) RuleNames() map[string][]string {
 	ruleNamesMap := map[string][]string{
-		RuleTypeAll:        {},
-		RuleTypeStandard:   {},
-		RuleTypeXDefined:   {},
-		RuleTypeXUndefined: {}}
+		RuleTypeAll:         {},
+		RuleTypeDeclarative: {},
+		RuleTypeStandard:    {},
+		RuleTypeXDefined:    {},
+		RuleTypeXUndefined:  {}}
 	stdRules := NewRuleCollectionStandard()
 	xRuleNames := map[string]int{} // defined = 1, undefined 0
 	for ruleName := range polCfg.Rules {
@@ -66,6 +110,11 @@ func (polCfg *PolicyConfig) RuleNames() map[string][]string {
 			}
 		}
 	}
+	for _, ruleCfg := range polCfg.DeclarativeRules {
+		ruleNamesMap[RuleTypeAll] = append(ruleNamesMap[RuleTypeAll], ruleCfg.Name)
+		ruleNamesMap[RuleTypeDeclarative] =
+			append(ruleNamesMap[RuleTypeDeclarative], ruleCfg.Name)
+	}
 	for ruleName, ruleVal := range xRuleNames {
 		if ruleVal >= 1 {
 			ruleNamesMap[RuleTypeXDefined] =
@@ -87,10 +136,23 @@ func (polCfg *PolicyConfig) AddRuleCollection(collection RuleCollection) {
 }
 
 type RuleConfig struct {
-	Severity string `json:"severity"`
+	Severity string            `json:"severity"`
+	Options  map[string]string `json:"options,omitempty"`
 }
 
+// Policy resolves `Extends` and returns the resulting `Policy`. Use
+// `Policy.RuleSettings()` to see where each rule setting came from.
 func (polCfg *PolicyConfig) Policy() (Policy, error) {
+	resolved, settings, err := polCfg.Resolve()
+	if err != nil {
+		return NewPolicy(), err
+	}
+	pol, err := resolved.policy()
+	pol.ruleSettings = settings
+	return pol, err
+}
+
+func (polCfg *PolicyConfig) policy() (Policy, error) {
 	pol := NewPolicy()
 	stdRules := NewRuleCollectionStandard()
 	ruleCollectionsMap := map[string][]string{}
@@ -102,7 +164,7 @@ func (polCfg *PolicyConfig) Policy() (Policy, error) {
 					ruleCollectionsMap[ruleName] = []string{}
 				}
 				ruleCollectionsMap[ruleName] = append(ruleCollectionsMap[ruleName], stdRules.Name())
-				rule, err := stdRules.Rule(ruleName)
+				rule, err := collectionRule(stdRules, ruleName, ruleCfg.Options)
 				if err != nil {
 					return pol, errorsutil.Wrap(err, "standard error not found. PolicyConfig.Policy()")
 				}
@@ -121,7 +183,7 @@ func (polCfg *PolicyConfig) Policy() (Policy, error) {
 					ruleCollectionsMap[ruleName] = []string{}
 				}
 				ruleCollectionsMap[ruleName] = append(ruleCollectionsMap[ruleName], collection.Name())
-				rule, err := collection.Rule(ruleName)
+				rule, err := collectionRule(collection, ruleName, ruleCfg.Options)
 				if err != nil {
 					return pol, errorsutil.Wrap(err, "collection rule exists but not found. PolicyConfig.Policy()")
 				}
@@ -150,5 +212,15 @@ func (polCfg *PolicyConfig) Policy() (Policy, error) {
 		return pol, fmt.Errorf("rule collisions: %s", string(bytes))
 	}
 
+	for _, ruleCfg := range polCfg.DeclarativeRules {
+		rule, err := ruledeclarative.NewRule(ruleCfg)
+		if err != nil {
+			return pol, errorsutil.Wrap(err, "ruledeclarative.NewRule()")
+		}
+		if err = pol.AddRule(rule, ruleCfg.Severity, true); err != nil {
+			return pol, errorsutil.Wrap(err, fmt.Sprintf("Policy.AddRule() [%s]", ruleCfg.Name))
+		}
+	}
+
 	return pol, nil
 }
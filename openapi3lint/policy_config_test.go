@@ -0,0 +1,55 @@
+package openapi3lint
+
+import (
+	"testing"
+
+	"github.com/grokify/mogo/log/severity"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+)
+
+const ruleOptionsSpecJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},
+"paths":{"/pets":{"get":{"responses":{
+  "200":{"description":"OK"},
+  "500":{"description":"Error","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Problem"}}}}}}}},
+"components":{"schemas":{"Error":{"type":"object"},"Problem":{"type":"object"}}}}`
+
+var ruleOptionsTests = []struct {
+	options   map[string]string
+	wantCount int
+	wantValue string
+}{
+	{nil, 0, ""},
+	{map[string]string{"schema": "Error"}, 1, "#/components/schemas/Problem"},
+	{map[string]string{"schema": "#/components/schemas/Problem"}, 0, ""},
+}
+
+// TestPolicyConfigRuleOptions ensures `RuleConfig.Options` are passed to
+// standard rules.
+func TestPolicyConfigRuleOptions(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(ruleOptionsSpecJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	for _, tt := range ruleOptionsTests {
+		polCfg := PolicyConfig{
+			IncludeStandardRules: true,
+			Rules: map[string]RuleConfig{
+				lintutil.RulenameOpResponseErrorSchemaShared: {Severity: severity.SeverityError, Options: tt.options}}}
+		pol, err := polCfg.Policy()
+		if err != nil {
+			t.Fatalf("PolicyConfig.Policy() Error [%s]", err.Error())
+		}
+		vsets, err := pol.ValidateSpec(spec, "", severity.SeverityError)
+		if err != nil {
+			t.Fatalf("Policy.ValidateSpec() Error [%s]", err.Error())
+		}
+		vios := vsets.Violations()
+		if len(vios) != tt.wantCount {
+			t.Errorf("Policy.ValidateSpec() Count Mismatch [%v]: want [%d], got [%d]", tt.options, tt.wantCount, len(vios))
+		} else if tt.wantCount > 0 && vios[0].Value != tt.wantValue {
+			t.Errorf("Policy.ValidateSpec() Value Mismatch [%v]: want [%s], got [%s]", tt.options, tt.wantValue, vios[0].Value)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package openapi3lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grokify/mogo/log/severity"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/mogo/type/slicesutil"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+	"github.com/grokify/spectrum/openapi3lint/ruledeclarative"
+)
+
+// Built-in profiles can be used in `PolicyConfig.Extends` in addition to
+// policy file paths.
+const (
+	ProfilePrefix      = "spectrum:"
+	ProfileMinimal     = "spectrum:minimal"
+	ProfileRecommended = "spectrum:recommended"
+)
+
+// ProfileNames returns the names of the built-in profiles.
+func ProfileNames() []string {
+	return []string{ProfileMinimal, ProfileRecommended}
+}
+
+// ProfilePolicyConfig returns the `PolicyConfig` for a built-in profile.
+func ProfilePolicyConfig(name string) (PolicyConfig, error) {
+	polCfg := PolicyConfig{
+		Name:                 name,
+		IncludeStandardRules: true,
+		Rules:                map[string]RuleConfig{}}
+	errorRules := []string{
+		lintutil.RulenameOpSummaryExist,
+		lintutil.RulenameOpResponseSuccessExist,
+		lintutil.RulenameSchemaReferenceHasSchema}
+	warningRules := []string{}
+	switch name {
+	case ProfileMinimal:
+	case ProfileRecommended:
+		errorRules = append(errorRules,
+			lintutil.RulenameDatatypeIntFormatStandardExist,
+			lintutil.RulenameOpIDStyleCamelCase,
+			lintutil.RulenamePathParamStyleCamelCase,
+			lintutil.RulenameSchemaObjectPropsExist)
+		warningRules = append(warningRules,
+			lintutil.RulenameOpSummaryStyleFirstUpperCase,
+			lintutil.RulenameOpResponseDefaultOr500Exist,
+			lintutil.RulenameSchemaHasReference,
+			lintutil.RulenameSchemaPropEnumStylePascalCase,
+			lintutil.RulenameTagStyleFirstUpperCase)
+	default:
+		return polCfg, fmt.Errorf("unknown profile [%s] valid [%s]", name, strings.Join(ProfileNames(), ","))
+	}
+	for _, ruleName := range errorRules {
+		polCfg.Rules[ruleName] = RuleConfig{Severity: severity.SeverityError}
+	}
+	for _, ruleName := range warningRules {
+		polCfg.Rules[ruleName] = RuleConfig{Severity: severity.SeverityWarning}
+	}
+	return polCfg, nil
+}
+
+// RuleSetting is the effective setting of a rule after `extends` has been
+// resolved. `Source` is the policy file or profile that set it and
+// `Overrides` lists the sources of earlier settings it replaced.
+type RuleSetting struct {
+	RuleName  string   `json:"rule"`
+	Severity  string   `json:"severity,omitempty"`
+	Disabled  bool     `json:"disabled,omitempty"`
+	Source    string   `json:"source"`
+	Overrides []string `json:"overrides,omitempty"`
+}
+
+type RuleSettings map[string]RuleSetting
+
+// Slice returns the settings sorted by rule name.
+func (settings RuleSettings) Slice() []RuleSetting {
+	slice := []RuleSetting{}
+	for _, ruleName := range maputil.Keys(settings) {
+		slice = append(slice, settings[ruleName])
+	}
+	return slice
+}
+
+func (settings RuleSettings) set(setting RuleSetting) {
+	if prior, ok := settings[setting.RuleName]; ok {
+		setting.Overrides = append(append(append([]string{}, prior.Overrides...), prior.Source), setting.Overrides...)
+	}
+	settings[setting.RuleName] = setting
+}
+
+// RuleDisabled returns true for severities that disable an inherited rule.
+func RuleDisabled(sev string) bool {
+	switch strings.ToLower(strings.TrimSpace(sev)) {
+	case "off", "false", severity.SeverityDisabled:
+		return true
+	}
+	return false
+}
+
+// Resolve returns the policy config with `Extends` applied. Bases are
+// applied in order, followed by the config itself, so later settings win.
+// A rule without a severity keeps its inherited severity and a rule
+// without options keeps its inherited options. A severity of `off` or
+// `disabled` removes an inherited rule. An explicit `includeStandardRules`
+// overrides the inherited value. Relative base file paths are
+// resolved against the directory of the extending policy file.
+func (polCfg *PolicyConfig) Resolve() (PolicyConfig, RuleSettings, error) {
+	resolved, settings, err := polCfg.resolve(polCfg.source(), []string{})
+	if err != nil {
+		return resolved, settings, err
+	}
+	resolved.xRuleCollections = polCfg.xRuleCollections
+	return resolved, settings, nil
+}
+
+func (polCfg *PolicyConfig) source() string {
+	if len(polCfg.filename) > 0 {
+		return polCfg.filename
+	} else if len(polCfg.Name) > 0 {
+		return polCfg.Name
+	}
+	return "(inline)"
+}
+
+func (polCfg *PolicyConfig) resolve(source string, stack []string) (PolicyConfig, RuleSettings, error) {
+	key := source
+	if !strings.HasPrefix(source, ProfilePrefix) && len(polCfg.filename) > 0 {
+		if abs, err := filepath.Abs(polCfg.filename); err == nil {
+			key = abs
+		}
+	}
+	for i, prior := range stack {
+		if prior == key {
+			return PolicyConfig{}, nil, fmt.Errorf("policy extends cycle [%s]",
+				strings.Join(append(append([]string{}, stack[i:]...), key), " -> "))
+		}
+	}
+	stack = append(stack, key)
+
+	resolved := PolicyConfig{
+		Name:        polCfg.Name,
+		Version:     polCfg.Version,
+		LastUpdated: polCfg.LastUpdated,
+		Rules:       map[string]RuleConfig{},
+		filename:    polCfg.filename}
+	settings := RuleSettings{}
+
+	for _, ext := range polCfg.Extends {
+		base, baseSource, err := polCfg.extendsConfig(ext)
+		if err != nil {
+			return resolved, settings, err
+		}
+		baseResolved, baseSettings, err := base.resolve(baseSource, stack)
+		if err != nil {
+			return resolved, settings, err
+		}
+		if baseResolved.standardRulesSet() {
+			resolved.IncludeStandardRules = baseResolved.IncludeStandardRules
+			resolved.includeStandardRulesSet = true
+		}
+		for _, ruleName := range maputil.Keys(baseResolved.Rules) {
+			resolved.Rules[ruleName] = baseResolved.Rules[ruleName]
+		}
+		resolved.NonStandardRules = append(resolved.NonStandardRules, baseResolved.NonStandardRules...)
+		for _, declCfg := range baseResolved.DeclarativeRules {
+			resolved.upsertDeclarativeRule(declCfg)
+		}
+		for _, ruleName := range maputil.Keys(baseSettings) {
+			if baseSettings[ruleName].Disabled {
+				resolved.disableRule(ruleName)
+			}
+			settings.set(baseSettings[ruleName])
+		}
+	}
+
+	if polCfg.standardRulesSet() {
+		resolved.IncludeStandardRules = polCfg.IncludeStandardRules
+		resolved.includeStandardRulesSet = true
+	}
+	resolved.NonStandardRules = append(resolved.NonStandardRules, polCfg.NonStandardRules...)
+	for _, declCfg := range polCfg.DeclarativeRules {
+		resolved.upsertDeclarativeRule(declCfg)
+		settings.set(RuleSetting{RuleName: declCfg.Name, Severity: declCfg.Severity, Source: source})
+	}
+	for _, ruleName := range maputil.Keys(polCfg.Rules) {
+		ruleCfg := polCfg.Rules[ruleName]
+		if RuleDisabled(ruleCfg.Severity) {
+			resolved.disableRule(ruleName)
+			settings.set(RuleSetting{RuleName: ruleName, Disabled: true, Source: source})
+			continue
+		}
+		if idx := resolved.declarativeRuleIndex(ruleName); idx >= 0 {
+			if len(ruleCfg.Severity) > 0 {
+				resolved.DeclarativeRules[idx].Severity = ruleCfg.Severity
+			}
+			settings.set(RuleSetting{RuleName: ruleName, Severity: resolved.DeclarativeRules[idx].Severity, Source: source})
+			continue
+		}
+		if prior, ok := resolved.Rules[ruleName]; ok {
+			if len(ruleCfg.Severity) == 0 {
+				ruleCfg.Severity = prior.Severity
+			}
+			if ruleCfg.Options == nil {
+				ruleCfg.Options = prior.Options
+			}
+		}
+		resolved.Rules[ruleName] = ruleCfg
+		settings.set(RuleSetting{RuleName: ruleName, Severity: ruleCfg.Severity, Source: source})
+	}
+	resolved.NonStandardRules = slicesutil.Dedupe(resolved.NonStandardRules)
+	sort.Strings(resolved.NonStandardRules)
+	return resolved, settings, nil
+}
+
+// extendsConfig loads a base policy from a profile name or file path.
+func (polCfg *PolicyConfig) extendsConfig(ext string) (PolicyConfig, string, error) {
+	ext = strings.TrimSpace(ext)
+	if strings.HasPrefix(ext, ProfilePrefix) {
+		base, err := ProfilePolicyConfig(ext)
+		return base, ext, err
+	}
+	if !filepath.IsAbs(ext) && len(polCfg.filename) > 0 {
+		ext = filepath.Join(filepath.Dir(polCfg.filename), ext)
+	}
+	ext = filepath.Clean(ext)
+	base, err := NewPolicyConfigFile(ext)
+	if err != nil {
+		return base, ext, fmt.Errorf("policy extends [%s]: %w", ext, err)
+	}
+	return base, ext, nil
+}
+
+func (polCfg *PolicyConfig) disableRule(ruleName string) {
+	delete(polCfg.Rules, ruleName)
+	if idx := polCfg.declarativeRuleIndex(ruleName); idx >= 0 {
+		polCfg.DeclarativeRules = append(polCfg.DeclarativeRules[:idx], polCfg.DeclarativeRules[idx+1:]...)
+	}
+}
+
+func (polCfg *PolicyConfig) declarativeRuleIndex(ruleName string) int {
+	for i, declCfg := range polCfg.DeclarativeRules {
+		if declCfg.Name == ruleName {
+			return i
+		}
+	}
+	return -1
+}
+
+func (polCfg *PolicyConfig) upsertDeclarativeRule(declCfg ruledeclarative.RuleConfig) {
+	if idx := polCfg.declarativeRuleIndex(declCfg.Name); idx >= 0 {
+		polCfg.DeclarativeRules[idx] = declCfg
+	} else {
+		polCfg.DeclarativeRules = append(polCfg.DeclarativeRules, declCfg)
+	}
+}
@@ -0,0 +1,124 @@
+package openapi3lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const policyBaseYAML = `name: base
+extends:
+  - spectrum:minimal
+rules:
+  operation-operationid-style-camelcase:
+    severity: error
+  operation-response-success-exist:
+    severity: disabled
+`
+
+const policyChildJSON = `{"name":"child","extends":["base.yaml"],
+"rules":{"operation-operationid-style-camelcase":{"severity":"warning"}}}`
+
+var policyExtendsTests = []struct {
+	ruleName string
+	severity string
+	disabled bool
+	source   string
+	count    int
+}{
+	{"operation-operationid-style-camelcase", "warning", false, "child.json", 1},
+	{"operation-response-success-exist", "", true, "base.yaml", 1},
+	{"operation-summary-exist", "err", false, ProfileMinimal, 0},
+}
+
+// TestPolicyConfigExtends ensures `extends` is resolved with overrides, disabled rules and sources.
+func TestPolicyConfigExtends(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(policyBaseYAML), 0600); err != nil {
+		t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "child.json"), []byte(policyChildJSON), 0600); err != nil {
+		t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+	}
+	polCfg, err := NewPolicyConfigFile(filepath.Join(dir, "child.json"))
+	if err != nil {
+		t.Fatalf("openapi3lint.NewPolicyConfigFile() Error [%s]", err.Error())
+	}
+	pol, err := polCfg.Policy()
+	if err != nil {
+		t.Fatalf("PolicyConfig.Policy() Error [%s]", err.Error())
+	}
+	if len(pol.RuleNames()) != 3 {
+		t.Errorf("PolicyConfig.Policy() Count Mismatch: want [3], got [%d]", len(pol.RuleNames()))
+	}
+	settings := pol.ruleSettings
+	for _, tt := range policyExtendsTests {
+		setting := settings[tt.ruleName]
+		if setting.Severity != tt.severity || setting.Disabled != tt.disabled ||
+			filepath.Base(setting.Source) != tt.source || len(setting.Overrides) != tt.count {
+			t.Errorf("Policy.RuleSettings() Mismatch [%s]: want [%s, %v, %s, %d], got [%s, %v, %s, %d]",
+				tt.ruleName, tt.severity, tt.disabled, tt.source, tt.count,
+				setting.Severity, setting.Disabled, filepath.Base(setting.Source), len(setting.Overrides))
+		}
+	}
+}
+
+// TestPolicyConfigExtendsCycle ensures `extends` cycles return an error.
+func TestPolicyConfigExtendsCycle(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("extends: [b.yaml]\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("extends: [a.yaml]\n"), 0600); err != nil {
+		t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+	}
+	polCfg, err := NewPolicyConfigFile(filepath.Join(dir, "a.yaml"))
+	if err != nil {
+		t.Fatalf("openapi3lint.NewPolicyConfigFile() Error [%s]", err.Error())
+	}
+	_, err = polCfg.Policy()
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("PolicyConfig.Policy() Mismatch: want cycle error, got [%v]", err)
+	}
+}
+
+// TestPolicyConfigExtendsIncludeStandardRules ensures an explicit
+// `includeStandardRules` overrides the inherited value.
+func TestPolicyConfigExtendsIncludeStandardRules(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(policyBaseYAML), 0600); err != nil {
+		t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+	}
+	for _, tt := range []struct {
+		filename  string
+		child     string
+		wantStd   bool
+		wantRules int
+	}{
+		{"child.json", `{"extends":["base.yaml"]}`, true, 3},
+		{"child.json", `{"extends":["base.yaml"],"includeStandardRules":false}`, false, 0},
+		{"child.json", `{"extends":["base.yaml"],"includeStandardRules":true}`, true, 3},
+		{"child.yaml", "extends: [base.yaml]\nincludeStandardRules: false\n", false, 0},
+	} {
+		if err := os.WriteFile(filepath.Join(dir, tt.filename), []byte(tt.child), 0600); err != nil {
+			t.Fatalf("os.WriteFile() Error [%s]", err.Error())
+		}
+		polCfg, err := NewPolicyConfigFile(filepath.Join(dir, tt.filename))
+		if err != nil {
+			t.Fatalf("openapi3lint.NewPolicyConfigFile() Error [%s]", err.Error())
+		}
+		resolved, _, err := polCfg.Resolve()
+		if err != nil {
+			t.Fatalf("PolicyConfig.Resolve() Error [%s]", err.Error())
+		}
+		pol, err := polCfg.Policy()
+		if err != nil {
+			t.Fatalf("PolicyConfig.Policy() Error [%s]", err.Error())
+		}
+		if resolved.IncludeStandardRules != tt.wantStd || len(pol.RuleNames()) != tt.wantRules {
+			t.Errorf("PolicyConfig.Resolve() Mismatch [%s]: want [%v, %d], got [%v, %d]",
+				tt.child, tt.wantStd, tt.wantRules, resolved.IncludeStandardRules, len(pol.RuleNames()))
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package openapi3lint
+
+import (
+	"regexp"
+
+	"github.com/grokify/mogo/errors/errorsutil"
+	"github.com/grokify/mogo/log/severity"
+	"github.com/grokify/mogo/path/filepathutil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+)
+
+// FixSpec applies the fixes of all policy rules that implement `Fixer` and
+// are included by `filterSeverity`. The input spec is not modified. A copy
+// of the spec with the fixes applied is returned along with the list of
+// changes made. Rules are applied in rule name order.
+func (pol *Policy) FixSpec(spec *openapi3.Spec, pointerBase, filterSeverity string) (*openapi3.Spec, []lintutil.PolicyFix, error) {
+	fixes := []lintutil.PolicyFix{}
+	sm := openapi3.SpecMore{Spec: spec}
+	specFixed, err := sm.Clone()
+	if err != nil {
+		return nil, fixes, err
+	} else if specFixed == nil {
+		return nil, fixes, openapi3.ErrSpecNotSet
+	}
+	for _, ruleName := range pol.RuleNames() {
+		policyRule := pol.policyRules[ruleName]
+		fixer, ok := policyRule.Rule.(Fixer)
+		if !ok {
+			continue
+		}
+		inclRule, err := severity.SeverityInclude(filterSeverity, policyRule.Severity)
+		if err != nil {
+			return specFixed, fixes, err
+		} else if !inclRule {
+			continue
+		}
+		ruleFixes, err := fixer.Fix(specFixed, pointerBase)
+		if err != nil {
+			return specFixed, fixes, errorsutil.Wrapf(err, "fix rule [%s]", ruleName)
+		}
+		fixes = append(fixes, ruleFixes...)
+	}
+	return specFixed, fixes, nil
+}
+
+// FixRuleNames returns the names of policy rules that implement `Fixer`.
+func (pol *Policy) FixRuleNames() []string {
+	ruleNames := []string{}
+	for _, ruleName := range pol.RuleNames() {
+		if _, ok := pol.policyRules[ruleName].Rule.(Fixer); ok {
+			ruleNames = append(ruleNames, ruleName)
+		}
+	}
+	return ruleNames
+}
+
+var rxYAMLExtension = regexp.MustCompile(`(?i)\.ya?ml\s*$`)
+
+// FixSpecFiles applies `FixSpec()` to each file and writes the corrected
+// spec back to the same file using JSON or YAML based on the file extension.
+// Files without fixes are not rewritten.
+func (pol *Policy) FixSpecFiles(filterSeverity string, specfiles []string) ([]lintutil.PolicyFix, error) {
+	fixes := []lintutil.PolicyFix{}
+	if len(specfiles) == 0 {
+		return fixes, ErrNoSpecFiles
+	}
+	severityLevel, err := severity.Parse(filterSeverity)
+	if err != nil {
+		return fixes, err
+	}
+	for _, file := range specfiles {
+		spec, err := openapi3.ReadFile(file, false)
+		if err != nil {
+			return fixes, err
+		}
+		specFixed, fileFixes, err := pol.FixSpec(spec, filepathutil.FilepathLeaf(file), severityLevel)
+		if err != nil {
+			return fixes, err
+		} else if len(fileFixes) == 0 {
+			continue
+		}
+		fixes = append(fixes, fileFixes...)
+		sm := openapi3.SpecMore{Spec: specFixed}
+		if rxYAMLExtension.MatchString(file) {
+			err = sm.WriteFileYAML(file, 0600)
+		} else {
+			err = sm.WriteFileJSON(file, 0600, "", "  ")
+		}
+		if err != nil {
+			return fixes, err
+		}
+	}
+	return fixes, nil
+}
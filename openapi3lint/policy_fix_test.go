@@ -0,0 +1,128 @@
+package openapi3lint
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/grokify/mogo/log/severity"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+)
+
+const fixSpecJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},
+"tags":[{"name":"users"}],
+"paths":{
+  "/users/{user_id}":{"get":{"operationId":"get_user","summary":"get a user","tags":["users"],
+    "parameters":[{"name":"user_id","in":"path","required":true,"schema":{"type":"string"}}],
+    "responses":{"200":{"description":"OK"}}}},
+  "/groups/{groupId}/members/{member_id}":{"get":{"operationId":"listMembers","summary":"List members",
+    "parameters":[
+      {"name":"groupId","in":"path","required":true,"schema":{"type":"string"}},
+      {"$ref":"#/components/parameters/MemberId"}],
+    "responses":{"200":{"description":"OK"}}}}},
+"components":{
+  "parameters":{"MemberId":{"name":"member_id","in":"path","required":true,"schema":{"type":"string"}}},
+  "schemas":{
+    "User":{"type":"object","properties":{
+      "status":{"type":"string","enum":["active","on_hold"]},
+      "role":{"type":"string","enum":["admin"]}}},
+    "Account":{"type":"object","properties":{"state":{"type":"string","enum":["closed"]}}}}}}`
+
+// fixSpecTests lists fixes sorted unless `ordered` is set, in which case
+// they are listed in the order the rule reports them.
+var fixSpecTests = []struct {
+	ruleName  string
+	ordered   bool
+	wantFixes []string
+}{
+	{lintutil.RulenameOpIDStyleCamelCase, false, []string{
+		"spec.json#/paths/~1users~1{user_id}/get/operationId get_user getUser"}},
+	{lintutil.RulenameOpSummaryStyleFirstUpperCase, false, []string{
+		"spec.json#/paths/~1users~1{user_id}/get/summary get a user Get a user"}},
+	{lintutil.RulenamePathParamStyleCamelCase, false, []string{
+		"spec.json#/components/parameters/MemberId/name member_id memberId",
+		"spec.json#/paths/~1groups~1{groupId}~1members~1{member_id} /groups/{groupId}/members/{member_id} /groups/{groupId}/members/{memberId}",
+		"spec.json#/paths/~1users~1{user_id} /users/{user_id} /users/{userId}",
+		"spec.json#/paths/~1users~1{user_id}/get/parameters/0/name user_id userId"}},
+	{lintutil.RulenameSchemaPropEnumStylePascalCase, true, []string{
+		"spec.json#/components/schemas/Account/properties/state/enum/0 closed Closed",
+		"spec.json#/components/schemas/User/properties/role/enum/0 admin Admin",
+		"spec.json#/components/schemas/User/properties/status/enum/0 active Active",
+		"spec.json#/components/schemas/User/properties/status/enum/1 on_hold OnHold"}},
+	{lintutil.RulenameTagStyleFirstUpperCase, true, []string{
+		"spec.json#/tags/0/name users Users",
+		"spec.json#/paths/~1users~1{user_id}/get/tags/0 users Users"}},
+}
+
+// TestFixSpec ensures each `Fixer` rule reports its changes, leaves the
+// input spec unchanged and fixes its violations.
+func TestFixSpec(t *testing.T) {
+	for _, tt := range fixSpecTests {
+		spec, err := openapi3.Parse([]byte(fixSpecJSON))
+		if err != nil {
+			t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+		}
+		pol := NewPolicy()
+		rule, err := NewRuleCollectionStandard().RuleWithOptions(tt.ruleName, nil)
+		if err != nil {
+			t.Fatalf("RuleCollectionStandard.RuleWithOptions(%s) Error [%s]", tt.ruleName, err.Error())
+		}
+		if err := pol.AddRule(rule, severity.SeverityError, true); err != nil {
+			t.Fatalf("Policy.AddRule(%s) Error [%s]", tt.ruleName, err.Error())
+		}
+		if names := pol.FixRuleNames(); len(names) != 1 || names[0] != tt.ruleName {
+			t.Errorf("Policy.FixRuleNames() Mismatch: want [%s], got [%s]", tt.ruleName, strings.Join(names, ","))
+		}
+
+		specFixed, fixes, err := pol.FixSpec(spec, "spec.json", severity.SeverityError)
+		if err != nil {
+			t.Fatalf("Policy.FixSpec(%s) Error [%s]", tt.ruleName, err.Error())
+		}
+		got := []string{}
+		for _, fix := range fixes {
+			if fix.RuleName != tt.ruleName {
+				t.Errorf("Policy.FixSpec(%s) Mismatch: fix rule name [%s]", tt.ruleName, fix.RuleName)
+			}
+			got = append(got, strings.Join([]string{fix.Location, fix.Before, fix.After}, " "))
+		}
+		if !tt.ordered {
+			sort.Strings(got)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.wantFixes, "\n") {
+			t.Errorf("Policy.FixSpec(%s) Mismatch: want [%s], got [%s]", tt.ruleName,
+				strings.Join(tt.wantFixes, "; "), strings.Join(got, "; "))
+		}
+
+		specOrig, err := openapi3.Parse([]byte(fixSpecJSON))
+		if err != nil {
+			t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+		}
+		if !specJSONEqual(t, spec, specOrig) {
+			t.Errorf("Policy.FixSpec(%s) Mismatch: input spec was modified", tt.ruleName)
+		}
+		if vsets, err := pol.ValidateSpec(specFixed, "spec.json", severity.SeverityError); err != nil {
+			t.Errorf("Policy.ValidateSpec(%s) Error [%s]", tt.ruleName, err.Error())
+		} else if vios := vsets.Violations(); len(vios) > 0 {
+			t.Errorf("Policy.FixSpec(%s) Mismatch: want no violations after fix, got [%d] at [%s]", tt.ruleName, len(vios), vios[0].Location)
+		}
+
+		if _, fixes, err := pol.FixSpec(spec, "spec.json", severity.SeverityCritical); err != nil || len(fixes) > 0 {
+			t.Errorf("Policy.FixSpec(%s) Mismatch: want no fixes below severity filter, got [%d] err [%v]", tt.ruleName, len(fixes), err)
+		}
+	}
+}
+
+func specJSONEqual(t *testing.T, spec1, spec2 *openapi3.Spec) bool {
+	t.Helper()
+	b1, err := spec1.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Spec.MarshalJSON() Error [%s]", err.Error())
+	}
+	b2, err := spec2.MarshalJSON()
+	if err != nil {
+		t.Fatalf("Spec.MarshalJSON() Error [%s]", err.Error())
+	}
+	return string(b1) == string(b2)
+}
@@ -0,0 +1,31 @@
+package openapi3lint
+
+import (
+	"fmt"
+
+	"github.com/grokify/mogo/errors/errorsutil"
+	"github.com/grokify/spectrum/openapi3lint/rulespectral"
+)
+
+// AddSpectralRuleset adds the supported rules of a Spectral ruleset to the
+// policy. The returned report lists rules and functions that were not
+// imported.
+func (pol *Policy) AddSpectralRuleset(rs rulespectral.Ruleset) (*rulespectral.ImportReport, error) {
+	rules, rpt := rs.Compile()
+	for _, rule := range rules {
+		if err := pol.AddRule(rule, rule.Severity(), true); err != nil {
+			return rpt, errorsutil.Wrap(err, fmt.Sprintf("Policy.AddRule() [%s]", rule.Name()))
+		}
+	}
+	return rpt, nil
+}
+
+// AddSpectralRulesetFile adds the rules of a Spectral ruleset file such
+// as `.spectral.yaml`.
+func (pol *Policy) AddSpectralRulesetFile(filename string) (*rulespectral.ImportReport, error) {
+	rs, err := rulespectral.ReadRulesetFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return pol.AddSpectralRuleset(rs)
+}
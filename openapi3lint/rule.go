@@ -19,6 +19,12 @@ type Rule interface {
 	ProcessOperation(spec *openapi3.Spec, op *oas3.Operation, opPointer, path, method string) []lintutil.PolicyViolation
 }
 
+// Fixer is an optional interface for a `Rule` that can correct its own
+// violations. `Fix` modifies the spec in place and returns the changes made.
+type Fixer interface {
+	Fix(spec *openapi3.Spec, pointerBase string) ([]lintutil.PolicyFix, error)
+}
+
 type PolicyRule struct {
 	Rule     Rule
 	Severity string
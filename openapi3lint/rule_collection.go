@@ -8,3 +8,20 @@ type RuleCollection interface {
 	RuleExists(ruleName string) bool
 	Rule(ruleName string) (Rule, error)
 }
+
+// RuleCollectionOptions is an optional interface for a `RuleCollection`
+// that can create rules configured with `RuleConfig.Options`.
+type RuleCollectionOptions interface {
+	RuleWithOptions(ruleName string, opts map[string]string) (Rule, error)
+}
+
+// collectionRule returns a rule from a collection using options when
+// supported by the collection.
+func collectionRule(collection RuleCollection, ruleName string, opts map[string]string) (Rule, error) {
+	if len(opts) > 0 {
+		if collOpts, ok := collection.(RuleCollectionOptions); ok {
+			return collOpts.RuleWithOptions(ruleName, opts)
+		}
+	}
+	return collection.Rule(ruleName)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/grokify/spectrum/openapi3lint/lintutil"
 	"github.com/grokify/spectrum/openapi3lint/ruleintstdformat"
 	"github.com/grokify/spectrum/openapi3lint/ruleopidstyle"
+	"github.com/grokify/spectrum/openapi3lint/ruleopresponse"
 	"github.com/grokify/spectrum/openapi3lint/ruleopsummaryexist"
 	"github.com/grokify/spectrum/openapi3lint/ruleopsummarystylefirstuppercase"
 	"github.com/grokify/spectrum/openapi3lint/rulepathparamstyle"
@@ -54,6 +55,10 @@ func (std RuleCollectionStandard) RuleNames() []string {
 		lintutil.RulenameOpIDStyleSnakeCase,
 		lintutil.RulenameOpSummaryExist,
 		lintutil.RulenameOpSummaryStyleFirstUpperCase,
+		lintutil.RulenameOpResponseSuccessExist,
+		lintutil.RulenameOpResponseErrorSchemaShared,
+		lintutil.RulenameOpResponseDefaultOr500Exist,
+		lintutil.RulenameOpResponse201LocationHeaderExist,
 		lintutil.RulenamePathParamStyleCamelCase,
 		lintutil.RulenamePathParamStyleKebabCase,
 		lintutil.RulenamePathParamStylePascalCase,
@@ -72,6 +77,12 @@ func (std RuleCollectionStandard) RuleNames() []string {
 }
 
 func (std RuleCollectionStandard) Rule(name string) (Rule, error) {
+	return std.RuleWithOptions(name, nil)
+}
+
+// RuleWithOptions returns a rule configured with `RuleConfig.Options`. Options
+// are ignored by rules that do not support them.
+func (std RuleCollectionStandard) RuleWithOptions(name string, opts map[string]string) (Rule, error) {
 	name = strings.ToLower(strings.TrimSpace(name))
 	switch name {
 	case lintutil.RulenameDatatypeIntFormatStandardExist:
@@ -91,6 +102,12 @@ func (std RuleCollectionStandard) Rule(name string) (Rule, error) {
 	case lintutil.RulenameOpSummaryStyleFirstUpperCase:
 		return ruleopsummarystylefirstuppercase.NewRule(), nil
 
+	case lintutil.RulenameOpResponseSuccessExist,
+		lintutil.RulenameOpResponseErrorSchemaShared,
+		lintutil.RulenameOpResponseDefaultOr500Exist,
+		lintutil.RulenameOpResponse201LocationHeaderExist:
+		return ruleopresponse.NewRule(name, opts)
+
 	case lintutil.RulenamePathParamStyleCamelCase:
 		return rulepathparamstyle.NewRule(stringcase.CamelCase)
 	case lintutil.RulenamePathParamStyleKebabCase:
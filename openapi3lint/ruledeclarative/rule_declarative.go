@@ -0,0 +1,282 @@
+// ruledeclarative compiles declarative rules from a policy file into rules.
+package ruledeclarative
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/encoding/jsonpointer"
+	"github.com/grokify/mogo/text/stringcase"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	TargetOperations       = "operations"
+	TargetParameters       = "parameters"
+	TargetPaths            = "paths"
+	TargetSchemaProperties = "schema-properties"
+	TargetTags             = "tags"
+)
+
+// RuleConfig is a declarative rule definition in a policy file.
+type RuleConfig struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Severity    string    `json:"severity"`
+	Target      string    `json:"target"`
+	Selector    string    `json:"selector"`
+	Assert      Assertion `json:"assert"`
+}
+
+// Assertion is a set of checks applied to each selected value. All
+// non-empty checks must pass. `Present` asserts presence when `true` and
+// absence when `false`.
+type Assertion struct {
+	Pattern    string   `json:"pattern,omitempty"`
+	NotPattern string   `json:"notPattern,omitempty"`
+	Enum       []string `json:"enum,omitempty"`
+	Casing     string   `json:"casing,omitempty"`
+	MinLength  *int     `json:"minLength,omitempty"`
+	MaxLength  *int     `json:"maxLength,omitempty"`
+	Present    *bool    `json:"present,omitempty"`
+}
+
+type RuleDeclarative struct {
+	cfg        RuleConfig
+	selector   Selector
+	rxPattern  *regexp.Regexp
+	rxNot      *regexp.Regexp
+	stringCase string
+}
+
+func NewRule(cfg RuleConfig) (RuleDeclarative, error) {
+	cfg.Name = strings.TrimSpace(cfg.Name)
+	cfg.Target = strings.ToLower(strings.TrimSpace(cfg.Target))
+	rule := RuleDeclarative{cfg: cfg}
+	if len(cfg.Name) == 0 {
+		return rule, errors.New("declarative rule has no name")
+	}
+	switch cfg.Target {
+	case TargetOperations, TargetParameters, TargetPaths, TargetSchemaProperties, TargetTags:
+	default:
+		return rule, fmt.Errorf("declarative rule [%s] has unknown target [%s]", cfg.Name, cfg.Target)
+	}
+	sel, err := ParseSelector(cfg.Selector)
+	if err != nil {
+		return rule, fmt.Errorf("declarative rule [%s]: %w", cfg.Name, err)
+	}
+	rule.selector = sel
+	if len(cfg.Assert.Pattern) > 0 {
+		if rule.rxPattern, err = regexp.Compile(cfg.Assert.Pattern); err != nil {
+			return rule, fmt.Errorf("declarative rule [%s] pattern: %w", cfg.Name, err)
+		}
+	}
+	if len(cfg.Assert.NotPattern) > 0 {
+		if rule.rxNot, err = regexp.Compile(cfg.Assert.NotPattern); err != nil {
+			return rule, fmt.Errorf("declarative rule [%s] notPattern: %w", cfg.Name, err)
+		}
+	}
+	if len(cfg.Assert.Casing) > 0 {
+		if rule.stringCase, err = stringcase.Parse(cfg.Assert.Casing); err != nil {
+			return rule, fmt.Errorf("declarative rule [%s] casing: %w", cfg.Name, err)
+		}
+	}
+	return rule, nil
+}
+
+func (rule RuleDeclarative) Name() string {
+	return rule.cfg.Name
+}
+
+func (rule RuleDeclarative) Scope() string {
+	return lintutil.ScopeSpecification
+}
+
+func (rule RuleDeclarative) ProcessOperation(spec *openapi3.Spec, op *oas3.Operation, opPointer, path, method string) []lintutil.PolicyViolation {
+	return nil
+}
+
+func (rule RuleDeclarative) ProcessSpec(spec *openapi3.Spec, pointerBase string) []lintutil.PolicyViolation {
+	vios := []lintutil.PolicyViolation{}
+	if spec == nil {
+		return vios
+	}
+	for _, item := range targetItems(spec, rule.cfg.Target) {
+		vios = append(vios, rule.processItem(pointerBase, item)...)
+	}
+	return vios
+}
+
+func (rule RuleDeclarative) processItem(pointerBase string, item targetItem) []lintutil.PolicyViolation {
+	vios := []lintutil.PolicyViolation{}
+	itemPointer := pointerBase + item.pointer
+	var vals []SelectedValue
+	if rule.selector.IsKey() {
+		vals = []SelectedValue{{Value: item.key}}
+	} else {
+		selVals, err := rule.selector.Select(item.value)
+		if err != nil {
+			return append(vios, rule.violation(itemPointer, err.Error(), ""))
+		}
+		vals = selVals
+	}
+	if present := rule.cfg.Assert.Present; present != nil {
+		if *present && len(vals) == 0 {
+			vios = append(vios, rule.violation(itemPointer+rule.selector.Pointer(), "value not present", ""))
+		} else if !*present {
+			for _, val := range vals {
+				vios = append(vios, rule.violation(itemPointer+val.Pointer, "value present", fmt.Sprintf("%v", val.Value)))
+			}
+		}
+	}
+	for _, val := range vals {
+		valString, ok := valueString(val.Value)
+		if !ok {
+			continue
+		}
+		if msg := rule.check(valString); len(msg) > 0 {
+			vios = append(vios, rule.violation(itemPointer+val.Pointer, msg, valString))
+		}
+	}
+	return vios
+}
+
+// check returns a message for the first failed assertion or an empty string.
+func (rule RuleDeclarative) check(s string) string {
+	a := rule.cfg.Assert
+	if rule.rxPattern != nil && !rule.rxPattern.MatchString(s) {
+		return fmt.Sprintf("does not match pattern [%s]", a.Pattern)
+	}
+	if rule.rxNot != nil && rule.rxNot.MatchString(s) {
+		return fmt.Sprintf("matches disallowed pattern [%s]", a.NotPattern)
+	}
+	if len(a.Enum) > 0 && !slices.Contains(a.Enum, s) {
+		return fmt.Sprintf("not in enum [%s]", strings.Join(a.Enum, ","))
+	}
+	if len(rule.stringCase) > 0 {
+		if isCase, err := stringcase.IsCase(rule.stringCase, s); err != nil || !isCase {
+			return fmt.Sprintf("not %s", rule.stringCase)
+		}
+	}
+	if a.MinLength != nil && len(s) < *a.MinLength {
+		return fmt.Sprintf("shorter than minLength [%d]", *a.MinLength)
+	}
+	if a.MaxLength != nil && len(s) > *a.MaxLength {
+		return fmt.Sprintf("longer than maxLength [%d]", *a.MaxLength)
+	}
+	return ""
+}
+
+func (rule RuleDeclarative) violation(location, msg, value string) lintutil.PolicyViolation {
+	if len(rule.cfg.Description) > 0 {
+		msg = rule.cfg.Description + ": " + msg
+	}
+	return lintutil.PolicyViolation{
+		RuleName:  rule.Name(),
+		RuleType:  "declarative",
+		Violation: msg,
+		Location:  location,
+		Value:     value}
+}
+
+func valueString(v any) (string, bool) {
+	switch val := v.(type) {
+	case string:
+		return val, true
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(val), true
+	}
+	return "", false
+}
+
+type targetItem struct {
+	key     string
+	pointer string
+	value   any
+}
+
+func targetItems(spec *openapi3.Spec, target string) []targetItem {
+	items := []targetItem{}
+	switch target {
+	case TargetOperations, TargetParameters:
+		openapi3.VisitOperations(spec, func(path, method string, op *oas3.Operation) {
+			if op == nil {
+				return
+			}
+			opPointer := jsonpointer.PointerSubEscapeAll("#/paths/%s/%s", path, strings.ToLower(method))
+			if target == TargetOperations {
+				items = append(items, targetItem{
+					key:     op.OperationID,
+					pointer: opPointer,
+					value:   op})
+				return
+			}
+			for i, paramRef := range op.Parameters {
+				if paramRef == nil || paramRef.Value == nil {
+					continue
+				}
+				items = append(items, targetItem{
+					key:     paramRef.Value.Name,
+					pointer: fmt.Sprintf("%s/parameters/%d", opPointer, i),
+					value:   paramRef.Value})
+			}
+		})
+		if target == TargetParameters && spec.Components != nil {
+			for _, paramName := range maputil.Keys(spec.Components.Parameters) {
+				paramRef := spec.Components.Parameters[paramName]
+				if paramRef == nil || paramRef.Value == nil {
+					continue
+				}
+				items = append(items, targetItem{
+					key:     paramRef.Value.Name,
+					pointer: jsonpointer.PointerSubEscapeAll("#/components/parameters/%s", paramName),
+					value:   paramRef.Value})
+			}
+		}
+	case TargetPaths:
+		if spec.Paths != nil {
+			pathsMap := spec.Paths.Map()
+			for _, path := range maputil.Keys(pathsMap) {
+				items = append(items, targetItem{
+					key:     path,
+					pointer: jsonpointer.PointerSubEscapeAll("#/paths/%s", path),
+					value:   pathsMap[path]})
+			}
+		}
+	case TargetSchemaProperties:
+		if spec.Components != nil {
+			for _, schName := range maputil.Keys(spec.Components.Schemas) {
+				schRef := spec.Components.Schemas[schName]
+				if schRef == nil || schRef.Value == nil {
+					continue
+				}
+				for _, propName := range maputil.Keys(schRef.Value.Properties) {
+					items = append(items, targetItem{
+						key:     propName,
+						pointer: jsonpointer.PointerSubEscapeAll("#/components/schemas/%s/properties/%s", schName, propName),
+						value:   schRef.Value.Properties[propName]})
+				}
+			}
+		}
+	case TargetTags:
+		for i, tag := range spec.Tags {
+			if tag == nil {
+				continue
+			}
+			items = append(items, targetItem{
+				key:     tag.Name,
+				pointer: fmt.Sprintf("#/tags/%d", i),
+				value:   tag})
+		}
+	}
+	return items
+}
@@ -0,0 +1,70 @@
+package ruledeclarative
+
+import (
+	"testing"
+
+	"github.com/grokify/spectrum/openapi3"
+)
+
+const specJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},
+"tags":[{"name":"Users"},{"name":"accounts"}],
+"paths":{
+  "/users/{userId}":{"get":{"operationId":"getUser","x-api-group":"public",
+    "parameters":[{"name":"userId","in":"path","required":true,"schema":{"type":"string"}}],
+    "responses":{"200":{"description":"OK"}}}},
+  "/Accounts":{"get":{"operationId":"list_accounts","responses":{"200":{"description":"OK"}}}}},
+"components":{"schemas":{"User":{"type":"object","properties":{"id":{"type":"string","description":"ID"}}}}}}`
+
+func intPtr(i int) *int    { return &i }
+func boolPtr(b bool) *bool { return &b }
+
+var ruleDeclarativeTests = []struct {
+	cfg       RuleConfig
+	locations []string
+}{
+	{RuleConfig{Name: "op-id-camel", Target: TargetOperations, Selector: "$.operationId",
+		Assert: Assertion{Casing: "camelCase"}},
+		[]string{"#/paths/~1Accounts/get/operationId"}},
+	{RuleConfig{Name: "op-api-group", Target: TargetOperations, Selector: "$.x-api-group",
+		Assert: Assertion{Present: boolPtr(true), Enum: []string{"public", "internal"}}},
+		[]string{"#/paths/~1Accounts/get/x-api-group"}},
+	{RuleConfig{Name: "path-lowercase", Target: TargetPaths, Selector: SelectorKey,
+		Assert: Assertion{Pattern: `^(/[a-z0-9-]+|/\{[A-Za-z]+\})+$`}},
+		[]string{"#/paths/~1Accounts"}},
+	{RuleConfig{Name: "tag-pascal", Target: TargetTags, Selector: "$.name",
+		Assert: Assertion{Casing: "pascalCase"}},
+		[]string{"#/tags/1/name"}},
+	{RuleConfig{Name: "prop-desc-length", Target: TargetSchemaProperties, Selector: "$.description",
+		Assert: Assertion{MinLength: intPtr(3)}},
+		[]string{"#/components/schemas/User/properties/id/description"}},
+	{RuleConfig{Name: "param-no-schema-format", Target: TargetParameters, Selector: "$.schema.format",
+		Assert: Assertion{Present: boolPtr(false)}},
+		[]string{}},
+}
+
+// TestRuleDeclarative ensures declarative rules report the expected locations.
+func TestRuleDeclarative(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(specJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	for _, tt := range ruleDeclarativeTests {
+		rule, err := NewRule(tt.cfg)
+		if err != nil {
+			t.Fatalf("ruledeclarative.NewRule(\"%s\") Error [%s]", tt.cfg.Name, err.Error())
+		}
+		vios := rule.ProcessSpec(spec, "")
+		if len(vios) != len(tt.locations) {
+			t.Errorf("RuleDeclarative.ProcessSpec(\"%s\") Count Mismatch: want [%d], got [%d]",
+				tt.cfg.Name, len(tt.locations), len(vios))
+			continue
+		}
+		for i, vio := range vios {
+			if vio.Location != tt.locations[i] {
+				t.Errorf("RuleDeclarative.ProcessSpec(\"%s\") Mismatch: want [%s], got [%s]",
+					tt.cfg.Name, tt.locations[i], vio.Location)
+			}
+		}
+	}
+}
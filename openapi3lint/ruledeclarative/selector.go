@@ -0,0 +1,167 @@
+package ruledeclarative
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/mogo/encoding/jsonpointer"
+	"github.com/grokify/mogo/type/maputil"
+)
+
+// SelectorKey selects the key of the target item, such as the path for
+// `paths`, the property name for `schema-properties` and the tag name
+// for `tags`.
+const SelectorKey = "@key"
+
+// Selector is a parsed JSONPath-like selector such as `$.operationId`,
+// `$.x-api-group`, `$.schema.type`, `$.tags[*]` or `$..parameters[*]`.
+// Supported segments are object keys, array indexes, the `*` wildcard
+// and `..` recursive descent. Filter expressions are not supported.
+type Selector struct {
+	raw      string
+	segments []selectorSegment
+}
+
+type selectorSegment struct {
+	key       string
+	recursive bool
+}
+
+func ParseSelector(s string) (Selector, error) {
+	sel := Selector{raw: strings.TrimSpace(s)}
+	if sel.raw == SelectorKey {
+		return sel, nil
+	}
+	rest := strings.TrimPrefix(sel.raw, "$")
+	for len(rest) > 0 {
+		recursive := false
+		if strings.HasPrefix(rest, "..") {
+			recursive = true
+			rest = rest[1:]
+			if strings.HasPrefix(rest, ".[") {
+				rest = rest[1:]
+			}
+		}
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return sel, fmt.Errorf("empty key in selector [%s]", s)
+			}
+			sel.segments = append(sel.segments, selectorSegment{key: rest[:end], recursive: recursive})
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return sel, fmt.Errorf("unclosed bracket in selector [%s]", s)
+			}
+			seg := rest[1:end]
+			if strings.HasPrefix(seg, "?") || strings.HasPrefix(seg, "(") {
+				return sel, fmt.Errorf("filter expressions not supported in selector [%s]", s)
+			} else if strings.Contains(seg, ",") || strings.Contains(seg, ":") {
+				return sel, fmt.Errorf("unions and slices not supported in selector [%s]", s)
+			}
+			seg = strings.Trim(seg, `'"`)
+			if len(seg) == 0 {
+				return sel, fmt.Errorf("empty index in selector [%s]", s)
+			}
+			sel.segments = append(sel.segments, selectorSegment{key: seg, recursive: recursive})
+			rest = rest[end+1:]
+		default:
+			return sel, fmt.Errorf("invalid selector [%s]", s)
+		}
+	}
+	return sel, nil
+}
+
+func (sel Selector) IsKey() bool {
+	return sel.raw == SelectorKey
+}
+
+// Pointer returns the JSON pointer suffix for the selector when it has no
+// wildcards or recursive descent, otherwise an empty string.
+func (sel Selector) Pointer() string {
+	ptr := ""
+	for _, seg := range sel.segments {
+		if seg.key == "*" || seg.recursive {
+			return ""
+		}
+		ptr += "/" + jsonpointer.PropertyNameEscape(seg.key)
+	}
+	return ptr
+}
+
+// SelectedValue is a value found by a `Selector` with its JSON pointer
+// suffix relative to the target item.
+type SelectedValue struct {
+	Pointer string
+	Value   any
+}
+
+// Select evaluates the selector against the JSON representation of `v`.
+func (sel Selector) Select(v any) ([]SelectedValue, error) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := json.Unmarshal(bytes, &doc); err != nil {
+		return nil, err
+	}
+	return sel.SelectDocument(doc), nil
+}
+
+// SelectDocument evaluates the selector against a decoded JSON document
+// consisting of `map[string]any`, `[]any` and scalar values.
+func (sel Selector) SelectDocument(doc any) []SelectedValue {
+	vals := []SelectedValue{}
+	selectSegments(doc, "", sel.segments, &vals)
+	return vals
+}
+
+func selectSegments(node any, ptr string, segments []selectorSegment, vals *[]SelectedValue) {
+	if node == nil {
+		return
+	}
+	if len(segments) == 0 {
+		*vals = append(*vals, SelectedValue{Pointer: ptr, Value: node})
+		return
+	}
+	seg := segments[0]
+	switch n := node.(type) {
+	case map[string]any:
+		keys := []string{seg.key}
+		if seg.key == "*" || seg.recursive {
+			keys = maputil.Keys(n)
+		}
+		for _, key := range keys {
+			child, ok := n[key]
+			if !ok {
+				continue
+			}
+			childPtr := ptr + "/" + jsonpointer.PropertyNameEscape(key)
+			if seg.key == "*" || seg.key == key {
+				selectSegments(child, childPtr, segments[1:], vals)
+			}
+			if seg.recursive {
+				selectSegments(child, childPtr, segments, vals)
+			}
+		}
+	case []any:
+		for i, child := range n {
+			childPtr := ptr + "/" + strconv.Itoa(i)
+			if seg.key == "*" || seg.key == strconv.Itoa(i) {
+				selectSegments(child, childPtr, segments[1:], vals)
+			}
+			if seg.recursive {
+				selectSegments(child, childPtr, segments, vals)
+			}
+		}
+	}
+}
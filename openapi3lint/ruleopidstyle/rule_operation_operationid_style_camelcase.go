@@ -2,8 +2,10 @@ package ruleopidstyle
 
 import (
 	"fmt"
+	"strings"
 
 	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/encoding/jsonpointer"
 	"github.com/grokify/mogo/net/urlutil"
 	"github.com/grokify/mogo/text/stringcase"
 	"github.com/grokify/spectrum/openapi3"
@@ -68,3 +70,32 @@ func (rule RuleOperationOperationIDStyle) ProcessOperation(spec *openapi3.Spec,
 func (rule RuleOperationOperationIDStyle) ProcessSpec(spec *openapi3.Spec, pointerBase string) []lintutil.PolicyViolation {
 	return []lintutil.PolicyViolation{}
 }
+
+// Fix converts operationIds to the rule's string case.
+func (rule RuleOperationOperationIDStyle) Fix(spec *openapi3.Spec, pointerBase string) ([]lintutil.PolicyFix, error) {
+	fixes := []lintutil.PolicyFix{}
+	xf, err := stringcase.FuncToCase(rule.stringCase)
+	if err != nil {
+		return fixes, err
+	}
+	openapi3.VisitOperations(spec, func(path, method string, op *oas3.Operation) {
+		if op == nil || len(op.OperationID) == 0 {
+			return
+		}
+		if isWantCase, err := stringcase.IsCase(rule.stringCase, op.OperationID); err == nil && isWantCase {
+			return
+		}
+		opIDNew := xf(op.OperationID)
+		if opIDNew == op.OperationID {
+			return
+		}
+		fixes = append(fixes, lintutil.PolicyFix{
+			RuleName: rule.Name(),
+			Location: jsonpointer.PointerSubEscapeAll("%s#/paths/%s/%s/%s",
+				pointerBase, path, strings.ToLower(method), openapi3.PropertyOperationID),
+			Before: op.OperationID,
+			After:  opIDNew})
+		op.OperationID = opIDNew
+	})
+	return fixes, nil
+}
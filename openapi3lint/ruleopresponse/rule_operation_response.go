@@ -0,0 +1,204 @@
+// Package ruleopresponse provides rules for operation response coverage
+// including success responses, error response schemas, default responses
+// and `Location` headers for `201` responses.
+package ruleopresponse
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/encoding/jsonpointer"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+)
+
+// OptionSchema is the `RuleConfig.Options` key for the error schema name or
+// JSON pointer required by `operation-response-error-schema-shared`. When
+// not set, the component schema referenced by the most `4xx` and `5xx`
+// responses in the spec is required, with ties going to the first by name.
+const OptionSchema = "schema"
+
+const (
+	StatusDefault  = "default"
+	HeaderLocation = "Location"
+)
+
+type RuleOperationResponse struct {
+	name        string
+	errorSchema string
+}
+
+func NewRule(ruleName string, opts map[string]string) (RuleOperationResponse, error) {
+	ruleNameCanonical := strings.ToLower(strings.TrimSpace(ruleName))
+	rule := RuleOperationResponse{
+		name: ruleNameCanonical}
+	switch ruleNameCanonical {
+	case lintutil.RulenameOpResponseSuccessExist,
+		lintutil.RulenameOpResponseDefaultOr500Exist,
+		lintutil.RulenameOpResponse201LocationHeaderExist:
+	case lintutil.RulenameOpResponseErrorSchemaShared:
+		if schema := strings.TrimSpace(opts[OptionSchema]); len(schema) > 0 {
+			rule.errorSchema = openapi3.SchemaPointerExpand("", schema)
+		}
+	default:
+		return rule, fmt.Errorf("rule [%s] not supported", ruleName)
+	}
+	return rule, nil
+}
+
+func (rule RuleOperationResponse) Name() string {
+	return rule.name
+}
+
+func (rule RuleOperationResponse) Scope() string {
+	return lintutil.ScopeOperation
+}
+
+func (rule RuleOperationResponse) ProcessSpec(spec *openapi3.Spec, pointerBase string) []lintutil.PolicyViolation {
+	return []lintutil.PolicyViolation{}
+}
+
+func (rule RuleOperationResponse) ProcessOperation(spec *openapi3.Spec, op *oas3.Operation, opPointer, path, method string) []lintutil.PolicyViolation {
+	if spec == nil || op == nil {
+		return nil
+	}
+	responses := map[string]*oas3.ResponseRef{}
+	if op.Responses != nil {
+		responses = op.Responses.Map()
+	}
+	respsPointer := opPointer + "/responses"
+	switch rule.name {
+	case lintutil.RulenameOpResponseSuccessExist:
+		for status := range responses {
+			if strings.HasPrefix(status, "2") {
+				return nil
+			}
+		}
+		return []lintutil.PolicyViolation{{
+			RuleName: rule.Name(),
+			Location: respsPointer}}
+	case lintutil.RulenameOpResponseDefaultOr500Exist:
+		if _, ok := responses[StatusDefault]; ok {
+			return nil
+		} else if _, ok := responses["500"]; ok {
+			return nil
+		} else if _, ok := responses["5XX"]; ok {
+			return nil
+		}
+		return []lintutil.PolicyViolation{{
+			RuleName: rule.Name(),
+			Location: respsPointer}}
+	case lintutil.RulenameOpResponse201LocationHeaderExist:
+		respRef, ok := responses["201"]
+		if !ok {
+			return nil
+		}
+		resp := responseValue(spec, respRef)
+		if resp != nil {
+			for headerName := range resp.Headers {
+				if http.CanonicalHeaderKey(headerName) == HeaderLocation {
+					return nil
+				}
+			}
+		}
+		return []lintutil.PolicyViolation{{
+			RuleName: rule.Name(),
+			Location: respsPointer + "/201/headers"}}
+	case lintutil.RulenameOpResponseErrorSchemaShared:
+		return rule.processErrorSchemas(spec, responses, respsPointer)
+	}
+	return nil
+}
+
+func (rule RuleOperationResponse) processErrorSchemas(spec *openapi3.Spec, responses map[string]*oas3.ResponseRef, respsPointer string) []lintutil.PolicyViolation {
+	vios := []lintutil.PolicyViolation{}
+	errorSchema := rule.errorSchema
+	if len(errorSchema) == 0 {
+		errorSchema = sharedErrorSchema(spec)
+	}
+	for _, status := range maputil.Keys(responses) {
+		if !isErrorStatus(status) {
+			continue
+		}
+		statusPointer := respsPointer + "/" + status
+		resp := responseValue(spec, responses[status])
+		if resp == nil || len(resp.Content) == 0 {
+			vios = append(vios, lintutil.PolicyViolation{
+				RuleName: rule.Name(),
+				Location: statusPointer + "/content"})
+			continue
+		}
+		for _, mediaType := range maputil.Keys(resp.Content) {
+			mt := resp.Content[mediaType]
+			schPointer := statusPointer + "/content/" + jsonpointer.PropertyNameEscape(mediaType) + "/schema"
+			if mt == nil || mt.Schema == nil || !strings.HasPrefix(mt.Schema.Ref, openapi3.PointerComponentsSchemas+"/") {
+				vios = append(vios, lintutil.PolicyViolation{
+					RuleName: rule.Name(),
+					Location: schPointer})
+			} else if len(errorSchema) > 0 && mt.Schema.Ref != errorSchema {
+				vios = append(vios, lintutil.PolicyViolation{
+					RuleName: rule.Name(),
+					Location: schPointer,
+					Value:    mt.Schema.Ref})
+			}
+		}
+	}
+	return vios
+}
+
+// sharedErrorSchema returns the component schema referenced by the most
+// `4xx` and `5xx` responses in the spec, choosing the first by name on a tie.
+func sharedErrorSchema(spec *openapi3.Spec) string {
+	counts := map[string]int{}
+	openapi3.VisitOperations(spec, func(path, method string, op *oas3.Operation) {
+		if op == nil || op.Responses == nil {
+			return
+		}
+		for status, respRef := range op.Responses.Map() {
+			if !isErrorStatus(status) {
+				continue
+			}
+			resp := responseValue(spec, respRef)
+			if resp == nil {
+				continue
+			}
+			for _, mt := range resp.Content {
+				if mt != nil && mt.Schema != nil && strings.HasPrefix(mt.Schema.Ref, openapi3.PointerComponentsSchemas+"/") {
+					counts[mt.Schema.Ref]++
+				}
+			}
+		}
+	})
+	shared := ""
+	for _, ref := range maputil.Keys(counts) {
+		if counts[ref] > counts[shared] {
+			shared = ref
+		}
+	}
+	return shared
+}
+
+func isErrorStatus(status string) bool {
+	return strings.HasPrefix(status, "4") || strings.HasPrefix(status, "5")
+}
+
+// responseValue returns the response value, looking up `#/components/responses`
+// references when the reference has not been resolved.
+func responseValue(spec *openapi3.Spec, respRef *oas3.ResponseRef) *oas3.Response {
+	if respRef == nil {
+		return nil
+	} else if respRef.Value != nil {
+		return respRef.Value
+	}
+	name := strings.TrimPrefix(respRef.Ref, "#/components/responses/")
+	if name == respRef.Ref || spec.Components == nil {
+		return nil
+	}
+	if compRef, ok := spec.Components.Responses[name]; ok && compRef != nil {
+		return compRef.Value
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package ruleopresponse
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/encoding/jsonpointer"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+)
+
+const responseSpecJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},
+"paths":{
+  "/pets":{
+    "get":{"responses":{
+      "200":{"description":"OK"},
+      "default":{"$ref":"#/components/responses/Error"}}},
+    "post":{"responses":{
+      "201":{"description":"Created","headers":{"location":{"schema":{"type":"string"}}}},
+      "400":{"description":"Bad Request","content":{"application/json":{"schema":{"type":"object"}}}},
+      "500":{"description":"Error","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Problem"}}}}}}},
+  "/pets/{petId}":{
+    "put":{"responses":{
+      "201":{"$ref":"#/components/responses/Created"},
+      "404":{"description":"Not Found"},
+      "5XX":{"$ref":"#/components/responses/Error"}}},
+    "delete":{"responses":{
+      "400":{"description":"Bad Request","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Error"}}}}}}}},
+"components":{
+  "responses":{
+    "Created":{"description":"Created"},
+    "Error":{"description":"Error","content":{"application/json":{"schema":{"$ref":"#/components/schemas/Error"}}}}},
+  "schemas":{"Error":{"type":"object"},"Problem":{"type":"object"}}}}`
+
+var responseRuleTests = []struct {
+	ruleName string
+	opts     map[string]string
+	want     []string
+}{
+	{lintutil.RulenameOpResponseSuccessExist, nil, []string{
+		"#/paths/~1pets~1{petId}/delete/responses"}},
+	{lintutil.RulenameOpResponseDefaultOr500Exist, nil, []string{
+		"#/paths/~1pets~1{petId}/delete/responses"}},
+	{lintutil.RulenameOpResponse201LocationHeaderExist, nil, []string{
+		"#/paths/~1pets~1{petId}/put/responses/201/headers"}},
+	// Without the `schema` option, `Error` is required as it is referenced
+	// by the most error responses.
+	{lintutil.RulenameOpResponseErrorSchemaShared, nil, []string{
+		"#/paths/~1pets/post/responses/400/content/application~1json/schema",
+		"#/paths/~1pets/post/responses/500/content/application~1json/schema #/components/schemas/Problem",
+		"#/paths/~1pets~1{petId}/put/responses/404/content"}},
+	{lintutil.RulenameOpResponseErrorSchemaShared, map[string]string{OptionSchema: "Problem"}, []string{
+		"#/paths/~1pets/post/responses/400/content/application~1json/schema",
+		"#/paths/~1pets~1{petId}/delete/responses/400/content/application~1json/schema #/components/schemas/Error",
+		"#/paths/~1pets~1{petId}/put/responses/404/content",
+		"#/paths/~1pets~1{petId}/put/responses/5XX/content/application~1json/schema #/components/schemas/Error"}},
+	{lintutil.RulenameOpResponseErrorSchemaShared, map[string]string{OptionSchema: "Error"}, []string{
+		"#/paths/~1pets/post/responses/400/content/application~1json/schema",
+		"#/paths/~1pets/post/responses/500/content/application~1json/schema #/components/schemas/Problem",
+		"#/paths/~1pets~1{petId}/put/responses/404/content"}},
+}
+
+// TestRuleOperationResponse ensures each response coverage rule reports the
+// expected locations, including refs to component responses and the
+// `schema` option.
+func TestRuleOperationResponse(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(responseSpecJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	for _, tt := range responseRuleTests {
+		rule, err := NewRule(tt.ruleName, tt.opts)
+		if err != nil {
+			t.Fatalf("ruleopresponse.NewRule(%s) Error [%s]", tt.ruleName, err.Error())
+		}
+		got := []string{}
+		openapi3.VisitOperations(spec, func(path, method string, op *oas3.Operation) {
+			opPointer := jsonpointer.PointerSubEscapeAll("#/paths/%s/%s", path, strings.ToLower(method))
+			for _, vio := range rule.ProcessOperation(spec, op, opPointer, path, method) {
+				if vio.RuleName != tt.ruleName {
+					t.Errorf("RuleOperationResponse.ProcessOperation() Mismatch: rule name want [%s], got [%s]", tt.ruleName, vio.RuleName)
+				}
+				got = append(got, strings.TrimSpace(vio.Location+" "+vio.Value))
+			}
+		})
+		sort.Strings(got)
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("RuleOperationResponse.ProcessOperation(%s, %v) Mismatch: want [%s], got [%s]",
+				tt.ruleName, tt.opts, strings.Join(tt.want, "; "), strings.Join(got, "; "))
+		}
+	}
+	if _, err := NewRule("operation-response-unknown", nil); err == nil {
+		t.Errorf("ruleopresponse.NewRule() Mismatch: want error for unknown rule")
+	}
+}
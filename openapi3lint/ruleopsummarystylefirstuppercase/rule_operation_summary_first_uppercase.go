@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/encoding/jsonpointer"
 	"github.com/grokify/mogo/net/urlutil"
 	"github.com/grokify/mogo/type/stringsutil"
 	"github.com/grokify/spectrum/openapi3"
@@ -53,3 +54,29 @@ func (rule RuleOperationSummaryStyleFirstUpperCase) ProcessOperation(spec *opena
 func (rule RuleOperationSummaryStyleFirstUpperCase) ProcessSpec(spec *openapi3.Spec, pointerBase string) []lintutil.PolicyViolation {
 	return []lintutil.PolicyViolation{}
 }
+
+// Fix capitalizes the first character of operation summaries.
+func (rule RuleOperationSummaryStyleFirstUpperCase) Fix(spec *openapi3.Spec, pointerBase string) ([]lintutil.PolicyFix, error) {
+	fixes := []lintutil.PolicyFix{}
+	openapi3.VisitOperations(spec, func(path, method string, op *oas3.Operation) {
+		if op == nil {
+			return
+		}
+		summary := strings.TrimSpace(op.Summary)
+		if len(summary) == 0 {
+			return
+		}
+		summaryNew := stringsutil.ToUpperFirst(summary, false)
+		if summaryNew == summary {
+			return
+		}
+		fixes = append(fixes, lintutil.PolicyFix{
+			RuleName: rule.Name(),
+			Location: jsonpointer.PointerSubEscapeAll("%s#/paths/%s/%s/%s",
+				pointerBase, path, strings.ToLower(method), openapi3.PropertySummary),
+			Before: op.Summary,
+			After:  summaryNew})
+		op.Summary = summaryNew
+	})
+	return fixes, nil
+}
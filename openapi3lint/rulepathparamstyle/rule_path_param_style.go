@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 
 	oas3 "github.com/getkin/kin-openapi/openapi3"
 	"github.com/grokify/mogo/encoding/jsonpointer"
 	"github.com/grokify/mogo/text/stringcase"
+	"github.com/grokify/mogo/type/maputil"
 	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3edit"
 	"github.com/grokify/spectrum/openapi3lint/lintutil"
 )
 
@@ -112,3 +115,71 @@ func (rule RulePathParamStyle) ProcessSpec(spec *openapi3.Spec, pointerBase stri
 
 	return vios
 }
+
+// Fix converts path parameter names in paths, operations and components
+// to the rule's string case using `openapi3edit.SpecEdit.ParamPathNamesModify()`.
+// A fix is reported for each changed path, operation parameter and
+// component parameter.
+func (rule RulePathParamStyle) Fix(spec *openapi3.Spec, pointerBase string) ([]lintutil.PolicyFix, error) {
+	fixes := []lintutil.PolicyFix{}
+	xf, err := stringcase.FuncToCase(rule.stringCase)
+	if err != nil {
+		return fixes, err
+	}
+	if spec.Paths != nil {
+		pathsMap := spec.Paths.Map()
+		for _, pathBefore := range maputil.StringKeys(pathsMap, nil) {
+			if pathAfter := openapi3edit.PathTemplateParamMod(pathBefore, xf); pathAfter != pathBefore {
+				fixes = append(fixes, lintutil.PolicyFix{
+					RuleName: rule.Name(),
+					Location: jsonpointer.PointerSubEscapeAll("%s#/paths/%s", pointerBase, pathBefore),
+					Before:   pathBefore,
+					After:    pathAfter})
+			}
+		}
+	}
+	openapi3.VisitOperations(spec, func(path, method string, op *oas3.Operation) {
+		if op == nil {
+			return
+		}
+		for i, paramRef := range op.Parameters {
+			if paramRef == nil || paramRef.Value == nil || len(paramRef.Ref) > 0 ||
+				strings.ToLower(strings.TrimSpace(paramRef.Value.In)) != openapi3.InPath {
+				continue
+			}
+			if nameAfter := xf(paramRef.Value.Name); nameAfter != paramRef.Value.Name {
+				fixes = append(fixes, lintutil.PolicyFix{
+					RuleName: rule.Name(),
+					Location: jsonpointer.PointerSubEscapeAll("%s#/paths/%s/%s/parameters/%d/name",
+						pointerBase, path, strings.ToLower(method), i),
+					Before: paramRef.Value.Name,
+					After:  nameAfter})
+			}
+		}
+	})
+	if spec.Components != nil {
+		for _, paramName := range maputil.StringKeys(spec.Components.Parameters, nil) {
+			paramRef := spec.Components.Parameters[paramName]
+			if paramRef == nil || paramRef.Value == nil ||
+				strings.ToLower(strings.TrimSpace(paramRef.Value.In)) != openapi3.InPath {
+				continue
+			}
+			if nameAfter := xf(paramRef.Value.Name); nameAfter != paramRef.Value.Name {
+				fixes = append(fixes, lintutil.PolicyFix{
+					RuleName: rule.Name(),
+					Location: jsonpointer.PointerSubEscapeAll("%s#/components/parameters/%s/name",
+						pointerBase, paramName),
+					Before: paramRef.Value.Name,
+					After:  nameAfter})
+			}
+		}
+	}
+	if len(fixes) == 0 {
+		return fixes, nil
+	}
+	se := openapi3edit.NewSpecEdit(spec)
+	if _, err := se.ParamPathNamesModify(xf); err != nil {
+		return []lintutil.PolicyFix{}, err
+	}
+	return fixes, nil
+}
@@ -31,6 +31,9 @@ func (rule RuleSchemaObjectPropsExist) ProcessOperation(spec *openapi3.Spec, op
 
 func (rule RuleSchemaObjectPropsExist) ProcessSpec(spec *openapi3.Spec, pointerBase string) []lintutil.PolicyViolation {
 	vios := []lintutil.PolicyViolation{}
+	if spec == nil || spec.Components == nil {
+		return vios
+	}
 
 	for schName, schRef := range spec.Components.Schemas {
 		if schRef == nil || schRef.Value == nil || !openapi3.TypesRefIs(schRef.Value.Type, openapi3.TypeObject) {
@@ -6,6 +6,7 @@ import (
 	oas3 "github.com/getkin/kin-openapi/openapi3"
 	"github.com/grokify/mogo/encoding/jsonpointer"
 	"github.com/grokify/mogo/text/stringcase"
+	"github.com/grokify/mogo/type/maputil"
 	"github.com/grokify/spectrum/openapi3"
 	"github.com/grokify/spectrum/openapi3lint/lintutil"
 )
@@ -52,6 +53,9 @@ func (rule RuleSchemaPropEnumStyle) ProcessOperation(spec *openapi3.Spec, op *oa
 
 func (rule RuleSchemaPropEnumStyle) ProcessSpec(spec *openapi3.Spec, pointerBase string) []lintutil.PolicyViolation {
 	vios := []lintutil.PolicyViolation{}
+	if spec.Components == nil {
+		return vios
+	}
 
 	for schName, schRef := range spec.Components.Schemas {
 		if schRef == nil || schRef.Value == nil || !openapi3.TypesRefIs(schRef.Value.Type, openapi3.TypeObject) {
@@ -88,3 +92,50 @@ func (rule RuleSchemaPropEnumStyle) ProcessSpec(spec *openapi3.Spec, pointerBase
 
 	return vios
 }
+
+// Fix converts string enum values of schema properties to the rule's
+// string case.
+func (rule RuleSchemaPropEnumStyle) Fix(spec *openapi3.Spec, pointerBase string) ([]lintutil.PolicyFix, error) {
+	fixes := []lintutil.PolicyFix{}
+	xf, err := stringcase.FuncToCase(rule.stringCase)
+	if err != nil {
+		return fixes, err
+	}
+	if spec.Components == nil {
+		return fixes, nil
+	}
+	for _, schName := range maputil.StringKeys(spec.Components.Schemas, nil) {
+		schRef := spec.Components.Schemas[schName]
+		if schRef == nil || schRef.Value == nil || !openapi3.TypesRefIs(schRef.Value.Type, openapi3.TypeObject) {
+			continue
+		}
+		for _, propName := range maputil.StringKeys(schRef.Value.Properties, nil) {
+			propRef := schRef.Value.Properties[propName]
+			if propRef == nil || propRef.Value == nil || !openapi3.TypesRefIs(propRef.Value.Type, openapi3.TypeString) {
+				continue
+			}
+			for i, enumValue := range propRef.Value.Enum {
+				enumValueString, ok := enumValue.(string)
+				if !ok {
+					continue
+				}
+				if isWantCase, err := stringcase.IsCase(rule.stringCase, enumValueString); err == nil && isWantCase {
+					continue
+				}
+				enumValueNew := xf(enumValueString)
+				if enumValueNew == enumValueString {
+					continue
+				}
+				fixes = append(fixes, lintutil.PolicyFix{
+					RuleName: rule.Name(),
+					Location: jsonpointer.PointerSubEscapeAll(
+						"%s#/components/schemas/%s/properties/%s/enum/%d",
+						pointerBase, schName, propName, i),
+					Before: enumValueString,
+					After:  enumValueNew})
+				propRef.Value.Enum[i] = enumValueNew
+			}
+		}
+	}
+	return fixes, nil
+}
@@ -0,0 +1,345 @@
+package rulespectral
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/encoding/jsonpointer"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/spectrum/openapi3lint/ruledeclarative"
+)
+
+// thenCompiled is a `then` entry with its field selector and function
+// options compiled.
+type thenCompiled struct {
+	function string
+	fieldKey bool
+	field    *ruledeclarative.Selector
+	match    *regexp.Regexp
+	notMatch *regexp.Regexp
+	casing   string
+	casingRx *regexp.Regexp
+	values   []any
+	min      *float64
+	max      *float64
+	schema   *oas3.Schema
+}
+
+// target is a value a function is applied to. `present` is false when
+// the `then.field` was not found.
+type target struct {
+	pointer string
+	value   any
+	present bool
+}
+
+func (tgt target) property() string {
+	idx := strings.LastIndex(tgt.pointer, "/")
+	if idx < 0 {
+		return ""
+	}
+	return jsonpointer.PropertyNameUnescape(tgt.pointer[idx+1:])
+}
+
+func compileThen(then Then) (thenCompiled, error) {
+	tc := thenCompiled{function: strings.TrimSpace(then.Function)}
+	if field := strings.TrimSpace(then.Field); field == ruledeclarative.SelectorKey {
+		tc.fieldKey = true
+	} else if len(field) > 0 {
+		if !strings.HasPrefix(field, "$") {
+			if strings.HasPrefix(field, "[") {
+				field = "$" + field
+			} else {
+				field = "$." + field
+			}
+		}
+		sel, err := ruledeclarative.ParseSelector(field)
+		if err != nil {
+			return tc, err
+		}
+		tc.field = &sel
+	}
+	opts := then.FunctionOptions
+	var err error
+	switch tc.function {
+	case FunctionTruthy, FunctionFalsy:
+	case FunctionPattern:
+		if tc.match, err = compilePattern(opts["match"]); err != nil {
+			return tc, err
+		}
+		if tc.notMatch, err = compilePattern(opts["notMatch"]); err != nil {
+			return tc, err
+		}
+		if tc.match == nil && tc.notMatch == nil {
+			return tc, fmt.Errorf("pattern requires match or notMatch")
+		}
+	case FunctionCasing:
+		if _, ok := opts["separator"]; ok {
+			return tc, fmt.Errorf("casing separator option is not supported")
+		}
+		tc.casing = fmt.Sprintf("%v", opts["type"])
+		disallowDigits, _ := opts["disallowDigits"].(bool)
+		if tc.casingRx, err = casingRegexp(tc.casing, disallowDigits); err != nil {
+			return tc, err
+		}
+	case FunctionEnumeration:
+		values, ok := opts["values"].([]any)
+		if !ok {
+			return tc, fmt.Errorf("enumeration requires values")
+		}
+		tc.values = values
+	case FunctionLength:
+		tc.min = optionNumber(opts["min"])
+		tc.max = optionNumber(opts["max"])
+		if tc.min == nil && tc.max == nil {
+			return tc, fmt.Errorf("length requires min or max")
+		}
+	case FunctionSchema:
+		if tc.schema, err = compileSchema(opts["schema"]); err != nil {
+			return tc, err
+		}
+	case "":
+		return tc, fmt.Errorf("then has no function")
+	default:
+		return tc, fmt.Errorf("function not supported [%s]", tc.function)
+	}
+	return tc, nil
+}
+
+// targets returns the values selected by `then.field` for a `given` node.
+func (tc thenCompiled) targets(node ruledeclarative.SelectedValue) []target {
+	switch {
+	case tc.fieldKey:
+		tgts := []target{}
+		switch n := node.Value.(type) {
+		case map[string]any:
+			for _, key := range maputil.Keys(n) {
+				tgts = append(tgts, target{
+					pointer: node.Pointer + "/" + jsonpointer.PropertyNameEscape(key),
+					value:   key,
+					present: true})
+			}
+		case []any:
+			for i := range n {
+				tgts = append(tgts, target{
+					pointer: node.Pointer + "/" + strconv.Itoa(i),
+					value:   float64(i),
+					present: true})
+			}
+		}
+		return tgts
+	case tc.field != nil:
+		vals := tc.field.SelectDocument(node.Value)
+		if len(vals) == 0 {
+			return []target{{pointer: node.Pointer + tc.field.Pointer()}}
+		}
+		tgts := []target{}
+		for _, val := range vals {
+			tgts = append(tgts, target{
+				pointer: node.Pointer + val.Pointer,
+				value:   val.Value,
+				present: true})
+		}
+		return tgts
+	}
+	return []target{{pointer: node.Pointer, value: node.Value, present: true}}
+}
+
+// evaluate returns error messages for a target. Functions other than
+// `truthy` ignore missing values, as Spectral does.
+func (tc thenCompiled) evaluate(tgt target) []string {
+	if tc.function == FunctionTruthy {
+		if !tgt.present || !truthy(tgt.value) {
+			return []string{fmt.Sprintf("%s must be truthy", quoteProperty(tgt))}
+		}
+		return nil
+	} else if !tgt.present {
+		return nil
+	}
+	switch tc.function {
+	case FunctionFalsy:
+		if truthy(tgt.value) {
+			return []string{fmt.Sprintf("%s must be falsy", quoteProperty(tgt))}
+		}
+	case FunctionPattern:
+		s, ok := tgt.value.(string)
+		if !ok {
+			return nil
+		}
+		msgs := []string{}
+		if tc.match != nil && !tc.match.MatchString(s) {
+			msgs = append(msgs, fmt.Sprintf("%s must match the pattern %q", quoteValue(s), tc.match.String()))
+		}
+		if tc.notMatch != nil && tc.notMatch.MatchString(s) {
+			msgs = append(msgs, fmt.Sprintf("%s must not match the pattern %q", quoteValue(s), tc.notMatch.String()))
+		}
+		return msgs
+	case FunctionCasing:
+		if s, ok := tgt.value.(string); ok && !tc.casingRx.MatchString(s) {
+			return []string{fmt.Sprintf("%s must be %s case", quoteValue(s), tc.casing)}
+		}
+	case FunctionEnumeration:
+		for _, v := range tc.values {
+			if v == tgt.value {
+				return nil
+			}
+		}
+		allowed := []string{}
+		for _, v := range tc.values {
+			allowed = append(allowed, valueString(v))
+		}
+		return []string{fmt.Sprintf("%s must be equal to one of the allowed values: %s",
+			quoteValue(valueString(tgt.value)), strings.Join(allowed, ", "))}
+	case FunctionLength:
+		l, ok := length(tgt.value)
+		if !ok {
+			return nil
+		}
+		if tc.min != nil && l < *tc.min {
+			return []string{fmt.Sprintf("%s must not be shorter than %s", quoteProperty(tgt), numberString(*tc.min))}
+		}
+		if tc.max != nil && l > *tc.max {
+			return []string{fmt.Sprintf("%s must not be longer than %s", quoteProperty(tgt), numberString(*tc.max))}
+		}
+	case FunctionSchema:
+		if err := tc.schema.VisitJSON(tgt.value, oas3.MultiErrors()); err != nil {
+			return []string{fmt.Sprintf("%s does not match schema: %s", quoteProperty(tgt), err.Error())}
+		}
+	}
+	return nil
+}
+
+// compilePattern compiles a JavaScript-style `/regex/flags` or plain
+// regular expression. Only the `i`, `m` and `s` flags are kept.
+func compilePattern(v any) (*regexp.Regexp, error) {
+	if v == nil {
+		return nil, nil
+	}
+	s := fmt.Sprintf("%v", v)
+	if strings.HasPrefix(s, "/") {
+		if idx := strings.LastIndex(s, "/"); idx > 0 {
+			flags := ""
+			for _, f := range s[idx+1:] {
+				if strings.ContainsRune("ims", f) {
+					flags += string(f)
+				}
+			}
+			s = s[1:idx]
+			if len(flags) > 0 {
+				s = "(?" + flags + ")" + s
+			}
+		}
+	}
+	return regexp.Compile(s)
+}
+
+// casingRegexp returns the Spectral `casing` function pattern for a case type.
+func casingRegexp(caseType string, disallowDigits bool) (*regexp.Regexp, error) {
+	d := "0-9"
+	if disallowDigits {
+		d = ""
+	}
+	var pattern string
+	switch caseType {
+	case "flat":
+		pattern = `^[a-z][a-z%[1]s]*$`
+	case "camel":
+		pattern = `^[a-z][a-z%[1]s]*(?:[A-Z%[1]s](?:[a-z%[1]s]+|$))*$`
+	case "pascal":
+		pattern = `^[A-Z][a-z%[1]s]*(?:[A-Z%[1]s](?:[a-z%[1]s]+|$))*$`
+	case "kebab":
+		pattern = `^[a-z][a-z%[1]s]*(?:-[a-z%[1]s]+)*$`
+	case "cobol":
+		pattern = `^[A-Z][A-Z%[1]s]*(?:-[A-Z%[1]s]+)*$`
+	case "snake":
+		pattern = `^[a-z][a-z%[1]s]*(?:_[a-z%[1]s]+)*$`
+	case "macro":
+		pattern = `^[A-Z][A-Z%[1]s]*(?:_[A-Z%[1]s]+)*$`
+	default:
+		return nil, fmt.Errorf("casing type not supported [%s]", caseType)
+	}
+	return regexp.Compile(fmt.Sprintf(pattern, d))
+}
+
+func compileSchema(v any) (*oas3.Schema, error) {
+	if v == nil {
+		return nil, fmt.Errorf("schema requires a schema option")
+	}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	sch := &oas3.Schema{}
+	return sch, json.Unmarshal(bytes, sch)
+}
+
+func optionNumber(v any) *float64 {
+	if f, ok := v.(float64); ok {
+		return &f
+	}
+	return nil
+}
+
+func length(v any) (float64, bool) {
+	switch val := v.(type) {
+	case string:
+		return float64(utf8.RuneCountInString(val)), true
+	case []any:
+		return float64(len(val)), true
+	case map[string]any:
+		return float64(len(val)), true
+	case float64:
+		return val, true
+	}
+	return 0, false
+}
+
+// truthy follows JavaScript truthiness where empty arrays and objects are truthy.
+func truthy(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case string:
+		return len(val) > 0
+	case float64:
+		return val != 0 && !math.IsNaN(val)
+	}
+	return true
+}
+
+func valueString(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return numberString(val)
+	case bool:
+		return strconv.FormatBool(val)
+	}
+	return ""
+}
+
+func numberString(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func quoteProperty(tgt target) string {
+	if prop := tgt.property(); len(prop) > 0 {
+		return fmt.Sprintf("%q property", prop)
+	}
+	return "value"
+}
+
+func quoteValue(s string) string {
+	return strconv.Quote(s)
+}
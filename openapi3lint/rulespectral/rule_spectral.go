@@ -0,0 +1,152 @@
+package rulespectral
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/log/severity"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+	"github.com/grokify/spectrum/openapi3lint/ruledeclarative"
+)
+
+const RuleType = "spectral"
+
+// RuleSpectral is a compiled Spectral rule. It evaluates its `given`
+// paths against the JSON representation of the spec.
+type RuleSpectral struct {
+	name        string
+	severity    string
+	description string
+	message     string
+	givens      []ruledeclarative.Selector
+	thens       []thenCompiled
+}
+
+func newRuleSpectral(name string, def RuleDefinition, rpt *ImportReport) (RuleSpectral, bool, error) {
+	rule := RuleSpectral{
+		description: def.Description,
+		message:     def.Message}
+	sev, err := ParseSeverity(def.Severity)
+	if err != nil {
+		return rule, false, err
+	} else if sev == severity.SeverityDisabled ||
+		(def.Recommended != nil && !*def.Recommended) {
+		return rule, true, nil
+	}
+	rule.severity = sev
+	if rule.name, err = ruleName(name); err != nil {
+		return rule, false, err
+	}
+	givens, err := unmarshalStringOrList(def.Given)
+	if err != nil || len(givens) == 0 {
+		return rule, false, errors.New("given must be a JSONPath string or list")
+	}
+	for _, given := range givens {
+		if strings.HasPrefix(given, "#") {
+			return rule, false, fmt.Errorf("given aliases are not supported [%s]", given)
+		}
+		sel, err := ruledeclarative.ParseSelector(given)
+		if err != nil {
+			return rule, false, err
+		}
+		rule.givens = append(rule.givens, sel)
+	}
+	thens, err := unmarshalThens(def.Then)
+	if err != nil || len(thens) == 0 {
+		return rule, false, errors.New("then must be an object or list")
+	}
+	for _, then := range thens {
+		tc, err := compileThen(then)
+		if err != nil {
+			rpt.addUnsupported(name, then.Function, err.Error())
+			continue
+		}
+		rule.thens = append(rule.thens, tc)
+	}
+	if len(rule.thens) == 0 {
+		return rule, false, errors.New("no supported then functions")
+	}
+	return rule, false, nil
+}
+
+func (rule RuleSpectral) Name() string {
+	return rule.name
+}
+
+// Severity returns the rule severity mapped from the Spectral severity.
+func (rule RuleSpectral) Severity() string {
+	return rule.severity
+}
+
+func (rule RuleSpectral) Scope() string {
+	return lintutil.ScopeSpecification
+}
+
+func (rule RuleSpectral) ProcessOperation(spec *openapi3.Spec, op *oas3.Operation, opPointer, path, method string) []lintutil.PolicyViolation {
+	return nil
+}
+
+func (rule RuleSpectral) ProcessSpec(spec *openapi3.Spec, pointerBase string) []lintutil.PolicyViolation {
+	vios := []lintutil.PolicyViolation{}
+	if spec == nil {
+		return vios
+	}
+	doc, err := specDocument(spec)
+	if err != nil {
+		return append(vios, lintutil.PolicyViolation{
+			RuleName:  rule.Name(),
+			RuleType:  RuleType,
+			Violation: err.Error(),
+			Location:  pointerBase + "#"})
+	}
+	for _, given := range rule.givens {
+		for _, node := range given.SelectDocument(doc) {
+			for _, then := range rule.thens {
+				for _, tgt := range then.targets(node) {
+					for _, msg := range then.evaluate(tgt) {
+						vios = append(vios, lintutil.PolicyViolation{
+							RuleName:  rule.Name(),
+							RuleType:  RuleType,
+							Violation: rule.violationMessage(msg, tgt),
+							Location:  pointerBase + "#" + tgt.pointer,
+							Value:     valueString(tgt.value)})
+					}
+				}
+			}
+		}
+	}
+	return vios
+}
+
+// violationMessage renders the Spectral `message` template placeholders
+// `{{error}}`, `{{description}}`, `{{path}}`, `{{property}}` and `{{value}}`.
+func (rule RuleSpectral) violationMessage(errMsg string, tgt target) string {
+	msg := rule.message
+	if len(msg) == 0 {
+		if len(rule.description) > 0 {
+			msg = rule.description
+		} else {
+			msg = "{{error}}"
+		}
+	}
+	return strings.NewReplacer(
+		"{{error}}", errMsg,
+		"{{description}}", rule.description,
+		"{{path}}", "#"+tgt.pointer,
+		"{{property}}", tgt.property(),
+		"{{value}}", valueString(tgt.value),
+	).Replace(msg)
+}
+
+func specDocument(spec *openapi3.Spec) (any, error) {
+	bytes, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	return doc, json.Unmarshal(bytes, &doc)
+}
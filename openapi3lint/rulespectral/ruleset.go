@@ -0,0 +1,214 @@
+// rulespectral imports a subset of Spectral rulesets as lint rules.
+package rulespectral
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/grokify/mogo/log/severity"
+	"github.com/grokify/mogo/text/stringcase"
+	"github.com/grokify/mogo/type/maputil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	FunctionCasing      = "casing"
+	FunctionEnumeration = "enumeration"
+	FunctionFalsy       = "falsy"
+	FunctionLength      = "length"
+	FunctionPattern     = "pattern"
+	FunctionSchema      = "schema"
+	FunctionTruthy      = "truthy"
+)
+
+// Functions returns the supported Spectral core functions.
+func Functions() []string {
+	return []string{
+		FunctionCasing,
+		FunctionEnumeration,
+		FunctionFalsy,
+		FunctionLength,
+		FunctionPattern,
+		FunctionSchema,
+		FunctionTruthy}
+}
+
+// Ruleset is a Spectral ruleset such as a `.spectral.yaml` file. Rules
+// are kept raw because Spectral allows a rule to be a definition, a
+// severity string or a boolean.
+type Ruleset struct {
+	Extends json.RawMessage            `json:"extends,omitempty"`
+	Formats []string                   `json:"formats,omitempty"`
+	Rules   map[string]json.RawMessage `json:"rules,omitempty"`
+}
+
+// RuleDefinition is a Spectral rule definition. `Given` is a JSONPath
+// string or list and `Then` is an object or list.
+type RuleDefinition struct {
+	Description string          `json:"description,omitempty"`
+	Message     string          `json:"message,omitempty"`
+	Severity    json.RawMessage `json:"severity,omitempty"`
+	Recommended *bool           `json:"recommended,omitempty"`
+	Given       json.RawMessage `json:"given"`
+	Then        json.RawMessage `json:"then"`
+}
+
+type Then struct {
+	Field           string         `json:"field,omitempty"`
+	Function        string         `json:"function"`
+	FunctionOptions map[string]any `json:"functionOptions,omitempty"`
+}
+
+// ReadRulesetFile reads a Spectral ruleset in YAML or JSON format.
+func ReadRulesetFile(filename string) (Ruleset, error) {
+	bytes, err := os.ReadFile(filename)
+	if err != nil {
+		return Ruleset{}, err
+	}
+	return ParseRuleset(bytes)
+}
+
+func ParseRuleset(bytes []byte) (Ruleset, error) {
+	rs := Ruleset{}
+	return rs, yaml.Unmarshal(bytes, &rs)
+}
+
+// ImportReport describes how a ruleset was mapped to lint rules.
+type ImportReport struct {
+	Imported    []string      `json:"imported"`
+	Disabled    []string      `json:"disabled,omitempty"`
+	Unsupported []Unsupported `json:"unsupported,omitempty"`
+}
+
+// Unsupported is a ruleset feature that could not be imported. When
+// `Function` is empty, the whole rule was skipped.
+type Unsupported struct {
+	RuleName string `json:"rule"`
+	Function string `json:"function,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+func (rpt *ImportReport) HasUnsupported() bool {
+	return len(rpt.Unsupported) > 0
+}
+
+func (rpt *ImportReport) addUnsupported(ruleName, function, reason string) {
+	rpt.Unsupported = append(rpt.Unsupported, Unsupported{
+		RuleName: ruleName,
+		Function: function,
+		Reason:   reason})
+}
+
+// WriteText writes one line per unsupported feature followed by a summary.
+func (rpt *ImportReport) WriteText(w io.Writer) error {
+	for _, u := range rpt.Unsupported {
+		fn := u.Function
+		if len(fn) == 0 {
+			fn = "-"
+		}
+		if _, err := fmt.Fprintf(w, "unsupported\t%s\t%s\t%s\n", u.RuleName, fn, u.Reason); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "spectral rules imported: %d, disabled: %d, unsupported: %d\n",
+		len(rpt.Imported), len(rpt.Disabled), len(rpt.Unsupported))
+	return err
+}
+
+// Compile compiles the supported rules in the ruleset. Rules, `given` paths and
+// `then` functions that cannot be mapped are listed in the report. A rule
+// is imported when at least one of its `then` entries is supported.
+func (rs Ruleset) Compile() ([]RuleSpectral, *ImportReport) {
+	rules := []RuleSpectral{}
+	rpt := &ImportReport{Imported: []string{}}
+	if len(rs.Extends) > 0 && string(rs.Extends) != "null" {
+		rpt.addUnsupported("", "", "extends is not supported; only rules defined in this ruleset are imported")
+	}
+	for _, ruleName := range maputil.Keys(rs.Rules) {
+		raw := rs.Rules[ruleName]
+		def := RuleDefinition{}
+		if err := json.Unmarshal(raw, &def); err != nil {
+			// Rules given as `false`, `"off"` or a severity only override
+			// inherited rules.
+			rpt.addUnsupported(ruleName, "", fmt.Sprintf("rule override [%s] requires extends", string(raw)))
+			continue
+		}
+		rule, disabled, err := newRuleSpectral(ruleName, def, rpt)
+		if err != nil {
+			rpt.addUnsupported(ruleName, "", err.Error())
+			continue
+		} else if disabled {
+			rpt.Disabled = append(rpt.Disabled, ruleName)
+			continue
+		}
+		rules = append(rules, rule)
+		rpt.Imported = append(rpt.Imported, rule.Name())
+	}
+	return rules, rpt
+}
+
+// ParseSeverity converts a Spectral severity name or number to a
+// `severity` value. An empty severity defaults to `warn`. Disabled rules
+// return `severity.SeverityDisabled`.
+func ParseSeverity(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return severity.SeverityWarning, nil
+	}
+	var sev any
+	if err := json.Unmarshal(raw, &sev); err != nil {
+		return "", err
+	}
+	switch strings.ToLower(strings.TrimSpace(fmt.Sprintf("%v", sev))) {
+	case "error", "0":
+		return severity.SeverityError, nil
+	case "warn", "1":
+		return severity.SeverityWarning, nil
+	case "info", "2":
+		return severity.SeverityInformational, nil
+	case "hint", "3":
+		return severity.SeverityDebug, nil
+	case "off", "-1", "false":
+		return severity.SeverityDisabled, nil
+	}
+	return "", fmt.Errorf("unknown spectral severity [%s]", string(raw))
+}
+
+// ruleName returns a kebab-case rule name as required by `Policy.AddRule()`.
+func ruleName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		return "", errors.New("rule has no name")
+	}
+	if stringcase.IsKebabCase(name) {
+		return name, nil
+	}
+	kebab := stringcase.ToKebabCase(name)
+	if !stringcase.IsKebabCase(kebab) {
+		return "", fmt.Errorf("rule name cannot be converted to kebab-case [%s]", name)
+	}
+	return kebab, nil
+}
+
+func unmarshalStringOrList(raw json.RawMessage) ([]string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []string{s}, nil
+	}
+	var list []string
+	err := json.Unmarshal(raw, &list)
+	return list, err
+}
+
+func unmarshalThens(raw json.RawMessage) ([]Then, error) {
+	var then Then
+	if err := json.Unmarshal(raw, &then); err == nil {
+		return []Then{then}, nil
+	}
+	var thens []Then
+	err := json.Unmarshal(raw, &thens)
+	return thens, err
+}
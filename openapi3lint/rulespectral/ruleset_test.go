@@ -0,0 +1,106 @@
+package rulespectral
+
+import (
+	"testing"
+
+	"github.com/grokify/spectrum/openapi3"
+)
+
+const rulesetYAML = `
+rules:
+  operation-id-camel:
+    severity: error
+    given: "$.paths[*][*]"
+    then:
+      field: operationId
+      function: casing
+      functionOptions:
+        type: camel
+  info-contact:
+    given: $.info
+    then:
+      field: contact
+      function: truthy
+  path-keys-lower:
+    given: $.paths
+    then:
+      field: "@key"
+      function: pattern
+      functionOptions:
+        match: "^[a-z/{}]+$"
+  tag-description-length:
+    severity: info
+    given: "$.tags[*]"
+    then:
+      - field: description
+        function: length
+        functionOptions: {min: 10}
+      - field: name
+        function: alphabetical
+  no-x-internal:
+    given: "$..x-internal"
+    then:
+      function: falsy
+  operation-get-only:
+    given: "$.paths[*][?(@property === 'get')]"
+    then:
+      function: truthy
+`
+
+const specJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},
+"tags":[{"name":"users","description":"Users"}],
+"paths":{
+  "/Users":{"get":{"operationId":"list_users","x-internal":true,
+    "responses":{"200":{"description":"OK"}}}}}}`
+
+var rulesetTests = []struct {
+	ruleName  string
+	severity  string
+	locations []string
+}{
+	{"info-contact", "warning", []string{"#/info/contact"}},
+	{"no-x-internal", "warning", []string{"#/paths/~1Users/get/x-internal"}},
+	{"operation-id-camel", "err", []string{"#/paths/~1Users/get/operationId"}},
+	{"path-keys-lower", "warning", []string{"#/paths/~1Users"}},
+	{"tag-description-length", "info", []string{"#/tags/0/description"}},
+}
+
+// TestRulesetCompile ensures supported Spectral rules are imported and evaluated.
+func TestRulesetCompile(t *testing.T) {
+	rs, err := ParseRuleset([]byte(rulesetYAML))
+	if err != nil {
+		t.Fatalf("rulespectral.ParseRuleset() Error [%s]", err.Error())
+	}
+	spec, err := openapi3.Parse([]byte(specJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	rules, rpt := rs.Compile()
+	if len(rules) != len(rulesetTests) {
+		t.Fatalf("Ruleset.Compile() Count Mismatch: want [%d], got [%d]", len(rulesetTests), len(rules))
+	}
+	if len(rpt.Unsupported) != 2 {
+		t.Errorf("Ruleset.Compile() Unsupported Mismatch: want [2], got [%d]", len(rpt.Unsupported))
+	}
+	for i, tt := range rulesetTests {
+		rule := rules[i]
+		if rule.Name() != tt.ruleName || rule.Severity() != tt.severity {
+			t.Errorf("Ruleset.Compile() Mismatch: want [%s, %s], got [%s, %s]",
+				tt.ruleName, tt.severity, rule.Name(), rule.Severity())
+			continue
+		}
+		vios := rule.ProcessSpec(spec, "")
+		if len(vios) != len(tt.locations) {
+			t.Errorf("RuleSpectral.ProcessSpec(\"%s\") Count Mismatch: want [%d], got [%d]",
+				tt.ruleName, len(tt.locations), len(vios))
+			continue
+		}
+		for j, vio := range vios {
+			if vio.Location != tt.locations[j] {
+				t.Errorf("RuleSpectral.ProcessSpec(\"%s\") Mismatch: want [%s], got [%s]",
+					tt.ruleName, tt.locations[j], vio.Location)
+			}
+		}
+	}
+}
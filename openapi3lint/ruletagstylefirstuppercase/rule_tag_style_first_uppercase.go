@@ -2,10 +2,14 @@ package ruletagstylefirstuppercase
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/grokify/mogo/encoding/jsonpointer"
 	"github.com/grokify/mogo/text/stringcase"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/mogo/type/stringsutil"
 	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3edit"
 	"github.com/grokify/spectrum/openapi3lint/lintutil"
 )
 
@@ -67,3 +71,51 @@ func (rule RuleTagStyleFirstUpperCase) ProcessSpec(spec *openapi3.Spec, pointerB
 	}
 	return vios
 }
+
+// Fix capitalizes the first character of tag names in the spec tag
+// definitions and operations using `openapi3edit.SpecEdit.TagsModify()`.
+// A fix is reported for each tag definition and operation tag.
+func (rule RuleTagStyleFirstUpperCase) Fix(spec *openapi3.Spec, pointerBase string) ([]lintutil.PolicyFix, error) {
+	fixes := []lintutil.PolicyFix{}
+	tagsMap := map[string]string{}
+	add := func(location, tagName string) {
+		tagName = strings.TrimSpace(tagName)
+		if len(tagName) == 0 || stringcase.IsFirstAlphaUpper(tagName) {
+			return
+		}
+		tagNameNew := stringsutil.ToUpperFirst(tagName, false)
+		if tagNameNew == tagName {
+			return
+		}
+		tagsMap[tagName] = tagNameNew
+		fixes = append(fixes, lintutil.PolicyFix{
+			RuleName: rule.Name(),
+			Location: location,
+			Before:   tagName,
+			After:    tagNameNew})
+	}
+	for i, tag := range spec.Tags {
+		if tag != nil {
+			add(jsonpointer.PointerSubEscapeAll("%s#/tags/%d/name", pointerBase, i), tag.Name)
+		}
+	}
+	if spec.Paths != nil {
+		pathsMap := spec.Paths.Map()
+		for _, pathURL := range maputil.StringKeys(pathsMap, nil) {
+			openapi3.VisitOperationsPathItem(pathURL, pathsMap[pathURL], func(path, method string, op *openapi3.Operation) {
+				if op == nil {
+					return
+				}
+				for i, tagName := range op.Tags {
+					add(jsonpointer.PointerSubEscapeAll("%s#/paths/%s/%s/tags/%d",
+						pointerBase, path, strings.ToLower(method), i), tagName)
+				}
+			})
+		}
+	}
+	if len(tagsMap) > 0 {
+		se := openapi3edit.NewSpecEdit(spec)
+		se.TagsModify(tagsMap)
+	}
+	return fixes, nil
+}
@@ -0,0 +1,212 @@
+package openapi3lint
+
+import (
+	"fmt"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/encoding/jsonpointer"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+)
+
+// XLintIgnore is the extension used to suppress rules on the spec, operations,
+// path item and operation parameters, component parameters, schemas and
+// schema properties. The value can be a list of rule names, a map of rule
+// names to reasons, or a list of objects with `rule` and `reason`
+// properties. The rule name `*` suppresses all rules.
+//
+//	x-lint-ignore:
+//	  operation-operationid-style-camelcase: legacy endpoint kept for compatibility
+const XLintIgnore = "x-lint-ignore"
+
+const suppressAllRules = "*"
+
+// Suppression is a single `x-lint-ignore` entry.
+type Suppression struct {
+	RuleName string
+	Reason   string
+	Pointer  string
+}
+
+// Match returns true if the suppression applies to the violation.
+func (sup Suppression) Match(vio lintutil.PolicyViolation) bool {
+	if sup.RuleName != suppressAllRules && sup.RuleName != vio.RuleName {
+		return false
+	}
+	if sup.Pointer == "#" {
+		return true
+	}
+	ptr := vio.Pointer()
+	return ptr == sup.Pointer || strings.HasPrefix(ptr, sup.Pointer+"/")
+}
+
+type Suppressions []Suppression
+
+// Match returns the first suppression matching the violation.
+func (sups Suppressions) Match(vio lintutil.PolicyViolation) (Suppression, bool) {
+	for _, sup := range sups {
+		if sup.Match(vio) {
+			return sup, true
+		}
+	}
+	return Suppression{}, false
+}
+
+// SpecSuppressions returns the `x-lint-ignore` entries in the spec.
+func SpecSuppressions(spec *openapi3.Spec) (Suppressions, error) {
+	sups := Suppressions{}
+	if spec == nil {
+		return sups, nil
+	}
+	add := func(ptr string, xprops map[string]any) error {
+		newSups, err := parseSuppressions(ptr, xprops)
+		if err != nil {
+			return err
+		}
+		sups = append(sups, newSups...)
+		return nil
+	}
+	if err := add("#", spec.Extensions); err != nil {
+		return sups, err
+	}
+	var errs []string
+	if spec.Paths != nil {
+		for path, pathItem := range spec.Paths.Map() {
+			if pathItem == nil {
+				continue
+			}
+			for i, paramRef := range pathItem.Parameters {
+				if paramRef == nil || paramRef.Value == nil {
+					continue
+				}
+				if err := add(jsonpointer.PointerSubEscapeAll("#/paths/%s/parameters/%d", path, i), paramRef.Value.Extensions); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+		}
+	}
+	openapi3.VisitOperations(spec, func(path, method string, op *oas3.Operation) {
+		if op == nil {
+			return
+		}
+		opPtr := jsonpointer.PointerSubEscapeAll("#/paths/%s/%s", path, strings.ToLower(method))
+		if err := add(opPtr, op.Extensions); err != nil {
+			errs = append(errs, err.Error())
+		}
+		for i, paramRef := range op.Parameters {
+			if paramRef == nil || paramRef.Value == nil {
+				continue
+			}
+			if err := add(fmt.Sprintf("%s/parameters/%d", opPtr, i), paramRef.Value.Extensions); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return sups, fmt.Errorf("invalid %s: %s", XLintIgnore, strings.Join(errs, "; "))
+	}
+	if spec.Components == nil {
+		return sups, nil
+	}
+	for paramName, paramRef := range spec.Components.Parameters {
+		if paramRef == nil || paramRef.Value == nil {
+			continue
+		}
+		if err := add(jsonpointer.PointerSubEscapeAll("#/components/parameters/%s", paramName), paramRef.Value.Extensions); err != nil {
+			return sups, err
+		}
+	}
+	for schName, schRef := range spec.Components.Schemas {
+		if schRef == nil || schRef.Value == nil {
+			continue
+		}
+		schPtr := jsonpointer.PointerSubEscapeAll("#/components/schemas/%s", schName)
+		if err := add(schPtr, schRef.Value.Extensions); err != nil {
+			return sups, err
+		}
+		for propName, propRef := range schRef.Value.Properties {
+			if propRef == nil || propRef.Value == nil {
+				continue
+			}
+			if err := add(schPtr+"/properties/"+jsonpointer.PropertyNameEscape(propName), propRef.Value.Extensions); err != nil {
+				return sups, err
+			}
+		}
+	}
+	return sups, nil
+}
+
+func parseSuppressions(ptr string, xprops map[string]any) (Suppressions, error) {
+	sups := Suppressions{}
+	val, ok := xprops[XLintIgnore]
+	if !ok || val == nil {
+		return sups, nil
+	}
+	switch v := val.(type) {
+	case string:
+		sups = append(sups, Suppression{RuleName: strings.TrimSpace(v), Pointer: ptr})
+	case []any:
+		for _, item := range v {
+			switch itemVal := item.(type) {
+			case string:
+				sups = append(sups, Suppression{RuleName: strings.TrimSpace(itemVal), Pointer: ptr})
+			case map[string]any:
+				ruleName, _ := itemVal["rule"].(string)
+				reason, _ := itemVal["reason"].(string)
+				if len(strings.TrimSpace(ruleName)) == 0 {
+					return sups, fmt.Errorf("missing rule at [%s]", ptr)
+				}
+				sups = append(sups, Suppression{
+					RuleName: strings.TrimSpace(ruleName),
+					Reason:   reason,
+					Pointer:  ptr})
+			default:
+				return sups, fmt.Errorf("unsupported type [%T] at [%s]", item, ptr)
+			}
+		}
+	case map[string]any:
+		for ruleName, reason := range v {
+			reasonString, _ := reason.(string)
+			sups = append(sups, Suppression{
+				RuleName: strings.TrimSpace(ruleName),
+				Reason:   reasonString,
+				Pointer:  ptr})
+		}
+	default:
+		return sups, fmt.Errorf("unsupported type [%T] at [%s]", val, ptr)
+	}
+	return sups, nil
+}
+
+// applySuppressions moves violations matched by `x-lint-ignore` entries
+// in the spec from `ByRule` to `SuppressedByRule`.
+func applySuppressions(spec *openapi3.Spec, vsets *lintutil.PolicyViolationsSets) error {
+	sups, err := SpecSuppressions(spec)
+	if err != nil || len(sups) == 0 {
+		return err
+	}
+	for ruleName, set := range vsets.ByRule {
+		kept := []lintutil.PolicyViolation{}
+		for _, vio := range set.Violations {
+			if sup, ok := sups.Match(vio); ok {
+				if len(sup.Reason) > 0 {
+					if vio.Data == nil {
+						vio.Data = map[string]string{}
+					}
+					vio.Data[lintutil.DataKeySuppressionReason] = sup.Reason
+				}
+				vsets.AddSuppressed(vio)
+			} else {
+				kept = append(kept, vio)
+			}
+		}
+		if len(kept) == 0 {
+			delete(vsets.ByRule, ruleName)
+		} else {
+			set.Violations = kept
+			vsets.ByRule[ruleName] = set
+		}
+	}
+	return nil
+}
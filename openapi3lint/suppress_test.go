@@ -0,0 +1,132 @@
+package openapi3lint
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/grokify/mogo/log/severity"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3lint/lintutil"
+	"github.com/grokify/spectrum/openapi3lint/ruledeclarative"
+)
+
+const suppressSpecJSON = `{
+"openapi":"3.0.3","info":{"title":"Test","version":"1.0.0"},
+"paths":{
+  "/users":{"get":{"operationId":"get_users",
+    "x-lint-ignore":{"operation-operationid-style-camelcase":"legacy endpoint"},
+    "responses":{"200":{"description":"OK"}}}},
+  "/pets":{"get":{"operationId":"get_pets","x-lint-ignore":["operation-summary-exist"],
+    "responses":{"200":{"description":"OK"}}}},
+  "/accounts":{
+    "parameters":[
+      {"name":"page_size","in":"query","schema":{"type":"integer"},
+        "x-lint-ignore":{"path-param-name-camel":"shared paging parameter"}},
+      {"name":"sort_by","in":"query","schema":{"type":"string"}}],
+    "get":{"operationId":"getAccounts","summary":"List accounts",
+      "responses":{"200":{"description":"OK"}}}}}}`
+
+var suppressTests = []struct {
+	ruleName   string
+	location   string
+	suppressed bool
+}{
+	{"operation-operationid-style-camelcase", "spec.json#/paths/~1users/get/operationId", true},
+	{"operation-operationid-style-camelcase", "spec.json#/paths/~1pets/get/operationId", false},
+	{"operation-summary-exist", "spec.json#/paths/~1pets/get/summary", true},
+	{"operation-summary-exist", "spec.json#/paths/~1users", false},
+	{"path-param-name-camel", "spec.json#/paths/~1accounts/parameters/0/name", true},
+	{"path-param-name-camel", "spec.json#/paths/~1accounts/parameters/1/name", false},
+}
+
+// TestSpecSuppressions ensures `x-lint-ignore` extensions are parsed and matched.
+func TestSpecSuppressions(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(suppressSpecJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	sups, err := SpecSuppressions(spec)
+	if err != nil {
+		t.Fatalf("openapi3lint.SpecSuppressions() Error [%s]", err.Error())
+	}
+	for _, tt := range suppressTests {
+		sup, got := sups.Match(lintutil.PolicyViolation{
+			RuleName: tt.ruleName,
+			Location: tt.location})
+		if got != tt.suppressed {
+			t.Errorf("Suppressions.Match(\"%s\", \"%s\") Mismatch: want [%v], got [%v]",
+				tt.ruleName, tt.location, tt.suppressed, got)
+		}
+		if got && tt.ruleName == "operation-operationid-style-camelcase" && sup.Reason != "legacy endpoint" {
+			t.Errorf("Suppression.Reason Mismatch: want [%s], got [%s]", "legacy endpoint", sup.Reason)
+		}
+	}
+}
+
+// TestValidateSpecSuppressions ensures `Policy.ValidateSpec()` drops
+// suppressed violations, including on path item parameters, and reports
+// them separately with their reasons.
+func TestValidateSpecSuppressions(t *testing.T) {
+	spec, err := openapi3.Parse([]byte(suppressSpecJSON))
+	if err != nil {
+		t.Fatalf("openapi3.Parse() Error [%s]", err.Error())
+	}
+	pol := NewPolicy()
+	for _, ruleName := range []string{lintutil.RulenameOpIDStyleCamelCase, lintutil.RulenameOpSummaryExist} {
+		rule, err := NewRuleCollectionStandard().RuleWithOptions(ruleName, nil)
+		if err != nil {
+			t.Fatalf("RuleCollectionStandard.RuleWithOptions(%s) Error [%s]", ruleName, err.Error())
+		}
+		if err := pol.AddRule(rule, severity.SeverityError, true); err != nil {
+			t.Fatalf("Policy.AddRule(%s) Error [%s]", ruleName, err.Error())
+		}
+	}
+	paramRule, err := ruledeclarative.NewRule(ruledeclarative.RuleConfig{
+		Name:     "path-param-name-camel",
+		Target:   ruledeclarative.TargetPaths,
+		Selector: "$.parameters[*].name",
+		Assert:   ruledeclarative.Assertion{Casing: "camelCase"}})
+	if err != nil {
+		t.Fatalf("ruledeclarative.NewRule() Error [%s]", err.Error())
+	}
+	if err := pol.AddRule(paramRule, severity.SeverityError, true); err != nil {
+		t.Fatalf("Policy.AddRule(%s) Error [%s]", paramRule.Name(), err.Error())
+	}
+
+	vsets, err := pol.ValidateSpec(spec, "spec.json", severity.SeverityError)
+	if err != nil {
+		t.Fatalf("Policy.ValidateSpec() Error [%s]", err.Error())
+	}
+	got := []string{}
+	for _, vio := range vsets.Violations() {
+		got = append(got, vio.RuleName+" "+vio.Location)
+	}
+	sort.Strings(got)
+	want := strings.Join([]string{
+		"operation-operationid-style-camelcase spec.json#/paths/~1pets/get/operationId",
+		"operation-summary-exist spec.json#/paths/~1users/get/summary",
+		"path-param-name-camel spec.json#/paths/~1accounts/parameters/1/name"}, "\n")
+	if strings.Join(got, "\n") != want {
+		t.Errorf("Policy.ValidateSpec() Violations Mismatch: want [%s], got [%s]", want, strings.Join(got, "\n"))
+	}
+	if vsets.Count() != 3 {
+		t.Errorf("PolicyViolationsSets.Count() Mismatch: want [%d], got [%d]", 3, vsets.Count())
+	}
+
+	gotSuppressed := []string{}
+	for _, vio := range vsets.SuppressedViolations() {
+		gotSuppressed = append(gotSuppressed, vio.RuleName+" "+vio.Location+" "+vio.Data[lintutil.DataKeySuppressionReason])
+	}
+	sort.Strings(gotSuppressed)
+	wantSuppressed := strings.Join([]string{
+		"operation-operationid-style-camelcase spec.json#/paths/~1users/get/operationId legacy endpoint",
+		"operation-summary-exist spec.json#/paths/~1pets/get/summary ",
+		"path-param-name-camel spec.json#/paths/~1accounts/parameters/0/name shared paging parameter"}, "\n")
+	if strings.Join(gotSuppressed, "\n") != wantSuppressed {
+		t.Errorf("Policy.ValidateSpec() Suppressed Mismatch: want [%s], got [%s]", wantSuppressed, strings.Join(gotSuppressed, "\n"))
+	}
+	if vsets.SuppressedCount() != 3 {
+		t.Errorf("PolicyViolationsSets.SuppressedCount() Mismatch: want [%d], got [%d]", 3, vsets.SuppressedCount())
+	}
+}
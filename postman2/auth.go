@@ -0,0 +1,138 @@
+package postman2
+
+import "fmt"
+
+const (
+	AuthTypeAPIKey   = "apikey"
+	AuthTypeAWSv4    = "awsv4"
+	AuthTypeBasic    = "basic"
+	AuthTypeBearer   = "bearer"
+	AuthTypeDigest   = "digest"
+	AuthTypeEdgeGrid = "edgegrid"
+	AuthTypeHawk     = "hawk"
+	AuthTypeNoAuth   = "noauth"
+	AuthTypeNTLM     = "ntlm"
+	AuthTypeOAuth1   = "oauth1"
+	AuthTypeOAuth2   = "oauth2"
+)
+
+// Auth is a Postman auth object used on collections, folders and requests.
+// The attribute list matching `Type` is used by Postman.
+type Auth struct {
+	Type     string          `json:"type"`
+	NoAuth   any             `json:"noauth,omitempty"`
+	APIKey   []AuthAttribute `json:"apikey,omitempty"`
+	AWSv4    []AuthAttribute `json:"awsv4,omitempty"`
+	Basic    []AuthAttribute `json:"basic,omitempty"`
+	Bearer   []AuthAttribute `json:"bearer,omitempty"`
+	Digest   []AuthAttribute `json:"digest,omitempty"`
+	EdgeGrid []AuthAttribute `json:"edgegrid,omitempty"`
+	Hawk     []AuthAttribute `json:"hawk,omitempty"`
+	NTLM     []AuthAttribute `json:"ntlm,omitempty"`
+	OAuth1   []AuthAttribute `json:"oauth1,omitempty"`
+	OAuth2   []AuthAttribute `json:"oauth2,omitempty"`
+	Extra    Extra           `json:"-"`
+}
+
+func (auth *Auth) UnmarshalJSON(data []byte) error {
+	type authAlias Auth
+	var v authAlias
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*auth = Auth(v)
+	auth.Extra = extra
+	return nil
+}
+
+func (auth Auth) MarshalJSON() ([]byte, error) {
+	type authAlias Auth
+	return marshalWithExtra(authAlias(auth), auth.Extra)
+}
+
+// NewAuth returns an `Auth` with the attributes set for the auth type.
+func NewAuth(authType string, attrs ...AuthAttribute) *Auth {
+	auth := &Auth{Type: authType}
+	if ptr := auth.attributesPointer(); ptr != nil {
+		*ptr = attrs
+	}
+	return auth
+}
+
+// Attributes returns the attributes for the auth type.
+func (auth *Auth) Attributes() []AuthAttribute {
+	if ptr := auth.attributesPointer(); ptr != nil && *ptr != nil {
+		return *ptr
+	}
+	return []AuthAttribute{}
+}
+
+// AttributeString returns the value of the attribute with `key` for the
+// auth type as a string, or an empty string if it is not set.
+func (auth *Auth) AttributeString(key string) string {
+	for _, attr := range auth.Attributes() {
+		if attr.Key == key && attr.Value != nil {
+			if s, ok := attr.Value.(string); ok {
+				return s
+			}
+			return fmt.Sprint(attr.Value)
+		}
+	}
+	return ""
+}
+
+func (auth *Auth) attributesPointer() *[]AuthAttribute {
+	switch auth.Type {
+	case AuthTypeAPIKey:
+		return &auth.APIKey
+	case AuthTypeAWSv4:
+		return &auth.AWSv4
+	case AuthTypeBasic:
+		return &auth.Basic
+	case AuthTypeBearer:
+		return &auth.Bearer
+	case AuthTypeDigest:
+		return &auth.Digest
+	case AuthTypeEdgeGrid:
+		return &auth.EdgeGrid
+	case AuthTypeHawk:
+		return &auth.Hawk
+	case AuthTypeNTLM:
+		return &auth.NTLM
+	case AuthTypeOAuth1:
+		return &auth.OAuth1
+	case AuthTypeOAuth2:
+		return &auth.OAuth2
+	}
+	return nil
+}
+
+type AuthAttribute struct {
+	Key   string `json:"key"`
+	Value any    `json:"value,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Extra Extra  `json:"-"`
+}
+
+func (attr *AuthAttribute) UnmarshalJSON(data []byte) error {
+	type authAttribute AuthAttribute
+	var v authAttribute
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*attr = AuthAttribute(v)
+	attr.Extra = extra
+	return nil
+}
+
+func (attr AuthAttribute) MarshalJSON() ([]byte, error) {
+	type authAttribute AuthAttribute
+	return marshalWithExtra(authAttribute(attr), attr.Extra)
+}
+
+// NewAuthAttribute returns a string auth attribute.
+func NewAuthAttribute(key string, value any) AuthAttribute {
+	return AuthAttribute{Key: key, Value: value, Type: "string"}
+}
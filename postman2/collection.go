@@ -2,6 +2,7 @@ package postman2
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
 
@@ -14,10 +15,48 @@ const (
 	SchemaURL200 = "https://schema.getpostman.com/json/collection/v2.0.0/collection.json"
 )
 
+// Collection is a Postman 2.1 collection. Members not modeled by the
+// struct are kept in `Extra` so collections round-trip without data loss.
+// A nil `Item` is written as `[]`, which the schema requires, unless the
+// collection was read without `item`.
 type Collection struct {
-	Info  CollectionInfo `json:"info"`
-	Item  []*Item        `json:"item"`
-	Event []Event        `json:"event,omitempty"`
+	Info                    CollectionInfo `json:"info"`
+	Item                    []*Item        `json:"item"`
+	Event                   []Event        `json:"event,omitempty"`
+	Variable                []Variable     `json:"variable,omitempty"`
+	Auth                    *Auth          `json:"auth,omitempty"`
+	ProtocolProfileBehavior map[string]any `json:"protocolProfileBehavior,omitempty"`
+	Extra                   Extra          `json:"-"`
+	itemAbsent              bool
+}
+
+func (col *Collection) UnmarshalJSON(data []byte) error {
+	type collection Collection
+	var v collection
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*col = Collection(v)
+	col.Extra = extra
+	if _, ok := extra["item"]; !ok && col.Item == nil {
+		col.itemAbsent = true
+	}
+	return nil
+}
+
+func (col Collection) MarshalJSON() ([]byte, error) {
+	type collection Collection
+	if col.Item == nil && col.itemAbsent {
+		// The outer `Item` shadows the embedded one so `item` is omitted.
+		return marshalWithExtra(struct {
+			collection
+			Item []*Item `json:"item,omitempty"`
+		}{collection: collection(col)}, col.Extra)
+	} else if col.Item == nil {
+		col.Item = []*Item{}
+	}
+	return marshalWithExtra(collection(col), col.Extra)
 }
 
 func ReadFile(filename string) (Collection, error) {
@@ -72,34 +111,152 @@ func (col *Collection) Inflate() {
 }
 
 func (col *Collection) InflateRawURLs() {
-	for _, folder := range col.Item {
-		for j, api := range folder.Item {
-			if api.Request.URL.IsRawOnly() &&
-				len(strings.TrimSpace(api.Request.URL.Raw)) > 0 {
-				url := NewURL(strings.TrimSpace(api.Request.URL.Raw))
-				url.Auth = api.Request.URL.Auth
-				url.Variable = api.Request.URL.Variable
-				folder.Item[j].Request.URL = &url
-			}
+	inflateRawURLs(col.Item)
+}
+
+// inflateRawURLs parses raw-only request URLs for items at any folder depth.
+func inflateRawURLs(items []*Item) {
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		inflateRawURLs(item.Item)
+		if item.Request == nil || item.Request.URL == nil {
+			continue
+		}
+		if item.Request.URL.IsRawOnly() &&
+			len(strings.TrimSpace(item.Request.URL.Raw)) > 0 {
+			url := NewURL(strings.TrimSpace(item.Request.URL.Raw))
+			url.Auth = item.Request.URL.Auth
+			url.Query = item.Request.URL.Query
+			url.Variable = item.Request.URL.Variable
+			item.Request.URL = &url
 		}
 	}
 }
 
 type CollectionInfo struct {
-	Name        string `json:"name,omitempty"`
-	PostmanID   string `json:"_postman_id,omitempty"`
-	Description string `json:"description,omitempty"`
-	Schema      string `json:"schema,omitempty"`
+	Name        string       `json:"name,omitempty"`
+	PostmanID   string       `json:"_postman_id,omitempty"`
+	Description *Description `json:"description,omitempty"`
+	Version     *Version     `json:"version,omitempty"`
+	Schema      string       `json:"schema,omitempty"`
+	Extra       Extra        `json:"-"`
+}
+
+func (info *CollectionInfo) UnmarshalJSON(data []byte) error {
+	type collectionInfo CollectionInfo
+	var v collectionInfo
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*info = CollectionInfo(v)
+	info.Extra = extra
+	return nil
+}
+
+func (info CollectionInfo) MarshalJSON() ([]byte, error) {
+	type collectionInfo CollectionInfo
+	return marshalWithExtra(collectionInfo(info), info.Extra)
 }
 
-// Item can represent a folder or an API
+// Version is a collection version, either a string or a semantic version
+// object.
+type Version struct {
+	Major      int    `json:"major"`
+	Minor      int    `json:"minor"`
+	Patch      int    `json:"patch"`
+	Identifier string `json:"identifier,omitempty"`
+	Meta       any    `json:"meta,omitempty"`
+	Extra      Extra  `json:"-"`
+	text       string
+}
+
+// String returns the version as a string such as `1.2.3`.
+func (ver *Version) String() string {
+	if ver == nil {
+		return ""
+	} else if len(ver.text) > 0 {
+		return ver.text
+	}
+	s := fmt.Sprintf("%d.%d.%d", ver.Major, ver.Minor, ver.Patch)
+	if len(ver.Identifier) > 0 {
+		s += "-" + ver.Identifier
+	}
+	return s
+}
+
+func (ver *Version) UnmarshalJSON(data []byte) error {
+	if jsonIsString(data) {
+		*ver = Version{}
+		return json.Unmarshal(data, &ver.text)
+	}
+	type version Version
+	var v version
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*ver = Version(v)
+	ver.Extra = extra
+	return nil
+}
+
+func (ver Version) MarshalJSON() ([]byte, error) {
+	if len(ver.text) > 0 {
+		return json.Marshal(ver.text)
+	}
+	type version Version
+	return marshalWithExtra(version(ver), ver.Extra)
+}
+
+// Item can represent a folder or an API. Folders, also known as item
+// groups, have `Item` set and APIs have `Request` set.
 type Item struct {
-	Name        string       `json:"name,omitempty"`                 // Folder,Operation
-	Description *Description `json:"description,omitempty"`          // Folder
-	Item        []*Item      `json:"item,omitempty"`                 // Folder
-	IsSubFolder bool         `json:"_postman_isSubFolder,omitempty"` // Folder
-	Event       []Event      `json:"event,omitempty"`                // Operation
-	Request     *Request     `json:"request,omitempty"`              // Operation
+	ID                      string         `json:"id,omitempty"`                   // Operation
+	Name                    string         `json:"name,omitempty"`                 // Folder,Operation
+	Description             *Description   `json:"description,omitempty"`          // Folder,Operation
+	Variable                []Variable     `json:"variable,omitempty"`             // Folder,Operation
+	Item                    []*Item        `json:"item,omitempty"`                 // Folder
+	IsSubFolder             bool           `json:"_postman_isSubFolder,omitempty"` // Folder
+	Event                   []Event        `json:"event,omitempty"`                // Folder,Operation
+	Auth                    *Auth          `json:"auth,omitempty"`                 // Folder
+	Request                 *Request       `json:"request,omitempty"`              // Operation
+	Response                []Response     `json:"response,omitempty"`             // Operation
+	ProtocolProfileBehavior map[string]any `json:"protocolProfileBehavior,omitempty"`
+	Extra                   Extra          `json:"-"`
+}
+
+func (item *Item) UnmarshalJSON(data []byte) error {
+	type itemAlias Item
+	var v itemAlias
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*item = Item(v)
+	item.Extra = extra
+	return nil
+}
+
+func (item Item) MarshalJSON() ([]byte, error) {
+	type itemAlias Item
+	return marshalWithExtra(itemAlias(item), item.Extra)
+}
+
+// DisplayName returns the item name or, for unnamed requests, the method
+// and raw URL.
+func (item *Item) DisplayName() string {
+	name := strings.TrimSpace(item.Name)
+	if len(name) > 0 || item.Request == nil {
+		return name
+	}
+	raw := ""
+	if item.Request.URL != nil {
+		raw = item.Request.URL.Raw
+	}
+	return strings.TrimSpace(item.Request.Method + " " + raw)
 }
 
 func (item *Item) UpsertSubItem(newItem *Item) {
@@ -115,10 +272,31 @@ func (item *Item) UpsertSubItem(newItem *Item) {
 	item.Item = append(item.Item, newItem)
 }
 
+// Description is a description object or, when read from a JSON string,
+// a string. String descriptions are written back as strings.
 type Description struct {
-	Content string `json:"content,omitempty"`
-	Type    string `json:"type,omitempty"`
-	Version string `json:"version,omitempty"`
+	Content  string `json:"content,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Version  any    `json:"version,omitempty"`
+	Extra    Extra  `json:"-"`
+	isString bool
+}
+
+// NewDescription returns a string description or nil if `content` is empty.
+func NewDescription(content string) *Description {
+	content = strings.TrimSpace(content)
+	if len(content) == 0 {
+		return nil
+	}
+	return &Description{Content: content, isString: true}
+}
+
+// String returns the description content. It is safe to call on nil.
+func (desc *Description) String() string {
+	if desc == nil {
+		return ""
+	}
+	return desc.Content
 }
 
 func (desc *Description) Inflate() {
@@ -129,12 +307,84 @@ func (desc *Description) Inflate() {
 	}
 }
 
+func (desc *Description) UnmarshalJSON(data []byte) error {
+	if jsonIsString(data) {
+		*desc = Description{isString: true}
+		return json.Unmarshal(data, &desc.Content)
+	}
+	type description Description
+	var v description
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*desc = Description(v)
+	desc.Extra = extra
+	return nil
+}
+
+func (desc Description) MarshalJSON() ([]byte, error) {
+	if desc.isString && len(desc.Type) == 0 && desc.Version == nil && len(desc.Extra) == 0 {
+		return json.Marshal(desc.Content)
+	}
+	type description Description
+	return marshalWithExtra(description(desc), desc.Extra)
+}
+
+const (
+	EventListenPrerequest = "prerequest"
+	EventListenTest       = "test"
+
+	ScriptTypeJavaScript = "text/javascript"
+)
+
 type Event struct {
-	Listen string `json:"listen"`
-	Script Script `json:"script"`
+	ID       string `json:"id,omitempty"`
+	Listen   string `json:"listen"`
+	Script   Script `json:"script,omitzero"`
+	Disabled bool   `json:"disabled,omitempty"`
+	Extra    Extra  `json:"-"`
+}
+
+func (ev *Event) UnmarshalJSON(data []byte) error {
+	type event Event
+	var v event
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*ev = Event(v)
+	ev.Extra = extra
+	return nil
+}
+
+func (ev Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	return marshalWithExtra(event(ev), ev.Extra)
 }
 
 type Script struct {
-	Type string   `json:"type,omitempty"`
-	Exec []string `json:"exec,omitempty"`
+	ID    string     `json:"id,omitempty"`
+	Type  string     `json:"type,omitempty"`
+	Exec  StringList `json:"exec,omitempty"` // string or array of lines
+	Src   *URL       `json:"src,omitempty"`
+	Name  string     `json:"name,omitempty"`
+	Extra Extra      `json:"-"`
+}
+
+func (scr *Script) UnmarshalJSON(data []byte) error {
+	type script Script
+	var v script
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*scr = Script(v)
+	scr.Extra = extra
+	return nil
+}
+
+func (scr Script) MarshalJSON() ([]byte, error) {
+	type script Script
+	return marshalWithExtra(script(scr), scr.Extra)
 }
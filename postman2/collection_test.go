@@ -0,0 +1,171 @@
+package postman2
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const collectionRoundTripJSON = `{
+"info":{"_postman_id":"abc","name":"Round Trip","description":"Plain description","version":{"major":1,"minor":2,"patch":3},
+  "schema":"https://schema.getpostman.com/json/collection/v2.1.0/collection.json","_exporter_id":"42"},
+"item":[
+  {"name":"Folder","description":{"content":"Folder docs","type":"text/markdown"},"auth":{"type":"noauth"},
+    "item":[
+      {"id":"1","name":"Upload","protocolProfileBehavior":{"disableBodyPruning":true},
+        "request":{"method":"POST","url":"https://example.com/upload?x=1",
+          "header":[{"key":"X-Trace","value":"1","disabled":true,"description":"trace"}],
+          "proxy":{"host":"proxy.local","port":3128,"tunnel":true},
+          "certificate":{"name":"cert","matches":["https://example.com/*"],"cert":{"src":"/c.pem"},"key":{"src":"/k.pem"},"passphrase":"pw"},
+          "body":{"mode":"formdata","formdata":[
+            {"key":"name","value":"n","type":"text","contentType":"text/plain"},
+            {"key":"file","src":["/a.png","/b.png"],"type":"file"}],
+            "options":{"raw":{"language":"json"}}}},
+        "response":[{"name":"OK","code":200,"status":"OK","body":"{}","responseTime":12,
+          "header":[{"key":"Content-Type","value":"application/json"}],
+          "cookie":[{"domain":"example.com","path":"/","name":"sid","value":"1","httpOnly":true}],
+          "_postman_previewlanguage":"json"}]},
+      {"name":"Query","request":{"method":"POST","url":{"raw":"{{baseUrl}}/v1/graphql","host":["{{baseUrl}}"],
+          "path":["v1",{"type":"string","value":"graphql"}],"query":[]},
+        "body":{"mode":"graphql","graphql":{"query":"{ me { id } }","variables":"{}"}}},"response":[]},
+      {"name":"Binary","request":{"method":"PUT","url":"https://example.com/bin","header":[
+          {"key":"Content-Type","value":"application/octet-stream","disabled":false}],
+        "body":{"mode":"file","file":{"src":"data.bin"},"disabled":false}}},
+      {"name":"Empty","request":{"method":"GET","url":"https://example.com/empty","header":[]},"response":[]},
+      {"name":"Signed","request":{"method":"GET","url":"https://example.com/s",
+        "auth":{"type":"awsv4","awsv4":[{"key":"region","value":"us-east-1","type":"string"}]}}}]},
+  {"name":"Top","event":[{"listen":"test","script":{"id":"s1","type":"text/javascript","exec":["pm.test('ok')"]}}],
+    "request":"https://example.com/top"}],
+"event":[{"listen":"prerequest","script":{"type":"text/javascript","exec":["console.log(1)"]}}],
+"variable":[{"key":"baseUrl","value":"https://example.com","type":"string"}],
+"auth":{"type":"bearer","bearer":[{"key":"token","value":"{{token}}","type":"string"}]},
+"protocolProfileBehavior":{"followRedirects":false},
+"x-custom":{"keep":true}}`
+
+// TestCollectionRoundTrip ensures reading and writing a collection keeps all
+// modeled and unknown members.
+func TestCollectionRoundTrip(t *testing.T) {
+	col := Collection{}
+	if err := json.Unmarshal([]byte(collectionRoundTripJSON), &col); err != nil {
+		t.Fatalf("json.Unmarshal() Error [%s]", err.Error())
+	}
+	data, err := json.Marshal(col)
+	if err != nil {
+		t.Fatalf("json.Marshal() Error [%s]", err.Error())
+	}
+	var want, got any
+	if err := json.Unmarshal([]byte(collectionRoundTripJSON), &want); err != nil {
+		t.Fatalf("json.Unmarshal() Error [%s]", err.Error())
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() Error [%s]", err.Error())
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("postman2.Collection round trip Mismatch: want [%s], got [%s]",
+			collectionRoundTripJSON, string(data))
+	}
+	if err := col.Validate(); err != nil {
+		t.Errorf("postman2.Collection.Validate() Error [%s]", err.Error())
+	}
+}
+
+// TestCollectionRoundTripModified ensures empty members and path segment
+// objects that were read are replaced by modified values.
+func TestCollectionRoundTripModified(t *testing.T) {
+	col := Collection{}
+	if err := json.Unmarshal([]byte(collectionRoundTripJSON), &col); err != nil {
+		t.Fatalf("json.Unmarshal() Error [%s]", err.Error())
+	}
+	query := col.Item[0].Item[1]
+	query.Request.URL.Path = StringList{"v2", "graphql"}
+	query.Request.URL.Query = []URLQuery{{Key: "debug", Value: "1"}}
+	query.Response = []Response{{Name: "OK", Code: 200}}
+	binary := col.Item[0].Item[2]
+	binary.Request.Header[0].Disabled = true
+
+	data, err := json.Marshal(col.Item[0])
+	if err != nil {
+		t.Fatalf("json.Marshal() Error [%s]", err.Error())
+	}
+	got := Item{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() Error [%s]", err.Error())
+	}
+	gotQuery := got.Item[1]
+	if !reflect.DeepEqual(gotQuery.Request.URL.Path, StringList{"v2", "graphql"}) ||
+		len(gotQuery.Request.URL.Query) != 1 || len(gotQuery.Response) != 1 ||
+		!got.Item[2].Request.Header[0].Disabled {
+		t.Errorf("postman2.Item modified round trip Mismatch: got [%s]", string(data))
+	}
+}
+
+var collectionItemTests = []struct {
+	v    string
+	want string
+}{
+	{`{"info":{"name":"A"},"variables":[]}`, `{"info":{"name":"A"},"variables":[]}`},
+	{`{"info":{"name":"A"},"item":[]}`, `{"info":{"name":"A"},"item":[]}`},
+	{`{"info":{"name":"A"},"item":null}`, `{"info":{"name":"A"},"item":[]}`},
+}
+
+// TestCollectionItem ensures a collection read without `item` is written
+// without it and that a nil `Item` is otherwise written as `[]`.
+func TestCollectionItem(t *testing.T) {
+	for _, tt := range collectionItemTests {
+		col := Collection{}
+		if err := json.Unmarshal([]byte(tt.v), &col); err != nil {
+			t.Fatalf("json.Unmarshal() Error [%s]", err.Error())
+		}
+		data, err := json.Marshal(col)
+		if err != nil {
+			t.Fatalf("json.Marshal() Error [%s]", err.Error())
+		}
+		if string(data) != tt.want {
+			t.Errorf("postman2.Collection round trip Mismatch: want [%s], got [%s]", tt.want, string(data))
+		}
+	}
+
+	col := Collection{Info: CollectionInfo{Name: "A", Schema: SchemaURL210}}
+	data, err := json.Marshal(col)
+	if err != nil {
+		t.Fatalf("json.Marshal() Error [%s]", err.Error())
+	}
+	if err := ValidateCollectionBytes(data); err != nil {
+		t.Errorf("postman2.ValidateCollectionBytes(\"%s\") Error [%s]", string(data), err.Error())
+	}
+
+	colRead := Collection{}
+	if err := json.Unmarshal([]byte(collectionItemTests[0].v), &colRead); err != nil {
+		t.Fatalf("json.Unmarshal() Error [%s]", err.Error())
+	}
+	colRead.GetOrNewFolder("Folder")
+	if data, err := json.Marshal(colRead); err != nil {
+		t.Fatalf("json.Marshal() Error [%s]", err.Error())
+	} else if want := `{"info":{"name":"A"},"item":[{"name":"Folder"}],"variables":[]}`; string(data) != want {
+		t.Errorf("postman2.Collection Mismatch: want [%s], got [%s]", want, string(data))
+	}
+}
+
+var validateCollectionTests = []struct {
+	v     string
+	valid bool
+}{
+	{`{"info":{"name":"A","schema":"` + SchemaURL210 + `"},"item":[]}`, true},
+	{`{"info":{"name":"A","schema":"` + SchemaURL210 + `"},"item":[{"name":"R","request":"https://example.com"}]}`, true},
+	{`{"info":{"name":"A"},"item":[]}`, false},
+	{`{"info":{"name":"A","schema":"` + SchemaURL210 + `"}}`, false},
+	{`{"info":{"name":"A","schema":"` + SchemaURL210 + `"},"item":[{"name":"R","request":{"header":[{"key":"X"}]}}]}`, false},
+	{`{"info":{"name":"A","schema":"` + SchemaURL210 + `"},"item":[],"auth":{"type":"unknown"}}`, false},
+}
+
+// TestValidateCollectionBytes ensures collections are checked against the
+// local v2.1.0 schema.
+func TestValidateCollectionBytes(t *testing.T) {
+	for _, tt := range validateCollectionTests {
+		err := ValidateCollectionBytes([]byte(tt.v))
+		if (err == nil) != tt.valid {
+			t.Errorf("postman2.ValidateCollectionBytes(\"%s\") Mismatch: want [%v], got [%v]",
+				tt.v, tt.valid, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package postman2
+
+import (
+	"encoding/json"
+	"os"
+)
+
+const (
+	EnvironmentValueTypeDefault = "default"
+	EnvironmentValueTypeSecret  = "secret"
+
+	VariableScopeEnvironment = "environment"
+)
+
+// Environment is a Postman environment file, which provides variable values
+// that override collection variables.
+type Environment struct {
+	ID                   string             `json:"id,omitempty"`
+	Name                 string             `json:"name"`
+	Values               []EnvironmentValue `json:"values"`
+	PostmanVariableScope string             `json:"_postman_variable_scope,omitempty"`
+}
+
+type EnvironmentValue struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"` // `default` or `secret`
+	Enabled     bool   `json:"enabled"`
+	Description string `json:"description,omitempty"`
+}
+
+func NewEnvironment(name string) Environment {
+	return Environment{
+		Name:                 name,
+		Values:               []EnvironmentValue{},
+		PostmanVariableScope: VariableScopeEnvironment}
+}
+
+// Set adds or replaces a value by key.
+func (env *Environment) Set(val EnvironmentValue) {
+	for i, try := range env.Values {
+		if try.Key == val.Key {
+			env.Values[i] = val
+			return
+		}
+	}
+	env.Values = append(env.Values, val)
+}
+
+func ReadEnvironmentFile(filename string) (Environment, error) {
+	env := Environment{}
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return env, err
+	}
+	err = json.Unmarshal(b, &env)
+	return env, err
+}
+
+func (env Environment) WriteFile(filename string, perm os.FileMode) error {
+	b, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, perm)
+}
@@ -0,0 +1,201 @@
+package postman2
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Extra holds JSON object members that are not modeled by a struct, such
+// as `_postman_*` and exporter fields, so they are written back unchanged.
+// Modeled members read with an empty value, such as `"header": []` or
+// `"disabled": false`, are also kept because `omitempty` would drop them.
+// They are written back only while the field is still empty.
+type Extra map[string]json.RawMessage
+
+// unmarshalWithExtra decodes `data` into `v`, a pointer to a struct without
+// a custom `UnmarshalJSON`, and returns the members not matching a field
+// and the matching members with empty values.
+func unmarshalWithExtra(data []byte, v any) (Extra, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	all := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	extra := Extra{}
+	for key, raw := range all {
+		if !known[key] || jsonIsEmpty(raw) {
+			extra[key] = raw
+		}
+	}
+	if len(extra) == 0 {
+		return nil, nil
+	}
+	return extra, nil
+}
+
+// marshalWithExtra encodes `v`, a struct without a custom `MarshalJSON`,
+// and appends the `extra` members not already written, sorted by key.
+func marshalWithExtra(v any, extra Extra) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	written := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &written); err != nil {
+		return nil, err
+	}
+	keys := []string{}
+	for key := range extra {
+		if _, ok := written[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	buf := bytes.NewBuffer(data[:len(data)-1])
+	for _, key := range keys {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		buf.Write(extra[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+var jsonFieldNamesCache sync.Map
+
+// jsonFieldNames returns the JSON member names for a struct type, including
+// fields of embedded structs.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if names, ok := jsonFieldNamesCache.Load(t); ok {
+		return names.(map[string]bool)
+	}
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && len(name) == 0 {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for embedded := range jsonFieldNames(ft) {
+					names[embedded] = true
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	jsonFieldNamesCache.Store(t, names)
+	return names
+}
+
+// jsonIsString reports whether `data` is a JSON string.
+func jsonIsString(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '"'
+}
+
+// jsonIsEmpty reports whether `data` is `null`, `false`, `0`, `""`, `[]`
+// or `{}`, which `omitempty` fields do not write.
+func jsonIsEmpty(data []byte) bool {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return false
+	}
+	switch val := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !val
+	case float64:
+		return val == 0
+	case string:
+		return len(val) == 0
+	case []any:
+		return len(val) == 0
+	case map[string]any:
+		return len(val) == 0
+	}
+	return false
+}
+
+// jsonHasObject reports whether `data` is a JSON array with an object
+// element, such as a URL path with `{"type":..,"value":..}` segments.
+func jsonHasObject(data []byte) bool {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return false
+	}
+	for _, raw := range raws {
+		if raw = bytes.TrimSpace(raw); len(raw) > 0 && raw[0] == '{' {
+			return true
+		}
+	}
+	return false
+}
+
+// StringList is a list of strings that can also be read from a single JSON
+// string, as used by script `exec`, URL `host` and URL `path`. Path segment
+// objects are read as their `value`, see `URL` for how they are written.
+type StringList []string
+
+func (sl *StringList) UnmarshalJSON(data []byte) error {
+	if jsonIsString(data) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*sl = StringList{s}
+		return nil
+	}
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return err
+	}
+	list := StringList{}
+	for _, raw := range raws {
+		if jsonIsString(raw) {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return err
+			}
+			list = append(list, s)
+			continue
+		}
+		seg := struct {
+			Value string `json:"value"`
+		}{}
+		if err := json.Unmarshal(raw, &seg); err != nil {
+			return err
+		}
+		list = append(list, seg.Value)
+	}
+	*sl = list
+	return nil
+}
@@ -0,0 +1,424 @@
+// postman2openapi3 converts Postman 2 collections to OpenAPI 3 specs.
+package postman2openapi3
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/net/http/httputilmore"
+	"github.com/grokify/mogo/text/stringcase"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/openapi3edit"
+	"github.com/grokify/spectrum/postman2"
+	"github.com/grokify/spectrum/postman2/simple"
+)
+
+// Configuration holds spec values that are not in Postman collections.
+type Configuration struct {
+	OpenAPIVersion string `json:"openapiVersion,omitempty"` // defaults to `openapi3.OASVersionDefault`
+	APIVersion     string `json:"apiVersion,omitempty"`
+	// ServerURL overrides servers built from request URL hosts.
+	ServerURL string `json:"serverUrl,omitempty"`
+}
+
+// ConvertFile reads a simple or object URL Postman 2 collection file and
+// converts it to an OpenAPI 3 spec.
+func ConvertFile(cfg Configuration, filename string) (*openapi3.Spec, error) {
+	col, err := simple.ReadCanonicalCollection(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Convert(cfg, col)
+}
+
+// Convert converts a Postman 2 collection to an OpenAPI 3 spec. Folders
+// become tags, URL variables become path parameters, query entries become
+// query parameters and headers become header parameters. Raw JSON bodies
+// and saved responses become inferred schemas and examples. When a path
+// and method appears more than once, the first item is used.
+func Convert(cfg Configuration, col postman2.Collection) (*openapi3.Spec, error) {
+	oasVersion := strings.TrimSpace(cfg.OpenAPIVersion)
+	if len(oasVersion) == 0 {
+		oasVersion = openapi3.OASVersionDefault
+	}
+	spec := openapi3.NewSpec(oasVersion, col.Info.Name, cfg.APIVersion)
+	spec.Info.Description = strings.TrimSpace(col.Info.Description.String())
+	spec.Paths = oas3.NewPaths()
+	conv := &converter{
+		spec:         spec,
+		variables:    map[string]string{},
+		operationIDs: map[string]int{},
+		servers:      map[string]bool{}}
+	for _, v := range col.Variable {
+		if s, ok := v.Value.(string); ok {
+			conv.variables[v.Key] = s
+		}
+	}
+	if err := conv.addItems(col.Item, ""); err != nil {
+		return spec, err
+	}
+	if serverURL := strings.TrimSpace(cfg.ServerURL); len(serverURL) > 0 {
+		spec.Servers = oas3.Servers{{URL: serverURL}}
+	}
+	return spec, nil
+}
+
+type converter struct {
+	spec         *openapi3.Spec
+	variables    map[string]string
+	operationIDs map[string]int
+	servers      map[string]bool
+}
+
+func (conv *converter) addItems(items []*postman2.Item, tagName string) error {
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		if item.Request == nil {
+			conv.addTag(item)
+			if err := conv.addItems(item.Item, strings.TrimSpace(item.Name)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := conv.addOperation(item, tagName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (conv *converter) addTag(folder *postman2.Item) {
+	name := strings.TrimSpace(folder.Name)
+	if len(name) == 0 || conv.spec.Tags.Get(name) != nil {
+		return
+	}
+	tag := &oas3.Tag{Name: name}
+	if folder.Description != nil {
+		tag.Description = strings.TrimSpace(folder.Description.String())
+	}
+	conv.spec.Tags = append(conv.spec.Tags, tag)
+}
+
+func (conv *converter) addOperation(item *postman2.Item, tagName string) error {
+	req := item.Request
+	method := strings.ToUpper(strings.TrimSpace(req.Method))
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	pmURL := postman2.URL{}
+	if req.URL != nil {
+		pmURL = *req.URL
+		if pmURL.IsRawOnly() {
+			raw := strings.SplitN(pmURL.Raw, "?", 2)[0]
+			pmURL = postman2.NewURL(raw)
+			if strings.HasPrefix(strings.TrimSpace(raw), "/") {
+				pmURL.Path = strings.Split(strings.TrimSpace(raw), "/")
+			}
+			pmURL.Query = req.URL.Query
+			pmURL.Variable = req.URL.Variable
+		}
+	}
+	path, pathParamNames := conv.pathForURL(pmURL)
+	sm := openapi3.SpecMore{Spec: conv.spec}
+	if existing, err := sm.OperationByPathMethod(path, method); err == nil && existing != nil {
+		return nil
+	}
+
+	op := oas3.NewOperation()
+	op.Responses = oas3.NewResponses()
+	op.Summary = strings.TrimSpace(item.Name)
+	op.Description = strings.TrimSpace(req.Description.String())
+	op.OperationID = conv.operationID(item.Name, method, path)
+	if len(tagName) > 0 {
+		op.Tags = []string{tagName}
+	}
+	ope := openapi3edit.NewOperationEdit(path, method, op)
+
+	for _, name := range pathParamNames {
+		param := oas3.NewPathParameter(name).WithSchema(oas3.NewStringSchema())
+		for _, v := range pmURL.Variable {
+			if v.Key == name || v.ID == name {
+				param.Description = strings.TrimSpace(v.Description.String())
+				if s, ok := v.Value.(string); ok {
+					param.Example = exampleString(s)
+				}
+			}
+		}
+		op.AddParameter(param)
+	}
+	seen := map[string]bool{}
+	for _, q := range pmURL.Query {
+		if len(q.Key) == 0 || seen[q.Key] {
+			continue
+		}
+		seen[q.Key] = true
+		sch, example := schemaInferString(q.Value)
+		param := oas3.NewQueryParameter(q.Key).
+			WithDescription(strings.TrimSpace(q.Description.String())).
+			WithSchema(sch)
+		param.Example = example
+		op.AddParameter(param)
+	}
+	reqMediaType := ""
+	for _, h := range req.Header {
+		switch {
+		case strings.EqualFold(h.Key, httputilmore.HeaderContentType):
+			reqMediaType = mediaTypeBase(h.Value)
+		case strings.EqualFold(h.Key, httputilmore.HeaderAccept),
+			strings.EqualFold(h.Key, httputilmore.HeaderAuthorization),
+			len(h.Key) == 0:
+			// OpenAPI ignores these header parameters.
+		default:
+			param := oas3.NewHeaderParameter(h.Key).
+				WithDescription(strings.TrimSpace(h.Description.String())).
+				WithSchema(oas3.NewStringSchema())
+			param.Example = exampleString(h.Value)
+			op.AddParameter(param)
+		}
+	}
+
+	if err := conv.setRequestBody(&ope, req.Body, reqMediaType); err != nil {
+		return err
+	}
+	if err := conv.setResponses(&ope, item.Response); err != nil {
+		return err
+	}
+	sm.SetOperation(path, method, op)
+	return nil
+}
+
+var (
+	rxPostmanVariable = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+	rxPathVariable    = regexp.MustCompile(`{([^{}]+)}`)
+)
+
+// pathForURL returns the OpenAPI path and path parameter names. Path
+// segments such as `:id` and `{{id}}` become `{id}`. The URL host is
+// added to the spec servers.
+func (conv *converter) pathForURL(pmURL postman2.URL) (string, []string) {
+	conv.addServer(pmURL)
+	segments := []string{}
+	for _, seg := range pmURL.Path {
+		seg = strings.TrimSpace(seg)
+		if len(seg) == 0 {
+			continue
+		}
+		if strings.HasPrefix(seg, ":") {
+			seg = "{" + seg[1:] + "}"
+		}
+		segments = append(segments, rxPostmanVariable.ReplaceAllString(seg, "{$1}"))
+	}
+	path := "/" + strings.Join(segments, "/")
+	names := []string{}
+	for _, m := range rxPathVariable.FindAllStringSubmatch(path, -1) {
+		names = append(names, m[1])
+	}
+	return path, names
+}
+
+// addServer adds a server for the URL protocol and host. Postman variables
+// become server variables with collection variable values as defaults.
+func (conv *converter) addServer(pmURL postman2.URL) {
+	host := strings.Join(pmURL.Host, ".")
+	if len(strings.TrimSpace(host)) == 0 {
+		return
+	}
+	serverURL := host
+	if len(pmURL.Protocol) > 0 {
+		serverURL = pmURL.Protocol + "://" + host
+	}
+	serverURL = rxPostmanVariable.ReplaceAllString(serverURL, "{$1}")
+	if conv.servers[serverURL] {
+		return
+	}
+	conv.servers[serverURL] = true
+	server := &oas3.Server{URL: serverURL}
+	for _, m := range rxPathVariable.FindAllStringSubmatch(serverURL, -1) {
+		if server.Variables == nil {
+			server.Variables = map[string]*oas3.ServerVariable{}
+		}
+		server.Variables[m[1]] = &oas3.ServerVariable{Default: conv.variables[m[1]]}
+	}
+	conv.spec.Servers = append(conv.spec.Servers, server)
+}
+
+// operationID returns a unique camelCase operation ID from the item name,
+// or from the method and path when the name is empty.
+func (conv *converter) operationID(name, method, path string) string {
+	name = strings.TrimSpace(name)
+	if len(name) == 0 {
+		name = method + " " + rxPathVariable.ReplaceAllString(path, "by $1")
+	}
+	opID := stringcase.ToCamelCase(strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}), " "))
+	conv.operationIDs[opID]++
+	if count := conv.operationIDs[opID]; count > 1 {
+		opID += strconv.Itoa(count)
+	}
+	return opID
+}
+
+func (conv *converter) setRequestBody(ope *openapi3edit.OperationEdit, body *postman2.RequestBody, mediaType string) error {
+	if body == nil {
+		return nil
+	}
+	var sch *oas3.Schema
+	var example any
+	switch body.Mode {
+	case postman2.BodyModeRaw:
+		if len(strings.TrimSpace(body.Raw)) == 0 {
+			return nil
+		}
+		if len(mediaType) == 0 {
+			mediaType = httputilmore.ContentTypeTextPlain
+			if body.Options != nil && body.Options.Raw != nil {
+				switch body.Options.Raw.Language {
+				case "json":
+					mediaType = httputilmore.ContentTypeAppJSON
+				case "xml":
+					mediaType = httputilmore.ContentTypeAppXML
+				}
+			}
+		}
+		sch, example = schemaExampleForBody(mediaType, body.Raw)
+	case postman2.BodyModeURLEncoded:
+		mediaType = httputilmore.ContentTypeAppFormURLEncoded
+		sch = oas3.NewObjectSchema()
+		obj := map[string]any{}
+		for _, p := range body.URLEncoded {
+			propSch, propExample := schemaInferString(p.Value)
+			sch.WithProperty(p.Key, propSch)
+			if propExample != nil {
+				obj[p.Key] = propExample
+			}
+		}
+		if len(obj) > 0 {
+			example = obj
+		}
+	case postman2.BodyModeFormData:
+		mediaType = httputilmore.ContentTypeMultipartFormData
+		sch = oas3.NewObjectSchema()
+		for _, p := range body.FormData {
+			if p.Type == postman2.FormDataTypeFile {
+				sch.WithProperty(p.Key, oas3.NewStringSchema().WithFormat("binary"))
+			} else {
+				propSch, _ := schemaInferString(p.Value)
+				sch.WithProperty(p.Key, propSch)
+			}
+		}
+	default:
+		return nil
+	}
+	if err := ope.SetRequestBodySchemaRef(mediaType, oas3.NewSchemaRef("", sch)); err != nil {
+		return err
+	}
+	if example != nil {
+		ope.Operation.RequestBody.Value.Content[mediaType].Example = example
+	}
+	return nil
+}
+
+// setResponses adds responses from saved examples. When a status code has
+// several examples, the first is used. Operations without saved examples
+// get a `200` response since OpenAPI requires at least one response.
+func (conv *converter) setResponses(ope *openapi3edit.OperationEdit, resps []postman2.Response) error {
+	for _, resp := range resps {
+		code := resp.Code
+		if code == 0 {
+			code = http.StatusOK
+		}
+		statusCode := strconv.Itoa(code)
+		if ope.Operation.Responses.Value(statusCode) != nil {
+			continue
+		}
+		description := strings.TrimSpace(resp.Name)
+		if len(description) == 0 {
+			description = http.StatusText(code)
+		}
+		mediaType := ""
+		headers := oas3.Headers{}
+		for _, h := range resp.Header {
+			if strings.EqualFold(h.Key, httputilmore.HeaderContentType) {
+				mediaType = mediaTypeBase(h.Value)
+			} else if len(h.Key) > 0 {
+				hdr := &oas3.Header{Parameter: oas3.Parameter{
+					Description: strings.TrimSpace(h.Description.String()),
+					Schema:      oas3.NewSchemaRef("", oas3.NewStringSchema()),
+					Example:     exampleString(h.Value)}}
+				headers[h.Key] = &oas3.HeaderRef{Value: hdr}
+			}
+		}
+		if len(strings.TrimSpace(resp.Body)) > 0 {
+			if len(mediaType) == 0 {
+				mediaType = httputilmore.ContentTypeTextPlain
+				if json.Valid([]byte(resp.Body)) {
+					mediaType = httputilmore.ContentTypeAppJSON
+				}
+			}
+			sch, example := schemaExampleForBody(mediaType, resp.Body)
+			if err := ope.SetResponseBodySchemaRefMore(statusCode, description, mediaType, oas3.NewSchemaRef("", sch)); err != nil {
+				return err
+			}
+			ope.Operation.Responses.Value(statusCode).Value.Content[mediaType].Example = example
+		} else {
+			ope.Operation.Responses.Set(statusCode, &oas3.ResponseRef{
+				Value: oas3.NewResponse().WithDescription(description)})
+		}
+		if len(headers) > 0 {
+			ope.Operation.Responses.Value(statusCode).Value.Headers = headers
+		}
+	}
+	if ope.Operation.Responses.Len() == 0 {
+		ope.Operation.Responses.Set(strconv.Itoa(http.StatusOK), &oas3.ResponseRef{
+			Value: oas3.NewResponse().WithDescription(http.StatusText(http.StatusOK))})
+	}
+	return nil
+}
+
+// schemaExampleForBody infers a schema and example from a body. JSON bodies
+// that cannot be parsed, such as bodies with unquoted Postman variables,
+// are treated as strings.
+func schemaExampleForBody(mediaType, body string) (*oas3.Schema, any) {
+	if strings.Contains(strings.ToLower(mediaType), "json") {
+		var v any
+		if err := json.Unmarshal([]byte(body), &v); err == nil {
+			return openapi3.SchemaInfer(v), v
+		}
+	}
+	return oas3.NewStringSchema(), body
+}
+
+// schemaInferString infers a schema and typed example for a query or
+// form value. Values with Postman variables are strings without examples.
+func schemaInferString(s string) (*oas3.Schema, any) {
+	s = strings.TrimSpace(s)
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return oas3.NewIntegerSchema(), i
+	} else if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return oas3.NewFloat64Schema(), f
+	} else if b, err := strconv.ParseBool(s); err == nil {
+		return oas3.NewBoolSchema(), b
+	}
+	return oas3.NewStringSchema(), exampleString(s)
+}
+
+// exampleString returns nil for empty values and values that are Postman
+// variables, which are not useful examples.
+func exampleString(s string) any {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 || rxPostmanVariable.MatchString(s) {
+		return nil
+	}
+	return s
+}
+
+func mediaTypeBase(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+}
@@ -0,0 +1,124 @@
+package postman2openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	oas3 "github.com/getkin/kin-openapi/openapi3"
+	"github.com/grokify/mogo/type/maputil"
+	"github.com/grokify/spectrum/openapi3"
+	"github.com/grokify/spectrum/postman2"
+)
+
+const convertCollection = `{
+"info":{"name":"Pet Store","description":"Pets API","schema":"https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+"variable":[{"key":"baseUrl","value":"api.example.com"}],
+"item":[
+  {"name":"Pets","description":"Pet operations","item":[
+    {"name":"Get Pet","request":{"method":"GET",
+      "header":[{"key":"X-Request-Id","value":"abc123","description":"Trace id"},{"key":"Accept","value":"application/json"}],
+      "url":{"raw":"https://{{baseUrl}}/pets/:petId?verbose=true&limit=10","protocol":"https","host":["{{baseUrl}}"],"path":["pets",":petId"],
+        "query":[{"key":"verbose","value":"true"},{"key":"limit","value":"10","description":"Max items"}],
+        "variable":[{"key":"petId","value":"42","description":"Pet id"}]}},
+      "response":[
+        {"name":"Found","code":200,"status":"OK","header":[{"key":"Content-Type","value":"application/json"},{"key":"X-Rate-Limit","value":"100"}],
+          "body":"{\"id\":42,\"name\":\"Tom\"}"},
+        {"name":"Missing","code":404,"status":"Not Found","body":"not found"}]},
+    {"name":"Create Pet","request":{"method":"POST",
+      "header":[{"key":"Content-Type","value":"application/json"}],
+      "url":{"raw":"https://{{baseUrl}}/pets","protocol":"https","host":["{{baseUrl}}"],"path":["pets"]},
+      "body":{"mode":"raw","raw":"{\"name\":\"Tom\",\"age\":3}"}}}]},
+  {"name":"Status","request":{"method":"GET","url":"https://{{baseUrl}}/status"}}]}`
+
+var convertTests = []struct {
+	path   string
+	method string
+	want   string
+}{
+	{"/pets/{petId}", "GET", "getPet|Pets|path petId 42,query verbose true,query limit 10,header X-Request-Id abc123||200 application/json {\"id\":42,\"name\":\"Tom\"} X-Rate-Limit,404 text/plain \"not found\""},
+	{"/pets", "POST", "createPet|Pets||application/json {\"age\":3,\"name\":\"Tom\"}|200"},
+	{"/status", "GET", "status||||200"},
+}
+
+// TestConvert ensures folders become tags, URL variables, queries and
+// headers become parameters, raw bodies become request bodies, saved
+// responses become responses and the resulting spec is valid.
+func TestConvert(t *testing.T) {
+	col := postman2.Collection{}
+	if err := json.Unmarshal([]byte(convertCollection), &col); err != nil {
+		t.Fatalf("json.Unmarshal() Error [%s]", err.Error())
+	}
+	spec, err := Convert(Configuration{APIVersion: "1.0.0"}, col)
+	if err != nil {
+		t.Fatalf("postman2openapi3.Convert() Error [%s]", err.Error())
+	}
+	if spec.Info.Title != "Pet Store" || spec.Info.Description != "Pets API" {
+		t.Errorf("postman2openapi3.Convert() Info Mismatch: got [%s] [%s]", spec.Info.Title, spec.Info.Description)
+	}
+	if len(spec.Tags) != 1 || spec.Tags[0].Name != "Pets" || spec.Tags[0].Description != "Pet operations" {
+		t.Errorf("postman2openapi3.Convert() Tags Mismatch: want [Pets]")
+	}
+	if len(spec.Servers) != 1 || spec.Servers[0].URL != "https://{baseUrl}" ||
+		spec.Servers[0].Variables["baseUrl"].Default != "api.example.com" {
+		t.Errorf("postman2openapi3.Convert() Servers Mismatch: want [https://{baseUrl}] with default [api.example.com]")
+	}
+	for _, tt := range convertTests {
+		pathItem := spec.Paths.Find(tt.path)
+		if pathItem == nil || pathItem.GetOperation(tt.method) == nil {
+			t.Fatalf("postman2openapi3.Convert() Mismatch: want operation [%s %s]", tt.method, tt.path)
+		}
+		if got := convertTestOperation(t, pathItem.GetOperation(tt.method)); got != tt.want {
+			t.Errorf("postman2openapi3.Convert() Mismatch: [%s %s] want [%s], got [%s]", tt.method, tt.path, tt.want, got)
+		}
+	}
+	sm := openapi3.SpecMore{Spec: spec}
+	if err := sm.Validate(); err != nil {
+		t.Errorf("SpecMore.Validate() Error [%s]", err.Error())
+	}
+}
+
+// convertTestOperation returns the operation ID, tags, parameters as
+// `in name example`, request body and responses as
+// `code mediaType example headers`, separated by `|`.
+func convertTestOperation(t *testing.T, op *oas3.Operation) string {
+	t.Helper()
+	params := []string{}
+	for _, paramRef := range op.Parameters {
+		params = append(params, fmt.Sprintf("%s %s %v", paramRef.Value.In, paramRef.Value.Name, paramRef.Value.Example))
+	}
+	reqBody := ""
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		reqBody = convertTestContent(t, op.RequestBody.Value.Content)
+	}
+	resps := []string{}
+	respsMap := op.Responses.Map()
+	for _, statusCode := range maputil.Keys(respsMap) {
+		parts := []string{statusCode}
+		if content := convertTestContent(t, respsMap[statusCode].Value.Content); len(content) > 0 {
+			parts = append(parts, content)
+		}
+		parts = append(parts, maputil.Keys(respsMap[statusCode].Value.Headers)...)
+		resps = append(resps, strings.Join(parts, " "))
+	}
+	return strings.Join([]string{
+		op.OperationID,
+		strings.Join(op.Tags, ","),
+		strings.Join(params, ","),
+		reqBody,
+		strings.Join(resps, ",")}, "|")
+}
+
+func convertTestContent(t *testing.T, content oas3.Content) string {
+	t.Helper()
+	parts := []string{}
+	for _, mediaType := range maputil.Keys(content) {
+		bytes, err := json.Marshal(content[mediaType].Example)
+		if err != nil {
+			t.Fatalf("json.Marshal() Error [%s]", err.Error())
+		}
+		parts = append(parts, mediaType+" "+string(bytes))
+	}
+	return strings.Join(parts, ",")
+}
@@ -1,28 +1,399 @@
 package postman2
 
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Request is a Postman request. Requests read from a JSON string URL are
+// written back as strings when only the raw URL is set.
 type Request struct {
 	URL         *URL         `json:"url,omitempty"`
 	Method      string       `json:"method,omitempty"`
 	Header      []Header     `json:"header,omitempty"`
 	Body        *RequestBody `json:"body,omitempty"`
-	Description string       `json:"description,omitempty"`
+	Description *Description `json:"description,omitempty"`
+	Auth        *Auth        `json:"auth,omitempty"`
+	Proxy       *ProxyConfig `json:"proxy,omitempty"`
+	Certificate *Certificate `json:"certificate,omitempty"`
+	Extra       Extra        `json:"-"`
+	isString    bool
+}
+
+func (req *Request) UnmarshalJSON(data []byte) error {
+	if jsonIsString(data) {
+		var raw string
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		*req = Request{URL: &URL{Raw: raw, isString: true}, isString: true}
+		return nil
+	}
+	type request Request
+	v := struct {
+		*request
+		Header json.RawMessage `json:"header,omitempty"`
+	}{request: &request{}}
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*req = Request(*v.request)
+	req.Extra = extra
+	req.Header, err = unmarshalHeaders(v.Header)
+	return err
+}
+
+func (req Request) MarshalJSON() ([]byte, error) {
+	if req.isString && req.URL != nil && req.URL.IsRawOnly() &&
+		len(req.Method) == 0 && len(req.Header) == 0 && req.Body == nil &&
+		req.Description == nil && req.Auth == nil && req.Proxy == nil &&
+		req.Certificate == nil && len(req.Extra) == 0 {
+		return json.Marshal(req.URL.Raw)
+	}
+	type request Request
+	return marshalWithExtra(request(req), req.Extra)
 }
 
 type Header struct {
-	Key         string `json:"key,omitempty"`
-	Value       string `json:"value,omitempty"`
-	Description string `json:"description,omitempty"`
+	Key         string       `json:"key,omitempty"`
+	Value       string       `json:"value,omitempty"`
+	Type        string       `json:"type,omitempty"`
+	Disabled    bool         `json:"disabled,omitempty"`
+	Description *Description `json:"description,omitempty"`
+	Extra       Extra        `json:"-"`
+}
+
+func (h *Header) UnmarshalJSON(data []byte) error {
+	type header Header
+	var v header
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*h = Header(v)
+	h.Extra = extra
+	return nil
+}
+
+func (h Header) MarshalJSON() ([]byte, error) {
+	type header Header
+	return marshalWithExtra(header(h), h.Extra)
 }
 
+// unmarshalHeaders reads a header list, which can be an array of header
+// objects and `Key: Value` strings, a single string of `Key: Value` lines
+// or null.
+func unmarshalHeaders(data json.RawMessage) ([]Header, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	if jsonIsString(data) {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		return parseHeaderLines(s), nil
+	}
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, err
+	}
+	headers := []Header{}
+	for _, raw := range raws {
+		if jsonIsString(raw) {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, err
+			}
+			headers = append(headers, parseHeaderLines(s)...)
+			continue
+		}
+		h := Header{}
+		if err := json.Unmarshal(raw, &h); err != nil {
+			return nil, err
+		}
+		headers = append(headers, h)
+	}
+	return headers, nil
+}
+
+func parseHeaderLines(s string) []Header {
+	headers := []Header{}
+	for _, line := range strings.Split(s, "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(strings.TrimSpace(parts[0])) == 0 {
+			continue
+		}
+		h := Header{Key: strings.TrimSpace(parts[0])}
+		if len(parts) > 1 {
+			h.Value = strings.TrimSpace(parts[1])
+		}
+		headers = append(headers, h)
+	}
+	return headers
+}
+
+const (
+	BodyModeFile       = "file"
+	BodyModeFormData   = "formdata"
+	BodyModeGraphQL    = "graphql"
+	BodyModeRaw        = "raw"
+	BodyModeURLEncoded = "urlencoded"
+
+	FormDataTypeFile = "file"
+	FormDataTypeText = "text"
+)
+
 type RequestBody struct {
-	Mode       string            `json:"mode,omitempty"` // `raw`, `urlencoded`, `formdata`,`file`,`graphql`
-	Raw        string            `json:"raw,omitempty"`
-	URLEncoded []URLEncodedParam `json:"urlencoded,omitempty"`
+	Mode       string              `json:"mode,omitempty"` // `raw`, `urlencoded`, `formdata`,`file`,`graphql`
+	Raw        string              `json:"raw,omitempty"`
+	URLEncoded []URLEncodedParam   `json:"urlencoded,omitempty"`
+	FormData   []FormDataParam     `json:"formdata,omitempty"`
+	File       *RequestBodyFile    `json:"file,omitempty"`
+	GraphQL    *RequestBodyGraphQL `json:"graphql,omitempty"`
+	Options    *RequestBodyOptions `json:"options,omitempty"`
+	Disabled   bool                `json:"disabled,omitempty"`
+	Extra      Extra               `json:"-"`
+}
+
+func (body *RequestBody) UnmarshalJSON(data []byte) error {
+	type requestBody RequestBody
+	var v requestBody
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*body = RequestBody(v)
+	body.Extra = extra
+	return nil
+}
+
+func (body RequestBody) MarshalJSON() ([]byte, error) {
+	type requestBody RequestBody
+	return marshalWithExtra(requestBody(body), body.Extra)
+}
+
+type RequestBodyFile struct {
+	Src     any    `json:"src,omitempty"`
+	Content string `json:"content,omitempty"`
+	Extra   Extra  `json:"-"`
+}
+
+func (file *RequestBodyFile) UnmarshalJSON(data []byte) error {
+	type requestBodyFile RequestBodyFile
+	var v requestBodyFile
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*file = RequestBodyFile(v)
+	file.Extra = extra
+	return nil
+}
+
+func (file RequestBodyFile) MarshalJSON() ([]byte, error) {
+	type requestBodyFile RequestBodyFile
+	return marshalWithExtra(requestBodyFile(file), file.Extra)
+}
+
+// RequestBodyGraphQL is a GraphQL body. `Variables` is a JSON string.
+type RequestBodyGraphQL struct {
+	Query     string `json:"query,omitempty"`
+	Variables string `json:"variables,omitempty"`
+	Extra     Extra  `json:"-"`
+}
+
+func (gql *RequestBodyGraphQL) UnmarshalJSON(data []byte) error {
+	type requestBodyGraphQL RequestBodyGraphQL
+	var v requestBodyGraphQL
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*gql = RequestBodyGraphQL(v)
+	gql.Extra = extra
+	return nil
+}
+
+func (gql RequestBodyGraphQL) MarshalJSON() ([]byte, error) {
+	type requestBodyGraphQL RequestBodyGraphQL
+	return marshalWithExtra(requestBodyGraphQL(gql), gql.Extra)
+}
+
+// RequestBodyOptions sets the language Postman uses to display a raw body.
+// Options for other modes are kept in `Extra`.
+type RequestBodyOptions struct {
+	Raw   *RequestBodyOptionsRaw `json:"raw,omitempty"`
+	Extra Extra                  `json:"-"`
+}
+
+func (opts *RequestBodyOptions) UnmarshalJSON(data []byte) error {
+	type requestBodyOptions RequestBodyOptions
+	var v requestBodyOptions
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*opts = RequestBodyOptions(v)
+	opts.Extra = extra
+	return nil
+}
+
+func (opts RequestBodyOptions) MarshalJSON() ([]byte, error) {
+	type requestBodyOptions RequestBodyOptions
+	return marshalWithExtra(requestBodyOptions(opts), opts.Extra)
+}
+
+type RequestBodyOptionsRaw struct {
+	Language string `json:"language,omitempty"` // `json`, `xml`, `text`
+	Extra    Extra  `json:"-"`
+}
+
+func (raw *RequestBodyOptionsRaw) UnmarshalJSON(data []byte) error {
+	type requestBodyOptionsRaw RequestBodyOptionsRaw
+	var v requestBodyOptionsRaw
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*raw = RequestBodyOptionsRaw(v)
+	raw.Extra = extra
+	return nil
+}
+
+func (raw RequestBodyOptionsRaw) MarshalJSON() ([]byte, error) {
+	type requestBodyOptionsRaw RequestBodyOptionsRaw
+	return marshalWithExtra(requestBodyOptionsRaw(raw), raw.Extra)
 }
 
 type URLEncodedParam struct {
-	Key     string `json:"key,omitempty"`
-	Value   string `json:"value,omitempty"`
-	Type    string `json:"type,omitempty"`
-	Enabled bool   `json:"enabled,omitempty"`
+	Key         string       `json:"key,omitempty"`
+	Value       string       `json:"value,omitempty"`
+	Type        string       `json:"type,omitempty"`
+	Enabled     bool         `json:"enabled,omitempty"`
+	Disabled    bool         `json:"disabled,omitempty"`
+	Description *Description `json:"description,omitempty"`
+	Extra       Extra        `json:"-"`
+}
+
+func (param *URLEncodedParam) UnmarshalJSON(data []byte) error {
+	type urlEncodedParam URLEncodedParam
+	var v urlEncodedParam
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*param = URLEncodedParam(v)
+	param.Extra = extra
+	return nil
+}
+
+func (param URLEncodedParam) MarshalJSON() ([]byte, error) {
+	type urlEncodedParam URLEncodedParam
+	return marshalWithExtra(urlEncodedParam(param), param.Extra)
+}
+
+type FormDataParam struct {
+	Key         string       `json:"key,omitempty"`
+	Value       string       `json:"value,omitempty"`
+	Type        string       `json:"type,omitempty"` // `text` or `file`
+	Src         any          `json:"src,omitempty"`  // string or array of strings for `file`
+	ContentType string       `json:"contentType,omitempty"`
+	Description *Description `json:"description,omitempty"`
+	Disabled    bool         `json:"disabled,omitempty"`
+	Extra       Extra        `json:"-"`
+}
+
+func (param *FormDataParam) UnmarshalJSON(data []byte) error {
+	type formDataParam FormDataParam
+	var v formDataParam
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*param = FormDataParam(v)
+	param.Extra = extra
+	return nil
+}
+
+func (param FormDataParam) MarshalJSON() ([]byte, error) {
+	type formDataParam FormDataParam
+	return marshalWithExtra(formDataParam(param), param.Extra)
+}
+
+// ProxyConfig is the proxy used for a request.
+type ProxyConfig struct {
+	Match    string `json:"match,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Tunnel   bool   `json:"tunnel,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+	Extra    Extra  `json:"-"`
+}
+
+func (proxy *ProxyConfig) UnmarshalJSON(data []byte) error {
+	type proxyConfig ProxyConfig
+	var v proxyConfig
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*proxy = ProxyConfig(v)
+	proxy.Extra = extra
+	return nil
+}
+
+func (proxy ProxyConfig) MarshalJSON() ([]byte, error) {
+	type proxyConfig ProxyConfig
+	return marshalWithExtra(proxyConfig(proxy), proxy.Extra)
+}
+
+// Certificate is an SSL client certificate for a request.
+type Certificate struct {
+	Name       string           `json:"name,omitempty"`
+	Matches    []string         `json:"matches,omitempty"`
+	Key        *CertificateFile `json:"key,omitempty"`
+	Cert       *CertificateFile `json:"cert,omitempty"`
+	Passphrase string           `json:"passphrase,omitempty"`
+	Extra      Extra            `json:"-"`
+}
+
+func (cert *Certificate) UnmarshalJSON(data []byte) error {
+	type certificate Certificate
+	var v certificate
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*cert = Certificate(v)
+	cert.Extra = extra
+	return nil
+}
+
+func (cert Certificate) MarshalJSON() ([]byte, error) {
+	type certificate Certificate
+	return marshalWithExtra(certificate(cert), cert.Extra)
+}
+
+type CertificateFile struct {
+	Src   any   `json:"src,omitempty"`
+	Extra Extra `json:"-"`
+}
+
+func (file *CertificateFile) UnmarshalJSON(data []byte) error {
+	type certificateFile CertificateFile
+	var v certificateFile
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*file = CertificateFile(v)
+	file.Extra = extra
+	return nil
+}
+
+func (file CertificateFile) MarshalJSON() ([]byte, error) {
+	type certificateFile CertificateFile
+	return marshalWithExtra(certificateFile(file), file.Extra)
 }
@@ -0,0 +1,73 @@
+package postman2
+
+import "encoding/json"
+
+// Response is a saved example response for an item. Saved examples are
+// shown as documentation and are used by Postman mock servers.
+type Response struct {
+	ID                     string   `json:"id,omitempty"`
+	Name                   string   `json:"name,omitempty"`
+	OriginalRequest        *Request `json:"originalRequest,omitempty"`
+	ResponseTime           any      `json:"responseTime,omitempty"` // string or number
+	Timings                any      `json:"timings,omitempty"`
+	Status                 string   `json:"status,omitempty"` // e.g. `OK`
+	Code                   int      `json:"code,omitempty"`
+	PostmanPreviewLanguage string   `json:"_postman_previewlanguage,omitempty"` // `json`, `xml`, `html`, `text`
+	Header                 []Header `json:"header,omitempty"`
+	Cookie                 []Cookie `json:"cookie,omitempty"`
+	Body                   string   `json:"body,omitempty"`
+	Extra                  Extra    `json:"-"`
+}
+
+func (resp *Response) UnmarshalJSON(data []byte) error {
+	type response Response
+	v := struct {
+		*response
+		Header json.RawMessage `json:"header,omitempty"`
+	}{response: &response{}}
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*resp = Response(*v.response)
+	resp.Extra = extra
+	resp.Header, err = unmarshalHeaders(v.Header)
+	return err
+}
+
+func (resp Response) MarshalJSON() ([]byte, error) {
+	type response Response
+	return marshalWithExtra(response(resp), resp.Extra)
+}
+
+type Cookie struct {
+	Domain     string `json:"domain"`
+	Path       string `json:"path"`
+	Expires    any    `json:"expires,omitempty"` // string or null
+	MaxAge     string `json:"maxAge,omitempty"`
+	HostOnly   bool   `json:"hostOnly,omitempty"`
+	HTTPOnly   bool   `json:"httpOnly,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Secure     bool   `json:"secure,omitempty"`
+	Session    bool   `json:"session,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Extensions []any  `json:"extensions,omitempty"`
+	Extra      Extra  `json:"-"`
+}
+
+func (c *Cookie) UnmarshalJSON(data []byte) error {
+	type cookie Cookie
+	var v cookie
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*c = Cookie(v)
+	c.Extra = extra
+	return nil
+}
+
+func (c Cookie) MarshalJSON() ([]byte, error) {
+	type cookie Cookie
+	return marshalWithExtra(cookie(c), c.Extra)
+}
@@ -61,20 +61,22 @@ func (col *Collection) ToCanonical() postman2.Collection {
 }
 
 type Item struct {
-	Name        string           `json:"name,omitempty"`        // Folder,API
-	Description string           `json:"description,omitempty"` // Folder
-	Item        []*Item          `json:"item,omitempty"`        // Folder
-	Event       []postman2.Event `json:"event,omitempty"`       // API
-	Request     Request          `json:"request,omitempty"`     // API
+	Name        string              `json:"name,omitempty"`        // Folder,API
+	Description string              `json:"description,omitempty"` // Folder
+	Item        []*Item             `json:"item,omitempty"`        // Folder
+	Event       []postman2.Event    `json:"event,omitempty"`       // API
+	Request     Request             `json:"request,omitempty"`     // API
+	Response    []postman2.Response `json:"response,omitempty"`    // API
 }
 
 func (thisItem *Item) ToCanonical() *postman2.Item {
 	canRequest := thisItem.Request.ToCanonical()
 	canItem := &postman2.Item{
-		Name:    thisItem.Name,
-		Item:    []*postman2.Item{},
-		Event:   thisItem.Event,
-		Request: &canRequest}
+		Name:     thisItem.Name,
+		Item:     []*postman2.Item{},
+		Event:    thisItem.Event,
+		Request:  &canRequest,
+		Response: thisItem.Response}
 	thisItem.Description = strings.TrimSpace(thisItem.Description)
 	if len(thisItem.Description) > 0 {
 		canItem.Description = &postman2.Description{
@@ -88,17 +90,19 @@ func (thisItem *Item) ToCanonical() *postman2.Item {
 }
 
 type APIItem struct {
-	Name    string           `json:"name,omitempty"`
-	Event   []postman2.Event `json:"event,omitempty"`
-	Request Request          `json:"request,omitempty"`
+	Name     string              `json:"name,omitempty"`
+	Event    []postman2.Event    `json:"event,omitempty"`
+	Request  Request             `json:"request,omitempty"`
+	Response []postman2.Response `json:"response,omitempty"`
 }
 
 func (apiItem *APIItem) ToCanonical() postman2.Item {
 	canReq := apiItem.Request.ToCanonical()
 	return postman2.Item{
-		Name:    apiItem.Name,
-		Event:   apiItem.Event,
-		Request: &canReq}
+		Name:     apiItem.Name,
+		Event:    apiItem.Event,
+		Request:  &canReq,
+		Response: apiItem.Response}
 }
 
 type Request struct {
@@ -116,5 +120,5 @@ func (req *Request) ToCanonical() postman2.Request {
 		Method:      req.Method,
 		Header:      req.Header,
 		Body:        &req.Body,
-		Description: req.Description}
+		Description: postman2.NewDescription(req.Description)}
 }
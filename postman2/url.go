@@ -1,22 +1,79 @@
 package postman2
 
 import (
+	"encoding/json"
+	"maps"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/grokify/mogo/net/urlutil"
 )
 
-// URL is the Postman URL used in the Postman 2.0 Collection Spec.
+// URL is the Postman URL used in the Postman 2.0 Collection Spec. URLs read
+// from a JSON string are written back as strings while they are raw only.
+// Paths read with `{"type":..,"value":..}` segment objects are written back
+// as read while `Path` is unchanged.
 type URL struct {
 	Raw      string            `json:"raw,omitempty"`
 	Protocol string            `json:"protocol,omitempty"`
-	Auth     map[string]string `json:"auth,omitempty"`
-	Host     []string          `json:"host,omitempty"`
-	Path     []string          `json:"path,omitempty"`
+	Auth     map[string]string `json:"auth,omitempty"` // Old, pre 2.1.0
+	Host     StringList        `json:"host,omitempty"`
+	Port     string            `json:"port,omitempty"`
+	Path     StringList        `json:"path,omitempty"`
 	Query    []URLQuery        `json:"query,omitempty"`
+	Hash     string            `json:"hash,omitempty"`
 	Variable []URLVariable     `json:"variable,omitempty"`
+	Extra    Extra             `json:"-"`
+	isString bool
+	pathRaw  json.RawMessage
+}
+
+func (pmURL *URL) UnmarshalJSON(data []byte) error {
+	if jsonIsString(data) {
+		*pmURL = URL{isString: true}
+		return json.Unmarshal(data, &pmURL.Raw)
+	}
+	type postmanURL URL
+	var v postmanURL
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*pmURL = URL(v)
+	pmURL.Extra = extra
+	members := struct {
+		Path json.RawMessage `json:"path"`
+	}{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	if jsonHasObject(members.Path) {
+		pmURL.pathRaw = members.Path
+	}
+	return nil
+}
+
+func (pmURL URL) MarshalJSON() ([]byte, error) {
+	if pmURL.isString && pmURL.IsRawOnly() && len(pmURL.Auth) == 0 &&
+		len(pmURL.Port) == 0 && len(pmURL.Query) == 0 && len(pmURL.Hash) == 0 &&
+		len(pmURL.Variable) == 0 && len(pmURL.Extra) == 0 {
+		return json.Marshal(pmURL.Raw)
+	}
+	type postmanURL URL
+	v := postmanURL(pmURL)
+	extra := pmURL.Extra
+	if len(pmURL.pathRaw) > 0 {
+		var path StringList
+		if err := json.Unmarshal(pmURL.pathRaw, &path); err == nil && slices.Equal(path, pmURL.Path) {
+			v.Path = nil
+			extra = Extra{}
+			maps.Copy(extra, pmURL.Extra)
+			extra["path"] = pmURL.pathRaw
+		}
+	}
+	return marshalWithExtra(v, extra)
 }
 
 // URLParameters is a temp struct to hold parsed parameters.
@@ -34,8 +91,7 @@ func NewURLParameters() URLParameters {
 }
 
 func (pmURL *URL) IsRawOnly() bool {
-	pmURL.Protocol = strings.TrimSpace(pmURL.Protocol)
-	if len(pmURL.Protocol) > 0 ||
+	if len(strings.TrimSpace(pmURL.Protocol)) > 0 ||
 		len(pmURL.Host) > 0 ||
 		len(pmURL.Path) > 0 {
 		return false
@@ -44,25 +100,37 @@ func (pmURL *URL) IsRawOnly() bool {
 }
 
 type URLQuery struct {
-	Key         string `json:"key,omitempty"`
-	Value       string `json:"value,omitempty"`
-	Description string `json:"description,omitempty"`
-	Disabled    bool   `json:"disabled,omitempty"`
+	Key         string       `json:"key,omitempty"`
+	Value       string       `json:"value,omitempty"`
+	Description *Description `json:"description,omitempty"`
+	Disabled    bool         `json:"disabled,omitempty"`
+	Extra       Extra        `json:"-"`
 }
 
-type URLVariable struct {
-	Key         string                 `json:"key,omitempty"`
-	Value       interface{}            `json:"value,omitempty"`
-	Description URLVariableDescription `json:"description,omitempty"`
-	Disabled    bool                   `json:"disabled,omitempty"`
-	ID          string                 `json:"id,omitempty"` // Old, pre 2.0.1
+func (q *URLQuery) UnmarshalJSON(data []byte) error {
+	type urlQuery URLQuery
+	var v urlQuery
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*q = URLQuery(v)
+	q.Extra = extra
+	return nil
 }
 
-type URLVariableDescription struct {
-	Content string `json:"content,omitempty"`
-	Type    string `json:"type,omitempty"`
+func (q URLQuery) MarshalJSON() ([]byte, error) {
+	type urlQuery URLQuery
+	return marshalWithExtra(urlQuery(q), q.Extra)
 }
 
+// URLVariable is a path variable, such as `:id`, which has the same
+// structure as a collection variable.
+type URLVariable = Variable
+
+// URLVariableDescription is the description of a URL variable.
+type URLVariableDescription = Description
+
 func NewURLForGoURL(goURL url.URL) URL {
 	pmURL := URL{Variable: []URLVariable{}}
 	goURL.Scheme = strings.TrimSpace(goURL.Scheme)
@@ -0,0 +1,75 @@
+package postman2
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/grokify/mogo/errors/errorsutil"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// schemaCollection210 is a local copy of the Postman Collection v2.1.0 JSON
+// Schema published at `SchemaURL210` so validation works offline.
+//
+//go:embed schemas/collection_v2.1.0.json
+var schemaCollection210 []byte
+
+var (
+	schema210Once sync.Once
+	schema210     *jsonschema.Schema
+	schema210Err  error
+)
+
+func collectionSchema210() (*jsonschema.Schema, error) {
+	schema210Once.Do(func() {
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaCollection210))
+		if err != nil {
+			schema210Err = err
+			return
+		}
+		c := jsonschema.NewCompiler()
+		if err := c.AddResource(SchemaURL210, doc); err != nil {
+			schema210Err = err
+			return
+		}
+		schema210, schema210Err = c.Compile(SchemaURL210)
+	})
+	return schema210, schema210Err
+}
+
+// ValidateCollectionBytes validates a Postman collection JSON document
+// against the Postman Collection v2.1.0 schema.
+func ValidateCollectionBytes(data []byte) error {
+	sch, err := collectionSchema210()
+	if err != nil {
+		return errorsutil.Wrap(err, "spectrum.postman2.ValidateCollectionBytes << compile schema")
+	}
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return errorsutil.Wrap(err, "spectrum.postman2.ValidateCollectionBytes << jsonschema.UnmarshalJSON")
+	}
+	return sch.Validate(inst)
+}
+
+// ValidateCollectionFile validates a Postman collection file against the
+// Postman Collection v2.1.0 schema.
+func ValidateCollectionFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return ValidateCollectionBytes(data)
+}
+
+// Validate validates the collection as it would be written against the
+// Postman Collection v2.1.0 schema.
+func (col Collection) Validate() error {
+	data, err := json.Marshal(col)
+	if err != nil {
+		return err
+	}
+	return ValidateCollectionBytes(data)
+}
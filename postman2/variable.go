@@ -0,0 +1,50 @@
+package postman2
+
+const (
+	VariableTypeAny     = "any"
+	VariableTypeBoolean = "boolean"
+	VariableTypeNumber  = "number"
+	VariableTypeString  = "string"
+)
+
+// Variable is a collection variable which can be referenced as `{{key}}`.
+type Variable struct {
+	ID          string       `json:"id,omitempty"`
+	Key         string       `json:"key,omitempty"`
+	Value       any          `json:"value,omitempty"`
+	Type        string       `json:"type,omitempty"`
+	Name        string       `json:"name,omitempty"`
+	Description *Description `json:"description,omitempty"`
+	System      bool         `json:"system,omitempty"`
+	Disabled    bool         `json:"disabled,omitempty"`
+	Extra       Extra        `json:"-"`
+}
+
+func (v *Variable) UnmarshalJSON(data []byte) error {
+	type variable Variable
+	var vv variable
+	extra, err := unmarshalWithExtra(data, &vv)
+	if err != nil {
+		return err
+	}
+	*v = Variable(vv)
+	v.Extra = extra
+	return nil
+}
+
+func (v Variable) MarshalJSON() ([]byte, error) {
+	type variable Variable
+	return marshalWithExtra(variable(v), v.Extra)
+}
+
+// UpsertVariable adds a collection variable if one with the same key does
+// not already exist. Existing values are kept so user-set values are not
+// overwritten.
+func (col *Collection) UpsertVariable(v Variable) {
+	for _, try := range col.Variable {
+		if try.Key == v.Key {
+			return
+		}
+	}
+	col.Variable = append(col.Variable, v)
+}